@@ -31,9 +31,18 @@ switch ($Command.ToLower()) {
         & $DockerCompose up
     }
     
+    "migrate-up" {
+        Write-Host "Aplicando migrações..." -ForegroundColor Yellow
+        & $DockerCompose run --rm app ./migrate up
+    }
+    
+    "migrate-status" {
+        & $DockerCompose run --rm app ./migrate status
+    }
+    
     default {
         Write-Host "Event Go - Scripts para Windows" -ForegroundColor Green
-        Write-Host "Comandos: build, run, stop, logs, dev" -ForegroundColor Cyan
+        Write-Host "Comandos: build, run, stop, logs, dev, migrate-up, migrate-status" -ForegroundColor Cyan
         Write-Host "Exemplo: .\build.ps1 build" -ForegroundColor Yellow
     }
 }
\ No newline at end of file
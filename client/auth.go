@@ -0,0 +1,191 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+)
+
+// renewBefore antecipa a renovação para o token não vencer no meio de uma
+// requisição.
+const renewBefore = 30 * time.Second
+
+// ErrMFARequired indica que a conta usa 2FA: o login precisa ser concluído com
+// LoginMFA.
+var ErrMFARequired = errors.New("login exige o código de dois fatores")
+
+// Login autentica com email e senha e guarda o token para as próximas
+// chamadas; antes de ele vencer, o cliente o troca por um novo em
+// /login/refresh. A senha não é guardada: só WithCredentials permite repetir o
+// login quando a sessão não pode mais ser renovada. Em contas com 2FA devolve
+// a resposta (com MFAToken) e ErrMFARequired.
+func (c *Client) Login(ctx context.Context, email, password string) (*AuthResponse, error) {
+	var auth AuthResponse
+	err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   "/login",
+		body:   domain.LoginRequest{Email: email, Password: password},
+	}, &auth)
+	if err != nil {
+		return nil, err
+	}
+
+	if auth.MFARequired {
+		c.forgetCredentials()
+		return &auth, ErrMFARequired
+	}
+
+	c.setToken(auth.Token)
+	return &auth, nil
+}
+
+// LoginMFA conclui o login de uma conta com 2FA. O token é renovado como o de
+// Login; quando a sessão não puder mais ser renovada, é preciso repetir Login e
+// LoginMFA.
+func (c *Client) LoginMFA(ctx context.Context, mfaToken, code string) (*AuthResponse, error) {
+	var auth AuthResponse
+	err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   "/login/2fa",
+		body:   domain.MFALoginRequest{MFAToken: mfaToken, Code: code},
+	}, &auth)
+	if err != nil {
+		return nil, err
+	}
+	c.setToken(auth.Token)
+	return &auth, nil
+}
+
+// Refresh troca o token atual, ainda válido, por um novo sem enviar a senha.
+func (c *Client) Refresh(ctx context.Context) error {
+	return c.refresh(ctx, c.Token())
+}
+
+// refresh, como relogin, só renova se o token ainda for stale.
+func (c *Client) refresh(ctx context.Context, stale string) error {
+	c.reloginMu.Lock()
+	defer c.reloginMu.Unlock()
+
+	if stale == "" {
+		return errors.New("sem token para renovar")
+	}
+	if c.Token() != stale {
+		return nil
+	}
+
+	resp, err := c.send(ctx, request{method: http.MethodPost, path: "/login/refresh", auth: true}, nil, stale)
+	if err != nil {
+		return err
+	}
+	if apiErr := readError(resp); apiErr != nil {
+		return apiErr
+	}
+	defer resp.Body.Close()
+
+	var auth AuthResponse
+	if err := json.NewDecoder(resp.Body).Decode(&auth); err != nil {
+		return fmt.Errorf("decodificar resposta: %w", err)
+	}
+	c.setToken(auth.Token)
+	return nil
+}
+
+// Relogin repete o login com as credenciais de WithCredentials para obter um
+// token novo.
+func (c *Client) Relogin(ctx context.Context) error {
+	return c.relogin(ctx, c.Token())
+}
+
+// relogin só faz o login se o token ainda for stale: quando várias goroutines
+// encontram o mesmo token vencido, apenas a primeira repete o login.
+func (c *Client) relogin(ctx context.Context, stale string) error {
+	c.reloginMu.Lock()
+	defer c.reloginMu.Unlock()
+
+	c.mu.Lock()
+	creds := c.credentials
+	current := c.token
+	c.mu.Unlock()
+	if creds == nil {
+		return errors.New("sem credenciais para repetir o login")
+	}
+	if current != stale {
+		return nil
+	}
+
+	_, err := c.Login(ctx, creds.email, creds.password)
+	return err
+}
+
+// forgetCredentials descarta a senha guardada quando ela não serve mais para
+// repetir o login.
+func (c *Client) forgetCredentials() {
+	c.mu.Lock()
+	c.credentials = nil
+	c.mu.Unlock()
+}
+
+// Token devolve o token JWT atual, ou "" antes do login.
+func (c *Client) Token() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.token
+}
+
+func (c *Client) setToken(token string) {
+	// O cliente não conhece a chave do servidor: lê exp sem validar a
+	// assinatura, apenas para saber quando renovar o token.
+	var expiry time.Time
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, claims); err == nil {
+		if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+			expiry = exp.Time
+		}
+	}
+
+	c.mu.Lock()
+	c.token = token
+	c.expiry = expiry
+	c.mu.Unlock()
+}
+
+func (c *Client) canRelogin() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return !c.staticAuth && c.credentials != nil
+}
+
+// ensureToken renova o token quando ele está para vencer e faz o login quando
+// não há token ou a renovação falhou, desde que haja credenciais. Com chave de
+// API ou WithToken não há nada a fazer.
+func (c *Client) ensureToken(ctx context.Context) error {
+	c.mu.Lock()
+	static := c.staticAuth
+	token := c.token
+	expiry := c.expiry
+	c.mu.Unlock()
+	if static {
+		return nil
+	}
+	if token != "" && (expiry.IsZero() || time.Until(expiry) > renewBefore) {
+		return nil
+	}
+
+	if token != "" && time.Now().Before(expiry) {
+		// Sem credenciais, uma renovação que falhou não impede a chamada: o
+		// token ainda vale por alguns segundos.
+		if err := c.refresh(ctx, token); err == nil || !c.canRelogin() {
+			return nil
+		}
+	}
+	if !c.canRelogin() {
+		return nil
+	}
+	return c.relogin(ctx, token)
+}
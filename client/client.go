@@ -0,0 +1,311 @@
+// Package client é o SDK Go da API: login repetido quando o token vence,
+// envio de eventos em lotes com novas tentativas e consulta de estatísticas.
+//
+//	c := client.New("https://eventos.exemplo.com", client.WithAPIKey(os.Getenv("EVENTS_API_KEY")))
+//	result, err := c.SendEvents(ctx, events)
+//
+// Os tipos de requisição e resposta são os mesmos do servidor (pacote domain),
+// reexportados aqui para que possam ser usados fora deste módulo.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+)
+
+type (
+	EmailEvent     = domain.EmailEvent
+	EventsResponse = domain.EventsResponse
+	ProcessedEvent = domain.ProcessedEvent
+	AuthResponse   = domain.AuthResponse
+	User           = domain.User
+	StatsResponse  = domain.StatsResponse
+	DailyStats     = domain.DailyStats
+	EventStats     = domain.EventStats
+	Problem        = domain.Problem
+	FieldError     = domain.FieldError
+)
+
+const (
+	// DefaultBatchSize é o número de eventos por requisição em SendEvents.
+	DefaultBatchSize = 500
+	// DefaultTimeout limita cada tentativa quando o http.Client não é informado.
+	DefaultTimeout = 30 * time.Second
+
+	userAgent = "goapp-client/1"
+)
+
+// Client é seguro para uso concorrente.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	apiKey     string
+	batchSize  int
+	retry      RetryPolicy
+	language   string
+
+	// staticAuth indica WithAPIKey ou WithToken: o cliente nunca renova o
+	// token nem repete o login, e não guarda senhas.
+	staticAuth bool
+
+	// mu protege token, expiry e credentials; reloginMu evita logins e
+	// renovações simultâneos quando várias goroutines encontram o token
+	// vencido ao mesmo tempo.
+	mu          sync.Mutex
+	reloginMu   sync.Mutex
+	token       string
+	expiry      time.Time
+	credentials *credentials
+}
+
+type credentials struct {
+	email    string
+	password string
+}
+
+type Option func(*Client)
+
+// WithHTTPClient substitui o http.Client padrão (timeout de DefaultTimeout).
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithAPIKey autentica com uma chave de API em vez de token JWT. A chave
+// precisa dos escopos events:write e stats:read conforme as chamadas usadas.
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.apiKey = key
+		c.staticAuth = true
+	}
+}
+
+// WithToken usa um token JWT já emitido. Quando ele vencer, as chamadas
+// devolvem 401; o cliente não repete o login.
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.setToken(token)
+		c.staticAuth = true
+	}
+}
+
+// WithCredentials guarda email e senha em memória para fazer login sob demanda
+// e repeti-lo quando a sessão não pode mais ser renovada ou o token é recusado.
+// É a única forma de o cliente guardar a senha, e é ignorado com WithAPIKey ou
+// WithToken. Contas com 2FA precisam usar Login e LoginMFA.
+func WithCredentials(email, password string) Option {
+	return func(c *Client) {
+		c.credentials = &credentials{email: email, password: password}
+	}
+}
+
+// WithBatchSize define quantos eventos vão em cada requisição de SendEvents.
+func WithBatchSize(n int) Option {
+	return func(c *Client) {
+		if n > 0 {
+			c.batchSize = n
+		}
+	}
+}
+
+// WithRetry substitui a política de novas tentativas.
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// WithLanguage pede as mensagens de erro no idioma informado (Accept-Language).
+func WithLanguage(lang string) Option {
+	return func(c *Client) {
+		c.language = lang
+	}
+}
+
+// New cria um cliente para a API em baseURL (ex.: "http://localhost:8080").
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: DefaultTimeout},
+		batchSize:  DefaultBatchSize,
+		retry:      DefaultRetryPolicy(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.staticAuth {
+		c.credentials = nil
+	}
+	return c
+}
+
+// Error é a resposta de erro da API (RFC 7807). Use Problem.Code para decidir
+// o que fazer; Problem.Detail é texto para pessoas.
+type Error struct {
+	StatusCode int
+	Problem    Problem
+	// RetryAfter vem do header Retry-After, quando presente.
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	if e.Problem.Detail != "" {
+		return fmt.Sprintf("api: %d %s: %s", e.StatusCode, e.Problem.Code, e.Problem.Detail)
+	}
+	return fmt.Sprintf("api: %d %s", e.StatusCode, e.Problem.Code)
+}
+
+// request descreve uma chamada à API. Com auth, o token ou a chave de API vão
+// nos headers; com retry, falhas transitórias são repetidas conforme a política.
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	body   interface{}
+	auth   bool
+	retry  bool
+}
+
+func (c *Client) do(ctx context.Context, req request, out interface{}) error {
+	var body []byte
+	if req.body != nil {
+		var err error
+		if body, err = json.Marshal(req.body); err != nil {
+			return fmt.Errorf("codificar requisição: %w", err)
+		}
+	}
+
+	reloggedIn := false
+	for attempt := 1; ; attempt++ {
+		if req.auth {
+			if err := c.ensureToken(ctx); err != nil {
+				return err
+			}
+		}
+
+		token := c.Token()
+		resp, err := c.send(ctx, req, body, token)
+		if err != nil {
+			if ctx.Err() != nil || !req.retry || attempt >= c.retry.MaxAttempts {
+				return err
+			}
+			if err := c.retry.wait(ctx, attempt, 0); err != nil {
+				return err
+			}
+			continue
+		}
+
+		apiErr := readError(resp)
+		if apiErr == nil {
+			defer resp.Body.Close()
+			if out == nil {
+				return nil
+			}
+			if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+				return fmt.Errorf("decodificar resposta: %w", err)
+			}
+			return nil
+		}
+
+		// Um 401 com token vencido ou revogado é tentado mais uma vez, depois
+		// de um novo login, sem contar como tentativa.
+		if apiErr.StatusCode == http.StatusUnauthorized && req.auth && c.canRelogin() && !reloggedIn {
+			reloggedIn = true
+			attempt--
+			if err := c.relogin(ctx, token); err != nil {
+				return err
+			}
+			continue
+		}
+		if !req.retry || !retryable(apiErr.StatusCode) || attempt >= c.retry.MaxAttempts {
+			return apiErr
+		}
+		if err := c.retry.wait(ctx, attempt, apiErr.RetryAfter); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, req request, body []byte, token string) (*http.Response, error) {
+	u := c.baseURL + req.path
+	if len(req.query) > 0 {
+		u += "?" + req.query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, u, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("User-Agent", userAgent)
+	if c.language != "" {
+		httpReq.Header.Set("Accept-Language", c.language)
+	}
+	if req.auth {
+		if c.apiKey != "" {
+			httpReq.Header.Set("X-API-Key", c.apiKey)
+		} else if token != "" {
+			httpReq.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+	return c.httpClient.Do(httpReq)
+}
+
+// readError devolve nil para respostas 2xx; nos demais casos consome e fecha o corpo.
+func readError(resp *http.Response) *Error {
+	if resp.StatusCode < 300 {
+		return nil
+	}
+	defer resp.Body.Close()
+
+	apiErr := &Error{StatusCode: resp.StatusCode}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		apiErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&apiErr.Problem); err != nil || apiErr.Problem.Status == 0 {
+		apiErr.Problem.Status = resp.StatusCode
+		apiErr.Problem.Title = http.StatusText(resp.StatusCode)
+	}
+	return apiErr
+}
+
+// envelope é o formato de domain.Response, com data mantido cru para ser
+// decodificado no tipo esperado por cada chamada.
+type envelope struct {
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+func (c *Client) getData(ctx context.Context, req request, out interface{}) error {
+	var env envelope
+	if err := c.do(ctx, req, &env); err != nil {
+		return err
+	}
+	if len(env.Data) == 0 {
+		return errors.New("resposta sem data")
+	}
+	if err := json.Unmarshal(env.Data, out); err != nil {
+		return fmt.Errorf("decodificar resposta: %w", err)
+	}
+	return nil
+}
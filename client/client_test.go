@@ -0,0 +1,313 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/nathaliaoliveira/goapp/client"
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/handler"
+	"github.com/nathaliaoliveira/goapp/internal/repository"
+	"github.com/nathaliaoliveira/goapp/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var jwtSecret = []byte("segredo-de-teste")
+
+const (
+	testEmail    = "joao@exemplo.com"
+	testPassword = "Senha-forte-123"
+	testAPIKey   = "gk_teste"
+)
+
+// Os serviços falsos embutem a interface: métodos não usados nos testes
+// causariam panic se chamados.
+type fakeUserService struct {
+	service.UserService
+	ttl       time.Duration
+	logins    int32
+	refreshes int32
+	// revoke faz CheckSession recusar a próxima sessão, como se o token
+	// tivesse sido revogado.
+	revoke int32
+}
+
+func (s *fakeUserService) Login(ctx context.Context, email, password, clientIP string) (*domain.AuthResponse, error) {
+	atomic.AddInt32(&s.logins, 1)
+	if email != testEmail || password != testPassword {
+		return nil, &service.AuthenticationError{Key: "auth.invalid_credentials"}
+	}
+	return s.session()
+}
+
+func (s *fakeUserService) RefreshSession(ctx context.Context, userID int, authTime time.Time) (*domain.AuthResponse, error) {
+	atomic.AddInt32(&s.refreshes, 1)
+	return s.session()
+}
+
+func (s *fakeUserService) session() (*domain.AuthResponse, error) {
+	user := &domain.User{ID: 7, OrgID: 3, Email: testEmail, Role: domain.RoleUser}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": user.ID,
+		"org_id":  user.OrgID,
+		"email":   user.Email,
+		"role":    user.Role,
+		"exp":     time.Now().Add(s.ttl).Unix(),
+		"jti":     fmt.Sprint(time.Now().UnixNano()), // tokens distintos a cada chamada
+	}).SignedString(jwtSecret)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.AuthResponse{Token: token, User: user}, nil
+}
+
+func (s *fakeUserService) CheckSession(ctx context.Context, userID int) error {
+	if atomic.CompareAndSwapInt32(&s.revoke, 1, 0) {
+		return &service.AuthenticationError{Key: "auth.token_invalid"}
+	}
+	return nil
+}
+
+type fakeEventService struct {
+	mu       sync.Mutex
+	batches  [][]domain.EmailEvent
+	failures []error
+	calls    int
+}
+
+func (s *fakeEventService) ProcessEvents(ctx context.Context, orgID int, events []domain.EmailEvent) (*domain.EventsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if len(s.failures) > 0 {
+		err := s.failures[0]
+		s.failures = s.failures[1:]
+		return nil, err
+	}
+	s.batches = append(s.batches, events)
+
+	resp := &domain.EventsResponse{Processed: len(events)}
+	for i, event := range events {
+		resp.Events = append(resp.Events, domain.ProcessedEvent{ID: fmt.Sprint(i), Email: event.Email, Status: "processed"})
+	}
+	return resp, nil
+}
+
+func (s *fakeEventService) GetDailyStats(ctx context.Context, orgID int, startDate, endDate, site string) (*domain.StatsResponse, error) {
+	return &domain.StatsResponse{
+		Period:     map[string]string{"start_date": startDate, "end_date": endDate},
+		SiteFilter: site,
+		TotalDays:  1,
+		Stats:      []domain.DailyStats{{Date: startDate, Site: site, TotalEvents: 4}},
+	}, nil
+}
+
+type fakeAPIKeyService struct {
+	service.APIKeyService
+}
+
+func (fakeAPIKeyService) Authenticate(ctx context.Context, rawKey string) (*domain.APIKey, error) {
+	if rawKey != testAPIKey {
+		return nil, &service.AuthenticationError{Key: "api_key.invalid"}
+	}
+	return &domain.APIKey{UserID: 7, OrgID: 3, Prefix: "gk_", Scopes: []string{domain.ScopeEventsWrite}}, nil
+}
+
+type nopAuditService struct {
+	service.AuditService
+}
+
+func (nopAuditService) Record(ctx context.Context, entry domain.AuditEntry) {}
+
+type fixture struct {
+	users  *fakeUserService
+	events *fakeEventService
+	url    string
+}
+
+func newFixture(t *testing.T) *fixture {
+	f := &fixture{
+		users:  &fakeUserService{ttl: time.Hour},
+		events: &fakeEventService{},
+	}
+	srv := httptest.NewServer(handler.NewRouter(handler.RouterConfig{
+		JWTSecret:     jwtSecret,
+		UserService:   f.users,
+		EventService:  f.events,
+		APIKeyService: fakeAPIKeyService{},
+		AuditService:  nopAuditService{},
+	}))
+	t.Cleanup(srv.Close)
+	f.url = srv.URL
+	return f
+}
+
+var fastRetry = client.WithRetry(client.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond})
+
+func makeEvents(n int) []client.EmailEvent {
+	events := make([]client.EmailEvent, n)
+	for i := range events {
+		events[i] = client.EmailEvent{Type: "open", Email: fmt.Sprintf("u%d@exemplo.com", i), Site: "exemplo.com", Timestamp: "2026-01-02T10:00:00Z"}
+	}
+	return events
+}
+
+func TestLogin_StoresToken(t *testing.T) {
+	f := newFixture(t)
+	c := client.New(f.url)
+
+	auth, err := c.Login(context.Background(), testEmail, testPassword)
+	require.NoError(t, err)
+	assert.Equal(t, testEmail, auth.User.Email)
+	assert.Equal(t, auth.Token, c.Token())
+
+	_, err = c.Login(context.Background(), testEmail, "errada")
+	var apiErr *client.Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, 401, apiErr.StatusCode)
+	assert.Equal(t, domain.ProblemAuthFailed, apiErr.Problem.Code)
+}
+
+func TestSendEvents_ChunksIntoBatches(t *testing.T) {
+	f := newFixture(t)
+	c := client.New(f.url, client.WithCredentials(testEmail, testPassword), client.WithBatchSize(4))
+
+	result, err := c.SendEvents(context.Background(), makeEvents(10))
+	require.NoError(t, err)
+
+	assert.Equal(t, 10, result.Processed)
+	assert.Len(t, result.Events, 10)
+	require.Len(t, f.events.batches, 3)
+	assert.Len(t, f.events.batches[0], 4)
+	assert.Len(t, f.events.batches[2], 2)
+	assert.Equal(t, "u8@exemplo.com", f.events.batches[2][0].Email)
+	assert.EqualValues(t, 1, f.users.logins, "login sob demanda apenas uma vez")
+}
+
+func TestSendEvents_RetriesTransientFailures(t *testing.T) {
+	f := newFixture(t)
+	f.events.failures = []error{
+		fmt.Errorf("gravar evento: %w", context.DeadlineExceeded),
+		&service.TooManyAttemptsError{},
+	}
+	c := client.New(f.url, client.WithAPIKey(testAPIKey), fastRetry)
+
+	result, err := c.SendEvents(context.Background(), makeEvents(2))
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, result.Processed)
+	assert.Equal(t, 3, f.events.calls)
+}
+
+func TestSendEvents_StopsOnPermanentErrors(t *testing.T) {
+	f := newFixture(t)
+	f.events.failures = []error{&repository.SiteNotFoundError{}}
+	c := client.New(f.url, client.WithAPIKey(testAPIKey), client.WithBatchSize(1), fastRetry)
+
+	result, err := c.SendEvents(context.Background(), makeEvents(2))
+
+	var batchErr *client.BatchError
+	require.ErrorAs(t, err, &batchErr)
+	assert.Equal(t, 0, batchErr.Offset)
+	var apiErr *client.Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, domain.ProblemSiteNotFound, apiErr.Problem.Code)
+	assert.Equal(t, 1, f.events.calls)
+	assert.Equal(t, 0, result.Processed)
+}
+
+func TestSendEvents_GivesUpAfterMaxAttempts(t *testing.T) {
+	f := newFixture(t)
+	for i := 0; i < 5; i++ {
+		f.events.failures = append(f.events.failures, context.DeadlineExceeded)
+	}
+	c := client.New(f.url, client.WithAPIKey(testAPIKey), client.WithBatchSize(1), fastRetry)
+
+	_, err := c.SendEvents(context.Background(), makeEvents(1))
+
+	var apiErr *client.Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, 504, apiErr.StatusCode)
+	assert.Equal(t, 3, f.events.calls)
+}
+
+func TestRefreshBeforeExpiry(t *testing.T) {
+	f := newFixture(t)
+	f.users.ttl = 10 * time.Second // menos que a antecedência da renovação
+	c := client.New(f.url)
+
+	auth, err := c.Login(context.Background(), testEmail, testPassword)
+	require.NoError(t, err)
+	_, err = c.DailyStats(context.Background(), client.StatsQuery{StartDate: "2026-01-02"})
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 1, f.users.logins, "a renovação não repete o login")
+	assert.EqualValues(t, 1, f.users.refreshes)
+	assert.NotEqual(t, auth.Token, c.Token())
+}
+
+func TestLogin_DoesNotKeepPassword(t *testing.T) {
+	f := newFixture(t)
+	f.users.revoke = 1
+	c := client.New(f.url)
+
+	_, err := c.Login(context.Background(), testEmail, testPassword)
+	require.NoError(t, err)
+	assert.Error(t, c.Relogin(context.Background()), "só WithCredentials guarda a senha")
+
+	_, err = c.DailyStats(context.Background(), client.StatsQuery{StartDate: "2026-01-02"})
+	var apiErr *client.Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, 401, apiErr.StatusCode)
+	assert.EqualValues(t, 1, f.users.logins)
+}
+
+func TestReloginOnUnauthorized(t *testing.T) {
+	f := newFixture(t)
+	f.users.revoke = 1
+	c := client.New(f.url, client.WithCredentials(testEmail, testPassword))
+
+	stats, err := c.DailyStats(context.Background(), client.StatsQuery{StartDate: "2026-01-02", EndDate: "2026-01-03", Site: "exemplo.com"})
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 2, f.users.logins)
+	assert.Equal(t, "exemplo.com", stats.SiteFilter)
+	assert.Equal(t, "2026-01-03", stats.Period["end_date"])
+	require.Len(t, stats.Stats, 1)
+	assert.Equal(t, 4, stats.Stats[0].TotalEvents)
+}
+
+func TestStaticToken_KeepsNoPassword(t *testing.T) {
+	f := newFixture(t)
+	c := client.New(f.url, client.WithToken("token-revogado"), client.WithCredentials(testEmail, testPassword))
+
+	_, err := c.DailyStats(context.Background(), client.StatsQuery{StartDate: "2026-01-02"})
+	var apiErr *client.Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, 401, apiErr.StatusCode)
+
+	_, err = c.Login(context.Background(), testEmail, testPassword)
+	require.NoError(t, err)
+	assert.Error(t, c.Relogin(context.Background()), "Login também não guarda a senha")
+	assert.EqualValues(t, 1, f.users.logins)
+}
+
+func TestDailyStats_APIKeyWithoutScope(t *testing.T) {
+	f := newFixture(t)
+	c := client.New(f.url, client.WithAPIKey(testAPIKey), client.WithLanguage("en"))
+
+	_, err := c.DailyStats(context.Background(), client.StatsQuery{})
+
+	var apiErr *client.Error
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, 403, apiErr.StatusCode)
+	assert.Equal(t, domain.ProblemInsufficientScope, apiErr.Problem.Code)
+}
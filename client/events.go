@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+)
+
+// BatchError indica o lote que falhou em SendEvents. Os lotes anteriores já
+// foram aceitos e estão somados na resposta devolvida junto com o erro.
+type BatchError struct {
+	// Offset é o índice, em events, do primeiro evento do lote que falhou.
+	Offset int
+	Err    error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("lote a partir do evento %d: %v", e.Offset, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// SendEvents envia os eventos em lotes de até WithBatchSize, em ordem, com novas
+// tentativas em falhas transitórias. A resposta soma os resultados de todos os
+// lotes. Reenviar é seguro: eventos já gravados voltam como duplicados.
+func (c *Client) SendEvents(ctx context.Context, events []EmailEvent) (*EventsResponse, error) {
+	total := &EventsResponse{Events: make([]ProcessedEvent, 0, len(events))}
+
+	for offset := 0; offset < len(events); offset += c.batchSize {
+		end := offset + c.batchSize
+		if end > len(events) {
+			end = len(events)
+		}
+
+		var batch EventsResponse
+		err := c.do(ctx, request{
+			method: http.MethodPost,
+			path:   "/api/events",
+			body:   domain.EventsRequest{Events: events[offset:end]},
+			auth:   true,
+			retry:  true,
+		}, &batch)
+		if err != nil {
+			return total, &BatchError{Offset: offset, Err: err}
+		}
+
+		total.Processed += batch.Processed
+		total.Duplicates += batch.Duplicates
+		total.Errors += batch.Errors
+		total.Events = append(total.Events, batch.Events...)
+	}
+	return total, nil
+}
+
+// StatsQuery filtra DailyStats. Datas no formato AAAA-MM-DD; campos vazios
+// usam o padrão do servidor.
+type StatsQuery struct {
+	StartDate string
+	EndDate   string
+	Site      string
+}
+
+// DailyStats consulta as estatísticas diárias de eventos.
+func (c *Client) DailyStats(ctx context.Context, q StatsQuery) (*StatsResponse, error) {
+	query := url.Values{}
+	for name, value := range map[string]string{"start_date": q.StartDate, "end_date": q.EndDate, "site": q.Site} {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+
+	var stats StatsResponse
+	err := c.getData(ctx, request{
+		method: http.MethodGet,
+		path:   "/api/stats/daily",
+		query:  query,
+		auth:   true,
+		retry:  true,
+	}, &stats)
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy controla as novas tentativas de envio de eventos e de consultas.
+// O intervalo dobra a cada tentativa, com jitter, até MaxBackoff; um
+// Retry-After maior enviado pelo servidor tem precedência. Login não é repetido.
+type RetryPolicy struct {
+	// MaxAttempts conta a primeira tentativa; 1 desativa as repetições.
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: 200 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+	}
+}
+
+// retryable indica falhas transitórias. Reenviar um lote de eventos é seguro:
+// eventos já gravados voltam como duplicados.
+func retryable(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.InitialBackoff
+	for i := 1; i < attempt && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+	// Jitter entre metade e o intervalo inteiro, para que clientes que falharam
+	// juntos não voltem todos no mesmo instante.
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+func (p RetryPolicy) wait(ctx context.Context, attempt int, retryAfter time.Duration) error {
+	d := p.backoff(attempt)
+	if retryAfter > d {
+		d = retryAfter
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+
+	"github.com/nathaliaoliveira/goapp/internal/database"
+	"github.com/nathaliaoliveira/goapp/internal/logging"
+)
+
+const usage = `Uso: migrate [-dir DIR] <comando> [argumentos]
+
+Comandos:
+  up             aplica todas as migrações pendentes
+  down [N]       desfaz as últimas N migrações (padrão 1)
+  status         lista as migrações e se já foram aplicadas
+  create NOME    cria NNNN_NOME.up.sql e NNNN_NOME.down.sql em -dir
+
+As conexões usam as mesmas variáveis DB_* do servidor.
+`
+
+func main() {
+	logging.Setup(logging.NewConfig())
+
+	dir := flag.String("dir", "internal/database/migrations", "diretório dos arquivos de migração (usado por create)")
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if args[0] == "create" {
+		if len(args) < 2 {
+			slog.Error("Informe o nome da migração")
+			os.Exit(2)
+		}
+		upPath, downPath, err := database.CreateMigration(*dir, args[1])
+		if err != nil {
+			fatal("Erro ao criar migração", err)
+		}
+		fmt.Println(upPath)
+		fmt.Println(downPath)
+		return
+	}
+
+	migrations, err := database.EmbeddedMigrations()
+	if err != nil {
+		fatal("Erro ao carregar migrações", err)
+	}
+
+	db, err := database.NewDatabaseConfig().Connect()
+	if err != nil {
+		fatal("Erro ao conectar ao banco", err)
+	}
+	defer db.Close()
+
+	migrator := database.NewMigrator(db, migrations)
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up()
+		for _, m := range applied {
+			slog.Info("Migração aplicada", "migration", m.String())
+		}
+		if err != nil {
+			fatal("Erro ao aplicar migrações", err)
+		}
+		if len(applied) == 0 {
+			slog.Info("Nenhuma migração pendente")
+		}
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				slog.Error("Número de migrações inválido", "value", args[1])
+				os.Exit(2)
+			}
+		}
+		reverted, err := migrator.Down(steps)
+		for _, m := range reverted {
+			slog.Info("Migração desfeita", "migration", m.String())
+		}
+		if err != nil {
+			fatal("Erro ao desfazer migrações", err)
+		}
+
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			fatal("Erro ao consultar migrações", err)
+		}
+		for _, s := range statuses {
+			state := "pendente"
+			switch {
+			case s.Missing:
+				state = "aplicada, arquivo ausente"
+			case s.ChecksumMismatch:
+				state = "aplicada, arquivo alterado"
+			case s.Applied:
+				state = "aplicada em " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%s  %s\n", s.Migration, state)
+		}
+
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
@@ -1,85 +1,277 @@
 package main
 
 import (
+    "context"
     "crypto/rand"
-    "log"
-    "net/http"
+    "log/slog"
+    "net"
     "os"
+    "os/signal"
+    "strings"
+    "syscall"
     "time"
 
-    "github.com/gorilla/mux"
     "github.com/nathaliaoliveira/goapp/internal/database"
+    "github.com/nathaliaoliveira/goapp/internal/env"
+    "github.com/nathaliaoliveira/goapp/internal/graphqlapi"
+    "github.com/nathaliaoliveira/goapp/internal/grpcserver"
     "github.com/nathaliaoliveira/goapp/internal/handler"
+    "github.com/nathaliaoliveira/goapp/internal/logging"
+    "github.com/nathaliaoliveira/goapp/internal/mailer"
+    "github.com/nathaliaoliveira/goapp/internal/metrics"
+    "github.com/nathaliaoliveira/goapp/internal/oidc"
     "github.com/nathaliaoliveira/goapp/internal/repository"
     "github.com/nathaliaoliveira/goapp/internal/seeds"
+    "github.com/nathaliaoliveira/goapp/internal/server"
     "github.com/nathaliaoliveira/goapp/internal/service"
+    "github.com/nathaliaoliveira/goapp/internal/tracing"
+    "github.com/prometheus/client_golang/prometheus"
 )
 
 func main() {
     startTime := time.Now()
+    logging.Setup(logging.NewConfig())
+
+    shutdownTracing, err := tracing.Setup(context.Background(), tracing.NewConfig())
+    if err != nil {
+        fatal("Erro ao configurar tracing", err)
+    }
     
     jwtSecret := generateJWTSecret()
-    log.Printf("JWT Secret gerado: %x", jwtSecret)
 
     dbConfig := database.NewDatabaseConfig()
     db, err := dbConfig.Connect()
     if err != nil {
-        log.Fatal("❌ Erro ao conectar ao banco:", err)
+        fatal("Erro ao conectar ao banco", err)
     }
-    defer db.Close()
 
-    if err := database.RunMigrations(db); err != nil {
-        log.Fatal("❌ Erro ao executar migrações:", err)
+    if env.Bool("DB_AUTO_MIGRATE", true) {
+        if err := database.RunMigrations(db); err != nil {
+            fatal("Erro ao executar migrações", err)
+        }
     }
     
-    if err := seeds.RunSeeds(db); err != nil {
-        log.Printf("⚠️ Aviso: Erro ao executar seeds: %v", err)
+    passwordPolicy := newPasswordPolicy()
+    if err := seeds.RunSeeds(db, passwordPolicy); err != nil {
+        slog.Warn("Erro ao executar seeds", "error", err)
     }
 
-    userRepo := repository.NewUserRepository(db)
-    eventRepo := repository.NewEventRepository(db)
+    if err := metrics.Register(prometheus.DefaultRegisterer, db); err != nil {
+        fatal("Erro ao registrar métricas", err)
+    }
+    queryDB := tracing.InstrumentDB(metrics.InstrumentDB(db))
 
-    userService := service.NewUserService(userRepo, jwtSecret)
-    eventService := service.NewEventService(eventRepo)
-    healthService := service.NewHealthService(eventRepo, db, startTime)
+    userRepo := repository.NewUserRepository(queryDB)
+    eventRepo := repository.NewEventRepository(queryDB)
+    apiKeyRepo := repository.NewAPIKeyRepository(queryDB)
+    senderRepo := repository.NewWebhookSenderRepository(queryDB)
+    tokenRepo := repository.NewTokenRepository(queryDB)
+    twoFactorRepo := repository.NewTwoFactorRepository(queryDB)
+    settingsRepo := repository.NewSettingsRepository(queryDB)
+    identityRepo := repository.NewIdentityRepository(queryDB)
+    orgRepo := repository.NewOrganizationRepository(queryDB)
+    siteRepo := repository.NewSiteRepository(queryDB)
+    auditRepo := repository.NewAuditRepository(queryDB)
+    analyticsRepo := repository.NewAnalyticsRepository(queryDB)
 
-    homeHandler := handler.NewHomeHandler()
-    userHandler := handler.NewUserHandler(userService)
-    eventHandler := handler.NewEventHandler(eventService)
-    healthHandler := handler.NewHealthHandler(healthService)
+    loginGuardConfig := service.DefaultLoginGuardConfig()
+    loginGuard := service.NewLoginGuard(repository.NewMemoryLoginAttemptStore(loginGuardConfig.Account.LockoutDuration), loginGuardConfig)
+    twoFactorService := service.NewTwoFactorService(userRepo, twoFactorRepo, settingsRepo, env.String("TOTP_ISSUER", service.DefaultTOTPIssuer))
+    userService := service.NewUserService(userRepo, jwtSecret,
+        service.WithLoginGuard(loginGuard),
+        service.WithPasswordPolicy(passwordPolicy),
+        service.WithTwoFactor(twoFactorService))
+    eventService := service.NewEventService(eventRepo, siteRepo)
+    orgService := service.NewOrganizationService(orgRepo, siteRepo)
+    auditService := service.NewAuditService(auditRepo)
+    analyticsService := service.NewAnalyticsService(analyticsRepo)
+    migrations, err := database.EmbeddedMigrations()
+    if err != nil {
+        fatal("Erro ao carregar migrações", err)
+    }
+    healthService := service.NewHealthService(eventRepo, db, startTime, env.Duration("HEALTH_CHECK_TIMEOUT", service.DefaultHealthCheckTimeout),
+        service.DatabaseCheck(db),
+        service.NewHealthCheck("migrations", database.NewMigrator(db, migrations).CheckPending))
+    apiKeyService := service.NewAPIKeyService(apiKeyRepo)
+    // Os emails de conta saem depois da resposta; registrá-los em srv.Go faz o
+    // desligamento esperar por eles. srv só é criado adiante, mas nenhum job
+    // roda antes de o servidor aceitar requisições.
+    var srv *server.Server
+    accountService := service.NewAccountService(userRepo, tokenRepo, newMailer(), passwordPolicy, env.String("APP_BASE_URL", "http://localhost:8080"),
+        service.WithBackground(func(job func()) {
+            srv.Go(func(context.Context) { job() })
+        }))
+    signatureService := service.NewSignatureService(senderRepo, repository.NewMemoryNonceStore(), env.Duration("SIGNATURE_TOLERANCE", service.DefaultSignatureTolerance))
 
-    r := mux.NewRouter()
-    
-    r.HandleFunc("/", homeHandler.Home).Methods("GET")
-    r.HandleFunc("/health", healthHandler.GetHealth).Methods("GET")
-    r.HandleFunc("/login", userHandler.Login).Methods("POST")
-    r.HandleFunc("/register", userHandler.Register).Methods("POST")
-    
-    r.HandleFunc("/users", handler.AuthMiddleware(jwtSecret)(userHandler.GetUsers)).Methods("GET")
-    r.HandleFunc("/users", handler.AuthMiddleware(jwtSecret)(userHandler.CreateUser)).Methods("POST")
-    r.HandleFunc("/profile", handler.AuthMiddleware(jwtSecret)(userHandler.GetProfile)).Methods("GET")
-    
-    r.HandleFunc("/api/events", handler.AuthMiddleware(jwtSecret)(eventHandler.CreateEvents)).Methods("POST")
-    
-    r.HandleFunc("/api/stats/daily", handler.AuthMiddleware(jwtSecret)(eventHandler.GetDailyStats)).Methods("GET")
+    var ssoService service.SSOService
+    if issuer := env.String("OIDC_ISSUER_URL", ""); issuer != "" {
+        provider := oidc.NewProvider(oidc.Config{
+            IssuerURL:    issuer,
+            ClientID:     env.String("OIDC_CLIENT_ID", ""),
+            ClientSecret: env.String("OIDC_CLIENT_SECRET", ""),
+            RedirectURL:  env.String("OIDC_REDIRECT_URL", env.String("APP_BASE_URL", "http://localhost:8080")+"/auth/oidc/callback"),
+        }, nil)
+        ssoService = service.NewSSOService(provider, repository.NewMemorySSOStateStore(), identityRepo, userRepo, userService, env.Int("OIDC_ORG_ID", 1))
+        slog.Info("Login SSO habilitado", "issuer", issuer)
+    }
+
+    graphqlHandler, err := graphqlapi.NewHandler(graphqlapi.Services{
+        AnalyticsService:    analyticsService,
+        OrganizationService: orgService,
+    }, graphqlapi.Limits{
+        MaxDepth: env.Int("GRAPHQL_MAX_DEPTH", graphqlapi.DefaultMaxDepth),
+        MaxCost:  env.Int("GRAPHQL_MAX_COST", graphqlapi.DefaultMaxCost),
+    })
+    if err != nil {
+        fatal("Erro ao montar o schema GraphQL", err)
+    }
+
+    // O mesmo limitador vale para a API REST e a gRPC, com baldes comuns.
+    rateLimiter := newRateLimiter()
+
+    r := handler.NewRouter(handler.RouterConfig{
+        JWTSecret:           jwtSecret,
+        UserService:         userService,
+        AccountService:      accountService,
+        EventService:        eventService,
+        HealthService:       healthService,
+        APIKeyService:       apiKeyService,
+        SignatureService:    signatureService,
+        TwoFactorService:    twoFactorService,
+        OrganizationService: orgService,
+        AuditService:        auditService,
+        SSOService:          ssoService,
+        GraphQL:             graphqlHandler,
+        RateLimiter:         rateLimiter,
+        Timeout:             env.Duration("REQUEST_TIMEOUT", 10*time.Second),
+        RouteTimeouts: map[string]time.Duration{
+            "/api/events":       env.Duration("EVENTS_REQUEST_TIMEOUT", 30*time.Second),
+            "/api/audit/export": env.Duration("AUDIT_EXPORT_TIMEOUT", 2*time.Minute),
+            "/graphql":          env.Duration("GRAPHQL_REQUEST_TIMEOUT", 30*time.Second),
+        },
+    })
+
+    ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+    defer stop()
+
+    serverConfig := server.NewConfig()
+    srv = server.New(serverConfig, handler.RequestID(handler.Language(r)))
+
+    grpcDone := make(chan error, 1)
+    if env.Bool("GRPC_ENABLED", true) {
+        grpcConfig := grpcserver.NewConfig()
+        grpcListener, err := net.Listen("tcp", grpcConfig.Addr)
+        if err != nil {
+            fatal("Erro ao abrir porta gRPC", err)
+        }
+        grpcServer := grpcserver.New(grpcConfig, grpcserver.Services{
+            JWTSecret:     jwtSecret,
+            UserService:   userService,
+            EventService:  eventService,
+            APIKeyService: apiKeyService,
+            RateLimiter:   rateLimiter,
+        })
+        go func() {
+            grpcDone <- grpcServer.Serve(ctx, grpcListener)
+        }()
+        slog.Info("Servidor gRPC iniciado", "addr", grpcConfig.Addr)
+    } else {
+        grpcDone <- nil
+    }
 
-    port := getEnv("PORT", "8080")
-    log.Printf("🚀 Servidor rodando na porta %s", port)
-    log.Fatal(http.ListenAndServe(":"+port, r))
+    metricsDone := make(chan error, 1)
+    if env.Bool("METRICS_ENABLED", true) {
+        metricsConfig := *serverConfig
+        metricsConfig.Addr = ":" + env.String("METRICS_PORT", "9100")
+        metricsServer := server.New(&metricsConfig, metrics.Handler())
+        go func() {
+            metricsDone <- metricsServer.Run(ctx)
+        }()
+        slog.Info("Servidor de métricas iniciado", "addr", metricsConfig.Addr)
+    } else {
+        metricsDone <- nil
+    }
+
+    slog.Info("Servidor iniciado", "addr", serverConfig.Addr)
+    if err := srv.Run(ctx); err != nil {
+        slog.Error("Erro ao desligar servidor", "error", err)
+    }
+    if err := <-grpcDone; err != nil {
+        slog.Error("Erro ao desligar servidor gRPC", "error", err)
+    }
+    if err := <-metricsDone; err != nil {
+        slog.Error("Erro no servidor de métricas", "error", err)
+    }
+
+    if err := shutdownTracing(context.Background()); err != nil {
+        slog.Error("Erro ao enviar spans pendentes", "error", err)
+    }
+
+    if err := db.Close(); err != nil {
+        slog.Error("Erro ao fechar banco", "error", err)
+    }
+}
+
+func fatal(msg string, err error) {
+    slog.Error(msg, "error", err)
+    os.Exit(1)
 }
 
 func generateJWTSecret() []byte {
     secret := make([]byte, 32)
     _, err := rand.Read(secret)
     if err != nil {
-        log.Fatal("❌ Erro ao gerar JWT secret:", err)
+        fatal("Erro ao gerar JWT secret", err)
     }
     return secret
 }
 
-func getEnv(key, defaultValue string) string {
-    if value := os.Getenv(key); value != "" {
-        return value
+func newMailer() mailer.Mailer {
+    smtpConfig := mailer.NewSMTPConfig()
+    if smtpConfig.Host == "" {
+        slog.Warn("SMTP_HOST não configurado, emails não serão enviados")
+        return mailer.NewLogMailer()
+    }
+    return mailer.NewSMTPMailer(smtpConfig)
+}
+
+// newRateLimiter lê os limites de RATE_LIMIT_<GRUPO>_<PRINCIPAL>, como
+// RATE_LIMIT_EVENTS_API_KEY=600/1m; "0" desliga o limite. Os baldes ficam em
+// memória, por réplica.
+func newRateLimiter() *service.RateLimiter {
+    if !env.Bool("RATE_LIMIT_ENABLED", true) {
+        return nil
+    }
+    config := service.DefaultRateLimiterConfig()
+    for _, group := range service.RateLimitGroups {
+        if config[group] == nil {
+            config[group] = service.RateLimitPolicy{}
+        }
+        for _, kind := range service.RateLimitPrincipals {
+            key := "RATE_LIMIT_" + strings.ToUpper(group+"_"+kind)
+            value := env.String(key, "")
+            if value == "" {
+                continue
+            }
+            limit, err := service.ParseRateLimit(value)
+            if err != nil {
+                fatal("Configuração inválida em "+key, err)
+            }
+            config[group][kind] = limit
+        }
     }
-    return defaultValue
-} 
\ No newline at end of file
+    return service.NewRateLimiter(repository.NewMemoryRateLimitStore(), config)
+}
+
+func newPasswordPolicy() *service.PasswordPolicy {
+    policy := service.DefaultPasswordPolicy()
+    policy.MinLength = env.Int("PASSWORD_MIN_LENGTH", policy.MinLength)
+    policy.RequireUpper = env.Bool("PASSWORD_REQUIRE_UPPER", policy.RequireUpper)
+    policy.RequireLower = env.Bool("PASSWORD_REQUIRE_LOWER", policy.RequireLower)
+    policy.RequireDigit = env.Bool("PASSWORD_REQUIRE_DIGIT", policy.RequireDigit)
+    policy.RequireSymbol = env.Bool("PASSWORD_REQUIRE_SYMBOL", policy.RequireSymbol)
+    if !env.Bool("PASSWORD_CHECK_BREACHED", true) {
+        policy.Breached = nil
+    }
+    return policy
+}
@@ -0,0 +1,76 @@
+// Package breach verifica senhas contra listas de senhas vazadas usando
+// k-anonimato: apenas os 5 primeiros caracteres do SHA-1 são usados na
+// consulta, e a comparação do restante do hash é feita localmente.
+package breach
+
+import (
+	"bufio"
+	"crypto/sha1"
+	_ "embed"
+	"encoding/hex"
+	"strings"
+)
+
+//go:embed breached_sha1.txt
+var bundledList string
+
+// RangeSource devolve os sufixos de hash conhecidos para um prefixo, no mesmo
+// formato da API de faixas do Have I Been Pwned.
+type RangeSource interface {
+	Range(prefix string) ([]string, error)
+}
+
+type Checker struct {
+	source RangeSource
+}
+
+func NewChecker(source RangeSource) *Checker {
+	return &Checker{source: source}
+}
+
+// NewLocalChecker usa a lista embutida no binário, sem acesso à rede.
+func NewLocalChecker() *Checker {
+	return NewChecker(NewLocalSource(bundledList))
+}
+
+func (c *Checker) IsBreached(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	suffixes, err := c.source.Range(prefix)
+	if err != nil {
+		return false, err
+	}
+
+	for _, candidate := range suffixes {
+		if candidate == suffix {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type localSource struct {
+	ranges map[string][]string
+}
+
+// NewLocalSource lê linhas no formato PREFIXO:SUFIXO.
+func NewLocalSource(list string) RangeSource {
+	ranges := make(map[string][]string)
+	scanner := bufio.NewScanner(strings.NewReader(list))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		prefix, suffix, ok := strings.Cut(line, ":")
+		if !ok || len(prefix) != 5 {
+			continue
+		}
+		prefix = strings.ToUpper(prefix)
+		ranges[prefix] = append(ranges[prefix], strings.ToUpper(suffix))
+	}
+	return &localSource{ranges: ranges}
+}
+
+func (s *localSource) Range(prefix string) ([]string, error) {
+	return s.ranges[strings.ToUpper(prefix)], nil
+}
@@ -0,0 +1,40 @@
+package breach
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalChecker_BundledPasswords(t *testing.T) {
+	checker := NewLocalChecker()
+
+	for _, password := range []string{"admin123", "password123", "123456", "senha123"} {
+		breached, err := checker.IsBreached(password)
+		assert.NoError(t, err)
+		assert.True(t, breached, password)
+	}
+
+	breached, err := checker.IsBreached("correct-Horse-battery-staple-91")
+	assert.NoError(t, err)
+	assert.False(t, breached)
+}
+
+type recordingSource struct {
+	prefixes []string
+}
+
+func (s *recordingSource) Range(prefix string) ([]string, error) {
+	s.prefixes = append(s.prefixes, prefix)
+	return nil, nil
+}
+
+func TestChecker_OnlySendsHashPrefix(t *testing.T) {
+	source := &recordingSource{}
+	checker := NewChecker(source)
+
+	_, err := checker.IsBreached("password")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"5BAA6"}, source.prefixes)
+}
@@ -1,32 +1,129 @@
 package database
 
 import (
-	"os"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nathaliaoliveira/goapp/internal/env"
 )
 
 type DatabaseConfig struct {
+	// URL, quando definida (DATABASE_URL), substitui os campos de conexão individuais.
+	URL      string
 	Host     string
 	Port     string
 	User     string
 	Password string
 	DBName   string
 	SSLMode  string
+
+	MaxOpenConns     int
+	MaxIdleConns     int
+	ConnMaxLifetime  time.Duration
+	ConnMaxIdleTime  time.Duration
+	StatementTimeout time.Duration
+	ConnectTimeout   time.Duration
+
+	// ConnectRetries é o número de novas tentativas na inicialização; a espera
+	// começa em ConnectBackoff e dobra a cada falha até MaxConnectBackoff.
+	ConnectRetries    int
+	ConnectBackoff    time.Duration
+	MaxConnectBackoff time.Duration
 }
 
 func NewDatabaseConfig() *DatabaseConfig {
 	return &DatabaseConfig{
-		Host:     getEnv("DB_HOST", "localhost"),
-		Port:     getEnv("DB_PORT", "5432"),
-		User:     getEnv("DB_USER", "postgres"),
-		Password: getEnv("DB_PASSWORD", "postgres"),
-		DBName:   getEnv("DB_NAME", "goapp"),
-		SSLMode:  getEnv("DB_SSLMODE", "disable"),
+		URL:      env.String("DATABASE_URL", ""),
+		Host:     env.String("DB_HOST", "localhost"),
+		Port:     env.String("DB_PORT", "5432"),
+		User:     env.String("DB_USER", "postgres"),
+		Password: env.String("DB_PASSWORD", "postgres"),
+		DBName:   env.String("DB_NAME", "goapp"),
+		SSLMode:  env.String("DB_SSLMODE", "disable"),
+
+		MaxOpenConns:     env.Int("DB_MAX_OPEN_CONNS", 25),
+		MaxIdleConns:     env.Int("DB_MAX_IDLE_CONNS", 5),
+		ConnMaxLifetime:  env.Duration("DB_CONN_MAX_LIFETIME", 30*time.Minute),
+		ConnMaxIdleTime:  env.Duration("DB_CONN_MAX_IDLE_TIME", 5*time.Minute),
+		StatementTimeout: env.Duration("DB_STATEMENT_TIMEOUT", 30*time.Second),
+		ConnectTimeout:   env.Duration("DB_CONNECT_TIMEOUT", 5*time.Second),
+
+		ConnectRetries:    env.Int("DB_CONNECT_RETRIES", 10),
+		ConnectBackoff:    env.Duration("DB_CONNECT_BACKOFF", 500*time.Millisecond),
+		MaxConnectBackoff: 10 * time.Second,
+	}
+}
+
+// DSN monta a string de conexão do lib/pq. Parâmetros que não são do driver,
+// como statement_timeout, são enviados ao Postgres como configuração da sessão.
+func (c *DatabaseConfig) DSN() string {
+	params := map[string]string{}
+	if c.StatementTimeout > 0 {
+		params["statement_timeout"] = strconv.FormatInt(c.StatementTimeout.Milliseconds(), 10)
+	}
+	if c.ConnectTimeout > 0 {
+		// connect_timeout do lib/pq é em segundos; abaixo de 1s seria ignorado.
+		seconds := int(c.ConnectTimeout.Round(time.Second) / time.Second)
+		if seconds < 1 {
+			seconds = 1
+		}
+		params["connect_timeout"] = strconv.Itoa(seconds)
+	}
+
+	if c.URL != "" {
+		return withURLParams(c.URL, params)
 	}
+
+	fields := []string{
+		"host=" + quoteDSNValue(c.Host),
+		"port=" + quoteDSNValue(c.Port),
+		"user=" + quoteDSNValue(c.User),
+		"password=" + quoteDSNValue(c.Password),
+		"dbname=" + quoteDSNValue(c.DBName),
+		"sslmode=" + quoteDSNValue(c.SSLMode),
+	}
+	for _, key := range []string{"connect_timeout", "statement_timeout"} {
+		if value, ok := params[key]; ok {
+			fields = append(fields, key+"="+value)
+		}
+	}
+	return strings.Join(fields, " ")
+}
+
+// Target descreve o banco para os logs sem expor a senha.
+func (c *DatabaseConfig) Target() string {
+	if c.URL != "" {
+		if u, err := url.Parse(c.URL); err == nil {
+			return u.Host + u.Path
+		}
+		return "DATABASE_URL"
+	}
+	return c.Host + ":" + c.Port + "/" + c.DBName
 }
 
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
+// withURLParams só acrescenta parâmetros que a URL ainda não define.
+func withURLParams(raw string, params map[string]string) string {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") {
+		return raw
+	}
+	query := u.Query()
+	for key, value := range params {
+		if query.Get(key) == "" {
+			query.Set(key, value)
+		}
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+func quoteDSNValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, ` '\`) {
 		return value
 	}
-	return defaultValue
-} 
\ No newline at end of file
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `'`, `\'`)
+	return "'" + value + "'"
+}
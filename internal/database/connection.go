@@ -3,22 +3,53 @@ package database
 import (
 	"database/sql"
 	"fmt"
+	"log/slog"
+	"time"
 
 	_ "github.com/lib/pq"
 )
 
+// Connect abre o pool e espera o banco ficar disponível, tentando de novo com
+// backoff exponencial; útil quando a aplicação sobe junto com o Postgres.
 func (c *DatabaseConfig) Connect() (*sql.DB, error) {
-	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		c.Host, c.Port, c.User, c.Password, c.DBName, c.SSLMode)
-		
-	db, err := sql.Open("postgres", connStr)
+	db, err := sql.Open("postgres", c.DSN())
 	if err != nil {
 		return nil, fmt.Errorf("erro ao conectar ao banco: %v", err)
 	}
-	
-	if err := db.Ping(); err != nil {
-		return nil, fmt.Errorf("erro ao fazer ping no banco: %v", err)
+
+	db.SetMaxOpenConns(c.MaxOpenConns)
+	db.SetMaxIdleConns(c.MaxIdleConns)
+	db.SetConnMaxLifetime(c.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(c.ConnMaxIdleTime)
+
+	slog.Info("Conectando ao banco", "target", c.Target())
+
+	backoff := c.ConnectBackoff
+	for attempt := 0; ; attempt++ {
+		err = db.Ping()
+		if err == nil {
+			break
+		}
+		if attempt >= c.ConnectRetries {
+			db.Close()
+			return nil, fmt.Errorf("erro ao fazer ping no banco após %d tentativas: %v", attempt+1, err)
+		}
+		slog.Warn("Banco indisponível, aguardando nova tentativa", "error", err, "retry_in", backoff)
+		time.Sleep(backoff)
+		backoff = nextBackoff(backoff, c.MaxConnectBackoff)
 	}
-	
+
+	slog.Info("Conectado ao banco de dados PostgreSQL")
 	return db, nil
-} 
\ No newline at end of file
+}
+
+func nextBackoff(current, max time.Duration) time.Duration {
+	next := current * 2
+	if next <= 0 {
+		next = 100 * time.Millisecond
+	}
+	if max > 0 && next > max {
+		return max
+	}
+	return next
+}
@@ -0,0 +1,413 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed migrations/*.sql
+var embeddedMigrations embed.FS
+
+// migrationLockKey identifica o advisory lock que serializa migrações de
+// instâncias subindo ao mesmo tempo.
+const migrationLockKey int64 = 7_263_001_036
+
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Checksum cobre apenas o up: é ele que define o schema aplicado.
+func (m Migration) Checksum() string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(m.Up)))
+}
+
+func (m Migration) String() string {
+	return fmt.Sprintf("%04d_%s", m.Version, m.Name)
+}
+
+type MigrationStatus struct {
+	Migration
+	Applied          bool
+	AppliedAt        *time.Time
+	ChecksumMismatch bool
+	// Missing indica uma versão registrada no banco sem arquivo correspondente.
+	Missing bool
+}
+
+// ChecksumMismatchError indica que uma migração já aplicada foi editada.
+type ChecksumMismatchError struct {
+	Version int
+	Name    string
+}
+
+func (e *ChecksumMismatchError) Error() string {
+	return fmt.Sprintf("migração %04d_%s foi alterada depois de aplicada; crie uma nova migração em vez de editá-la", e.Version, e.Name)
+}
+
+// EmbeddedMigrations devolve as migrações compiladas no binário.
+func EmbeddedMigrations() ([]Migration, error) {
+	sub, err := fs.Sub(embeddedMigrations, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	return LoadMigrations(sub)
+}
+
+// LoadMigrations lê pares NNNN_nome.up.sql / NNNN_nome.down.sql ordenados por versão.
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar migrações: %w", err)
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("nome de migração inválido: %s", entry.Name())
+		}
+		version, _ := strconv.Atoi(match[1])
+		if version == 0 {
+			return nil, fmt.Errorf("versão de migração inválida: %s", entry.Name())
+		}
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler migração %s: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("versão %04d usada por duas migrações: %s e %s", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if strings.TrimSpace(m.Up) == "" || strings.TrimSpace(m.Down) == "" {
+			return nil, fmt.Errorf("migração %s precisa dos arquivos up e down", m)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// CreateMigration cria o par de arquivos vazios com a próxima versão disponível em dir.
+func CreateMigration(dir, name string) (upPath, downPath string, err error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = regexp.MustCompile(`[^a-z0-9]+`).ReplaceAllString(name, "_")
+	name = strings.Trim(name, "_")
+	if name == "" {
+		return "", "", fmt.Errorf("nome da migração é obrigatório")
+	}
+
+	existing, err := LoadMigrations(os.DirFS(dir))
+	if err != nil {
+		return "", "", err
+	}
+	next := 1
+	if len(existing) > 0 {
+		next = existing[len(existing)-1].Version + 1
+	}
+
+	base := fmt.Sprintf("%04d_%s", next, name)
+	upPath = filepath.Join(dir, base+".up.sql")
+	downPath = filepath.Join(dir, base+".down.sql")
+	if err := os.WriteFile(upPath, []byte("-- "+base+" (up)\n"), 0o644); err != nil {
+		return "", "", err
+	}
+	if err := os.WriteFile(downPath, []byte("-- "+base+" (down)\n"), 0o644); err != nil {
+		return "", "", err
+	}
+	return upPath, downPath, nil
+}
+
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+func NewMigrator(db *sql.DB, migrations []Migration) *Migrator {
+	return &Migrator{db: db, migrations: migrations}
+}
+
+// Up aplica, em ordem e cada uma na sua transação, as migrações pendentes.
+func (m *Migrator) Up() ([]Migration, error) {
+	var applied []Migration
+	err := m.withLock(func(conn *sql.Conn) error {
+		state, err := m.appliedState(conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(state); err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := state[migration.Version]; ok {
+				continue
+			}
+			if err := m.apply(conn, migration); err != nil {
+				return err
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down desfaz as últimas steps migrações aplicadas, da mais nova para a mais antiga.
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	var reverted []Migration
+	err := m.withLock(func(conn *sql.Conn) error {
+		state, err := m.appliedState(conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := state[migration.Version]; !ok {
+				continue
+			}
+			if err := m.revert(conn, migration); err != nil {
+				return err
+			}
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	ctx := context.Background()
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := ensureMigrationsTable(conn); err != nil {
+		return nil, err
+	}
+	state, err := m.appliedState(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	known := map[int]bool{}
+	for _, migration := range m.migrations {
+		known[migration.Version] = true
+		status := MigrationStatus{Migration: migration}
+		if row, ok := state[migration.Version]; ok {
+			appliedAt := row.appliedAt
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+			status.ChecksumMismatch = row.checksum != migration.Checksum()
+		}
+		statuses = append(statuses, status)
+	}
+	for version, row := range state {
+		if !known[version] {
+			appliedAt := row.appliedAt
+			statuses = append(statuses, MigrationStatus{
+				Migration: Migration{Version: version, Name: row.name},
+				Applied:   true,
+				AppliedAt: &appliedAt,
+				Missing:   true,
+			})
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// PendingMigrationsError indica que o banco está atrás do binário.
+type PendingMigrationsError struct {
+	Pending []Migration
+}
+
+func (e *PendingMigrationsError) Error() string {
+	return fmt.Sprintf("%d migração(ões) pendente(s), a primeira é %s", len(e.Pending), e.Pending[0])
+}
+
+// CheckPending falha enquanto houver migração embutida não aplicada. Feita
+// para o /readyz: só lê schema_migrations, sem lock e sem criar a tabela.
+func (m *Migrator) CheckPending(ctx context.Context) error {
+	rows, err := m.db.QueryContext(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return fmt.Errorf("erro ao consultar schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := map[int]bool{}
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return err
+		}
+		applied[version] = true
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	var pending []Migration
+	for _, migration := range m.migrations {
+		if !applied[migration.Version] {
+			pending = append(pending, migration)
+		}
+	}
+	if len(pending) > 0 {
+		return &PendingMigrationsError{Pending: pending}
+	}
+	return nil
+}
+
+// withLock usa uma única conexão porque o advisory lock do Postgres pertence à sessão.
+func (m *Migrator) withLock(fn func(conn *sql.Conn) error) error {
+	ctx := context.Background()
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao abrir conexão para migrações: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockKey); err != nil {
+		return fmt.Errorf("erro ao obter lock de migração: %w", err)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", migrationLockKey); err != nil {
+			slog.Warn("Erro ao liberar lock de migração", "error", err)
+		}
+	}()
+
+	if err := ensureMigrationsTable(conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+func ensureMigrationsTable(conn *sql.Conn) error {
+	_, err := conn.ExecContext(context.Background(), `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			checksum VARCHAR(64) NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("erro ao criar schema_migrations: %w", err)
+	}
+	return nil
+}
+
+type appliedMigration struct {
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+func (m *Migrator) appliedState(conn *sql.Conn) (map[int]appliedMigration, error) {
+	rows, err := conn.QueryContext(context.Background(), "SELECT version, name, checksum, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("erro ao consultar schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	state := map[int]appliedMigration{}
+	for rows.Next() {
+		var version int
+		var row appliedMigration
+		if err := rows.Scan(&version, &row.name, &row.checksum, &row.appliedAt); err != nil {
+			return nil, err
+		}
+		state[version] = row
+	}
+	return state, rows.Err()
+}
+
+// verify recusa subir quando uma migração aplicada foi editada. Versões
+// desconhecidas só geram aviso, para que um binário antigo ainda suba durante o deploy.
+func (m *Migrator) verify(state map[int]appliedMigration) error {
+	known := map[int]bool{}
+	for _, migration := range m.migrations {
+		known[migration.Version] = true
+		if row, ok := state[migration.Version]; ok && row.checksum != migration.Checksum() {
+			return &ChecksumMismatchError{Version: migration.Version, Name: migration.Name}
+		}
+	}
+	for version, row := range state {
+		if !known[version] {
+			slog.Warn("Migração aplicada no banco não existe neste binário", "version", version, "name", row.name)
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) apply(conn *sql.Conn, migration Migration) error {
+	return inTx(conn, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(migration.Up); err != nil {
+			return fmt.Errorf("erro ao aplicar migração %s: %w", migration, err)
+		}
+		_, err := tx.Exec("INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)",
+			migration.Version, migration.Name, migration.Checksum())
+		return err
+	})
+}
+
+func (m *Migrator) revert(conn *sql.Conn, migration Migration) error {
+	return inTx(conn, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(migration.Down); err != nil {
+			return fmt.Errorf("erro ao desfazer migração %s: %w", migration, err)
+		}
+		_, err := tx.Exec("DELETE FROM schema_migrations WHERE version = $1", migration.Version)
+		return err
+	})
+}
+
+func inTx(conn *sql.Conn, fn func(tx *sql.Tx) error) error {
+	tx, err := conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,91 @@
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMigrations_SortsAndPairsFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0002_add_b.up.sql":   {Data: []byte("CREATE TABLE b (id INT);")},
+		"0002_add_b.down.sql": {Data: []byte("DROP TABLE b;")},
+		"0001_add_a.up.sql":   {Data: []byte("CREATE TABLE a (id INT);")},
+		"0001_add_a.down.sql": {Data: []byte("DROP TABLE a;")},
+	}
+
+	migrations, err := LoadMigrations(fsys)
+
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+	assert.Equal(t, 1, migrations[0].Version)
+	assert.Equal(t, "add_a", migrations[0].Name)
+	assert.Equal(t, "DROP TABLE a;", migrations[0].Down)
+	assert.Equal(t, "0002_add_b", migrations[1].String())
+}
+
+func TestLoadMigrations_RejectsInvalidSets(t *testing.T) {
+	cases := map[string]fstest.MapFS{
+		"sem down": {
+			"0001_add_a.up.sql": {Data: []byte("CREATE TABLE a (id INT);")},
+		},
+		"versão duplicada": {
+			"0001_add_a.up.sql":   {Data: []byte("SELECT 1;")},
+			"0001_add_a.down.sql": {Data: []byte("SELECT 1;")},
+			"0001_add_b.up.sql":   {Data: []byte("SELECT 1;")},
+			"0001_add_b.down.sql": {Data: []byte("SELECT 1;")},
+		},
+		"nome inválido": {
+			"add_a.sql": {Data: []byte("SELECT 1;")},
+		},
+	}
+
+	for name, fsys := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := LoadMigrations(fsys)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestChecksum_ChangesWithUpOnly(t *testing.T) {
+	m := Migration{Version: 1, Name: "add_a", Up: "CREATE TABLE a (id INT);", Down: "DROP TABLE a;"}
+	edited := m
+	edited.Up = "CREATE TABLE a (id BIGINT);"
+	downOnly := m
+	downOnly.Down = "DROP TABLE IF EXISTS a;"
+
+	assert.Len(t, m.Checksum(), 64)
+	assert.NotEqual(t, m.Checksum(), edited.Checksum())
+	assert.Equal(t, m.Checksum(), downOnly.Checksum())
+}
+
+func TestEmbeddedMigrations_AreSequential(t *testing.T) {
+	migrations, err := EmbeddedMigrations()
+
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+	for i, m := range migrations {
+		assert.Equal(t, i+1, m.Version, "migração %s fora de sequência", m)
+	}
+}
+
+func TestCreateMigration_UsesNextVersion(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0003_add_a.up.sql"), []byte("SELECT 1;"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0003_add_a.down.sql"), []byte("SELECT 1;"), 0o644))
+
+	upPath, downPath, err := CreateMigration(dir, "Add Audit Index")
+
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "0004_add_audit_index.up.sql"), upPath)
+	assert.Equal(t, filepath.Join(dir, "0004_add_audit_index.down.sql"), downPath)
+
+	migrations, err := LoadMigrations(os.DirFS(dir))
+	require.NoError(t, err)
+	assert.Len(t, migrations, 2)
+}
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS email_events;
+DROP TABLE IF EXISTS users;
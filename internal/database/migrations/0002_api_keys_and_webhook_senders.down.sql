@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS webhook_senders;
+DROP TABLE IF EXISTS api_keys;
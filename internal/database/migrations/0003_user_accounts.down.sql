@@ -0,0 +1,19 @@
+DROP TABLE IF EXISTS user_tokens;
+
+-- O email só volta a ser único sem os usuários removidos, que não são apagados
+-- aqui: o down falha enquanto existirem, e o operador decide o que fazer com
+-- eles e com os seus dados.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM users WHERE deleted_at IS NOT NULL) THEN
+        RAISE EXCEPTION 'há usuários removidos (deleted_at preenchido); apague-os ou restaure-os antes de desfazer 0003_user_accounts';
+    END IF;
+END
+$$;
+DROP INDEX IF EXISTS idx_users_email_active;
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS deleted_at,
+    DROP COLUMN IF EXISTS role,
+    DROP COLUMN IF EXISTS email_verified_at;
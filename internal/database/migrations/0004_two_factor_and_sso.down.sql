@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS app_settings;
+DROP TABLE IF EXISTS user_identities;
+DROP TABLE IF EXISTS user_recovery_codes;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS totp_last_step,
+    DROP COLUMN IF EXISTS totp_enabled_at,
+    DROP COLUMN IF EXISTS totp_secret;
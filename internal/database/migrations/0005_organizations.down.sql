@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS sites;
+
+ALTER TABLE webhook_senders DROP COLUMN IF EXISTS org_id;
+ALTER TABLE api_keys DROP COLUMN IF EXISTS org_id;
+ALTER TABLE email_events DROP COLUMN IF EXISTS org_id;
+ALTER TABLE users DROP COLUMN IF EXISTS org_id;
+
+DROP TABLE IF EXISTS organizations;
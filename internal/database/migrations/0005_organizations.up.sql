@@ -0,0 +1,40 @@
+CREATE TABLE IF NOT EXISTS organizations (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Os dados que já existiam passam a pertencer a uma organização padrão.
+INSERT INTO organizations (name) SELECT 'Default' WHERE NOT EXISTS (SELECT 1 FROM organizations);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS org_id INTEGER REFERENCES organizations(id) ON DELETE CASCADE;
+UPDATE users SET org_id = (SELECT MIN(id) FROM organizations) WHERE org_id IS NULL;
+ALTER TABLE users ALTER COLUMN org_id SET NOT NULL;
+
+ALTER TABLE email_events ADD COLUMN IF NOT EXISTS org_id INTEGER REFERENCES organizations(id) ON DELETE CASCADE;
+UPDATE email_events SET org_id = (SELECT MIN(id) FROM organizations) WHERE org_id IS NULL;
+ALTER TABLE email_events ALTER COLUMN org_id SET NOT NULL;
+
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS org_id INTEGER REFERENCES organizations(id) ON DELETE CASCADE;
+UPDATE api_keys SET org_id = (SELECT MIN(id) FROM organizations) WHERE org_id IS NULL;
+ALTER TABLE api_keys ALTER COLUMN org_id SET NOT NULL;
+
+ALTER TABLE webhook_senders ADD COLUMN IF NOT EXISTS org_id INTEGER REFERENCES organizations(id) ON DELETE CASCADE;
+UPDATE webhook_senders SET org_id = (SELECT MIN(id) FROM organizations) WHERE org_id IS NULL;
+ALTER TABLE webhook_senders ALTER COLUMN org_id SET NOT NULL;
+
+CREATE TABLE IF NOT EXISTS sites (
+    id SERIAL PRIMARY KEY,
+    org_id INTEGER NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    domain VARCHAR(255) UNIQUE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Sites que já recebiam eventos ficam com a organização desses eventos.
+INSERT INTO sites (org_id, domain)
+SELECT MIN(org_id), site FROM email_events GROUP BY site
+ON CONFLICT (domain) DO NOTHING;
+
+CREATE INDEX IF NOT EXISTS idx_email_events_org_timestamp ON email_events(org_id, timestamp);
+CREATE INDEX IF NOT EXISTS idx_users_org ON users(org_id);
+CREATE INDEX IF NOT EXISTS idx_sites_org ON sites(org_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_email_events_org_hash;
+CREATE INDEX IF NOT EXISTS idx_email_events_org_hash ON email_events(org_id, content_hash);
+
+INSERT INTO email_events SELECT * FROM email_events_duplicates;
+DROP TABLE IF EXISTS email_events_duplicates;
@@ -0,0 +1,22 @@
+-- Bancos criados pelo antigo init.sql tinham content_hash único em todas as
+-- organizações e um índice extra; a deduplicação passa a ser por organização.
+ALTER TABLE email_events DROP CONSTRAINT IF EXISTS email_events_content_hash_key;
+DROP INDEX IF EXISTS idx_email_events_content_hash;
+DROP INDEX IF EXISTS idx_email_events_org_hash;
+
+-- Eventos repetidos na mesma organização não são apagados: ficam em
+-- email_events_duplicates, para conferência, e só o mais antigo continua em
+-- email_events.
+CREATE TABLE IF NOT EXISTS email_events_duplicates (LIKE email_events);
+
+WITH moved AS (
+    DELETE FROM email_events a
+    USING email_events b
+    WHERE a.org_id = b.org_id
+      AND a.content_hash = b.content_hash
+      AND a.id > b.id
+    RETURNING a.*
+)
+INSERT INTO email_events_duplicates SELECT * FROM moved;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_email_events_org_hash ON email_events(org_id, content_hash);
@@ -0,0 +1,5 @@
+-- Falha se duas organizações tiverem o mesmo domínio; remova os sites
+-- repetidos antes de desfazer.
+CREATE INDEX IF NOT EXISTS idx_sites_org ON sites(org_id);
+ALTER TABLE sites DROP CONSTRAINT IF EXISTS sites_org_domain_key;
+ALTER TABLE sites ADD CONSTRAINT sites_domain_key UNIQUE (domain);
@@ -0,0 +1,6 @@
+-- O domínio era único em toda a instalação: uma organização não conseguia
+-- cadastrar um site já usado por outra, e o 409 revelava que ele existia.
+-- Agora o domínio é único só dentro da organização.
+ALTER TABLE sites DROP CONSTRAINT IF EXISTS sites_domain_key;
+ALTER TABLE sites ADD CONSTRAINT sites_org_domain_key UNIQUE (org_id, domain);
+DROP INDEX IF EXISTS idx_sites_org;
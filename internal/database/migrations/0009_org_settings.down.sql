@@ -0,0 +1,8 @@
+-- Volta a uma configuração global por chave, mantendo o valor da organização
+-- de menor ID.
+DELETE FROM app_settings a USING app_settings b
+WHERE a.key = b.key AND a.org_id > b.org_id;
+
+ALTER TABLE app_settings DROP CONSTRAINT IF EXISTS app_settings_pkey;
+ALTER TABLE app_settings DROP COLUMN IF EXISTS org_id;
+ALTER TABLE app_settings ADD PRIMARY KEY (key);
@@ -0,0 +1,15 @@
+-- As configurações eram globais: o administrador de qualquer organização
+-- alterava o 2FA obrigatório de todas. Cada organização passa a ter as suas,
+-- começando com o valor global que valia até aqui.
+ALTER TABLE app_settings ADD COLUMN IF NOT EXISTS org_id INTEGER REFERENCES organizations(id) ON DELETE CASCADE;
+
+INSERT INTO app_settings (org_id, key, value, updated_at)
+SELECT o.id, s.key, s.value, s.updated_at
+FROM app_settings s CROSS JOIN organizations o
+WHERE s.org_id IS NULL;
+
+DELETE FROM app_settings WHERE org_id IS NULL;
+
+ALTER TABLE app_settings ALTER COLUMN org_id SET NOT NULL;
+ALTER TABLE app_settings DROP CONSTRAINT IF EXISTS app_settings_pkey;
+ALTER TABLE app_settings ADD PRIMARY KEY (org_id, key);
@@ -0,0 +1,86 @@
+package domain
+
+import "time"
+
+// Granularidades aceitas nas estatísticas agregadas. Semanas começam na segunda-feira.
+const (
+	GranularityDay   = "day"
+	GranularityWeek  = "week"
+	GranularityMonth = "month"
+)
+
+// Dimensões pelas quais as estatísticas podem ser agrupadas, além do período.
+const (
+	DimensionSite      = "site"
+	DimensionEventType = "event_type"
+	DimensionCampaign  = "campaign"
+)
+
+// AnalyticsFilter restringe as consultas analíticas. As datas (AAAA-MM-DD) são
+// inclusivas; listas vazias e campos vazios não filtram.
+type AnalyticsFilter struct {
+	StartDate  string   `json:"start_date,omitempty"`
+	EndDate    string   `json:"end_date,omitempty"`
+	Sites      []string `json:"sites,omitempty"`
+	EventTypes []string `json:"event_types,omitempty"`
+	CampaignID string   `json:"campaign_id,omitempty"`
+	Email      string   `json:"email,omitempty"`
+}
+
+type AnalyticsPage struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+// StoredEvent é um evento já gravado, como devolvido pelas consultas.
+type StoredEvent struct {
+	ID         string    `json:"id"`
+	Type       string    `json:"type"`
+	Email      string    `json:"email"`
+	Site       string    `json:"site"`
+	Timestamp  time.Time `json:"timestamp"`
+	CampaignID string    `json:"campaign_id,omitempty"`
+	Subject    string    `json:"subject,omitempty"`
+}
+
+// CampaignSummary agrega os eventos de uma campanha (campaign_id) no período do filtro.
+type CampaignSummary struct {
+	ID           string    `json:"id"`
+	TotalEvents  int       `json:"total_events"`
+	UniqueEmails int       `json:"unique_emails"`
+	FirstSeen    time.Time `json:"first_seen"`
+	LastSeen     time.Time `json:"last_seen"`
+}
+
+// ContactSummary agrega os eventos de um destinatário no período do filtro.
+type ContactSummary struct {
+	Email       string    `json:"email"`
+	TotalEvents int       `json:"total_events"`
+	FirstSeen   time.Time `json:"first_seen"`
+	LastSeen    time.Time `json:"last_seen"`
+}
+
+type StatsQuery struct {
+	Filter      AnalyticsFilter `json:"filter"`
+	Granularity string          `json:"granularity"`
+	GroupBy     []string        `json:"group_by,omitempty"`
+	Limit       int             `json:"limit"`
+}
+
+// StatsBucket é uma linha das estatísticas agregadas. Period é o primeiro dia
+// do período; as dimensões fora de GroupBy ficam vazias.
+type StatsBucket struct {
+	Period       string `json:"period"`
+	Site         string `json:"site,omitempty"`
+	EventType    string `json:"event_type,omitempty"`
+	CampaignID   string `json:"campaign_id,omitempty"`
+	Count        int    `json:"count"`
+	UniqueEmails int    `json:"unique_emails"`
+}
+
+// GraphQLRequest é o corpo de POST /graphql.
+type GraphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
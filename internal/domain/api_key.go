@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+const (
+	ScopeEventsWrite = "events:write"
+	ScopeStatsRead   = "stats:read"
+	// ScopeEventsRead libera, além de stats:read, os eventos e destinatários
+	// individuais (com emails) na API GraphQL.
+	ScopeEventsRead = "events:read"
+)
+
+var APIKeyScopes = []string{ScopeEventsWrite, ScopeStatsRead, ScopeEventsRead}
+
+type APIKey struct {
+	ID         int        `json:"id"`
+	UserID     int        `json:"user_id"`
+	OrgID      int        `json:"org_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	Sites      []string   `json:"sites"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	Sites     []string   `json:"sites"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// A chave em texto puro só é devolvida na criação; depois disso apenas o hash fica armazenado.
+type APIKeyCreatedResponse struct {
+	Key    string `json:"key"`
+	APIKey APIKey `json:"api_key"`
+}
+
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package domain
+
+import "time"
+
+// Ações registradas no log de auditoria. O prefixo antes do ponto é o tipo do alvo.
+const (
+	AuditLogin            = "auth.login"
+	AuditLoginMFA         = "auth.login_2fa"
+	AuditLoginSSO         = "auth.login_sso"
+	AuditRegister         = "auth.register"
+	AuditPasswordForgot   = "auth.password_forgot"
+	AuditPasswordReset    = "auth.password_reset"
+	AuditUserCreate       = "user.create"
+	AuditUserUpdate       = "user.update"
+	AuditUserDelete       = "user.delete"
+	AuditUserUnlock       = "user.unlock"
+	AuditPasswordChange   = "user.password_change"
+	AuditTwoFactorEnable  = "user.2fa_enable"
+	AuditTwoFactorDisable = "user.2fa_disable"
+	AuditRecoveryCodes    = "user.2fa_recovery_codes"
+	AuditSettingsUpdate   = "settings.2fa_update"
+	AuditSiteCreate       = "site.create"
+	AuditSiteDelete       = "site.delete"
+	AuditAPIKeyCreate     = "api_key.create"
+	AuditAPIKeyRevoke     = "api_key.revoke"
+	AuditSenderCreate     = "sender.create"
+	AuditSenderRevoke     = "sender.revoke"
+	AuditExport           = "audit.export"
+)
+
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeDenied  = "denied"
+	AuditOutcomeFailure = "failure"
+)
+
+// AuditEntry é uma linha do log de auditoria; depois de gravada nunca é alterada.
+type AuditEntry struct {
+	ID         int64     `json:"id"`
+	OrgID      int       `json:"org_id,omitempty"`
+	ActorID    int       `json:"actor_id,omitempty"`
+	ActorEmail string    `json:"actor_email,omitempty"`
+	Action     string    `json:"action"`
+	TargetType string    `json:"target_type,omitempty"`
+	TargetID   string    `json:"target_id,omitempty"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"user_agent"`
+	Outcome    string    `json:"outcome"`
+	StatusCode int       `json:"status_code"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type AuditFilter struct {
+	Action   string
+	ActorID  int
+	Outcome  string
+	TargetID string
+	From     *time.Time
+	To       *time.Time
+	Page     int
+	PageSize int
+}
+
+type AuditListResponse struct {
+	Entries    []AuditEntry `json:"entries"`
+	Page       int          `json:"page"`
+	PageSize   int          `json:"page_size"`
+	Total      int          `json:"total"`
+	TotalPages int          `json:"total_pages"`
+}
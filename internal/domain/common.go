@@ -21,11 +21,55 @@ type HealthResponse struct {
 }
 
 type DatabaseHealth struct {
-    Status     string `json:"status"`
-    LatencyMs  int64  `json:"latency_ms"`
+    Status     string     `json:"status"`
+    LatencyMs  int64      `json:"latency_ms"`
+    Pool       PoolHealth `json:"pool"`
 }
 
+// PoolHealth espelha sql.DBStats do pool de conexões.
+type PoolHealth struct {
+    MaxOpen           int   `json:"max_open"`
+    Open              int   `json:"open"`
+    InUse             int   `json:"in_use"`
+    Idle              int   `json:"idle"`
+    WaitCount         int64 `json:"wait_count"`
+    WaitDurationMs    int64 `json:"wait_duration_ms"`
+    MaxIdleClosed     int64 `json:"max_idle_closed"`
+    MaxIdleTimeClosed int64 `json:"max_idle_time_closed"`
+    MaxLifetimeClosed int64 `json:"max_lifetime_closed"`
+}
+
+// StatisticsHealth traz contagens estimadas pelo planner do Postgres, não exatas.
 type StatisticsHealth struct {
-    TotalUsers  int `json:"total_users"`
-    TotalEvents int `json:"total_events"`
+    TotalUsers  int  `json:"total_users"`
+    TotalEvents int  `json:"total_events"`
+    Estimated   bool `json:"estimated"`
+}
+
+// Status dos health checks.
+const (
+    HealthStatusHealthy   = "healthy"
+    HealthStatusUnhealthy = "unhealthy"
+)
+
+// LivenessResponse só indica que o processo responde; não consulta dependências.
+type LivenessResponse struct {
+    Status    string `json:"status"`
+    Timestamp string `json:"timestamp"`
+    Uptime    string `json:"uptime"`
+}
+
+// ReadinessResponse agrega os checks de dependências; basta um falhar para
+// a instância deixar de receber tráfego.
+type ReadinessResponse struct {
+    Status    string        `json:"status"`
+    Timestamp string        `json:"timestamp"`
+    Checks    []CheckResult `json:"checks"`
+}
+
+type CheckResult struct {
+    Name      string `json:"name"`
+    Status    string `json:"status"`
+    LatencyMs int64  `json:"latency_ms"`
+    Error     string `json:"error,omitempty"`
 } 
\ No newline at end of file
@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// Organization é o tenant dono de usuários, sites, eventos e credenciais.
+type Organization struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Site é um domínio cujos eventos pertencem a uma única organização.
+type Site struct {
+	ID        int       `json:"id"`
+	OrgID     int       `json:"org_id"`
+	Domain    string    `json:"domain"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CreateSiteRequest struct {
+	Domain string `json:"domain"`
+}
@@ -0,0 +1,63 @@
+package domain
+
+// Problem é o corpo de erro de toda a API, no formato RFC 7807
+// (application/problem+json). Code é estável e deve ser usado pelos clientes
+// para decidir o que fazer; Detail é texto para pessoas e pode mudar.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      string       `json:"code"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError descreve a falha de validação de um campo da requisição. Message
+// vem no idioma padrão; Key e Args permitem traduzi-la para o do cliente.
+type FieldError struct {
+	Field   string        `json:"field"`
+	Rule    string        `json:"rule"`
+	Message string        `json:"message"`
+	Key     string        `json:"-"`
+	Args    []interface{} `json:"-"`
+}
+
+// Códigos de erro da API. Novos códigos podem surgir; os existentes não mudam.
+const (
+	ProblemValidationFailed  = "validation_failed"
+	ProblemInvalidBody       = "invalid_body"
+	ProblemInvalidID         = "invalid_id"
+	ProblemBodyTooLarge      = "body_too_large"
+	ProblemUnauthenticated   = "unauthenticated"
+	ProblemAuthFailed        = "authentication_failed"
+	ProblemInvalidToken      = "invalid_token"
+	ProblemForbidden         = "forbidden"
+	ProblemAdminRequired     = "admin_required"
+	ProblemAPIKeyNotAllowed  = "api_key_not_allowed"
+	ProblemInsufficientScope = "insufficient_scope"
+	ProblemSiteForbidden     = "site_forbidden"
+	ProblemSSORejected       = "sso_rejected"
+	ProblemSSOStateInvalid   = "sso_state_invalid"
+	ProblemSSOUserNotLinked  = "sso_user_not_linked"
+	ProblemTooManyAttempts   = "too_many_attempts"
+	ProblemRateLimited       = "rate_limited"
+	ProblemNotFound          = "not_found"
+	ProblemUserNotFound      = "user_not_found"
+	ProblemOrgNotFound       = "organization_not_found"
+	ProblemSiteNotFound      = "site_not_found"
+	ProblemAPIKeyNotFound    = "api_key_not_found"
+	ProblemSenderNotFound    = "webhook_sender_not_found"
+	ProblemIdentityNotFound  = "identity_not_found"
+	ProblemMethodNotAllowed  = "method_not_allowed"
+	ProblemEmailTaken        = "email_taken"
+	ProblemSiteExists        = "site_exists"
+	ProblemDuplicateEvent    = "duplicate_event"
+	ProblemInvalidQuery      = "invalid_query"
+	ProblemQueryTooDeep      = "query_too_deep"
+	ProblemQueryTooComplex   = "query_too_complex"
+	ProblemRequestTimeout    = "request_timeout"
+	ProblemClientClosed      = "client_closed_request"
+	ProblemInternal          = "internal_error"
+)
@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+// Tipos de principal do limitador de requisições. Remetentes de webhook
+// assinados contam como chave de API: os dois são credenciais de sistemas.
+const (
+	PrincipalAPIKey = "api_key"
+	PrincipalUser   = "user"
+	PrincipalIP     = "ip"
+)
+
+// RateLimit é um balde de fichas com capacidade Requests, reabastecido à taxa
+// de Requests por Period. Requests 0 significa sem limite.
+type RateLimit struct {
+	Requests int
+	Period   time.Duration
+}
+
+func (l RateLimit) Enabled() bool {
+	return l.Requests > 0 && l.Period > 0
+}
+
+// RateLimitResult é o estado do balde depois de uma requisição. RetryAfter só
+// é preenchido quando a requisição foi recusada; Reset é o tempo até o balde
+// voltar a ficar cheio.
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration
+	Reset      time.Duration
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// SSOState guarda, entre o início do login e o callback, os valores que
+// amarram a resposta do provedor à requisição original.
+type SSOState struct {
+	Nonce        string
+	CodeVerifier string
+	ExpiresAt    time.Time
+	// ClientIP limita quantos logins um mesmo cliente mantém em andamento.
+	ClientIP string
+}
+
+type SSOStart struct {
+	AuthURL string
+	State   string
+}
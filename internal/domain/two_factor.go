@@ -0,0 +1,45 @@
+package domain
+
+// TwoFactorState é o estado TOTP de um usuário. Secret fica preenchido desde a
+// configuração, mas o 2FA só vale depois que Enabled passa a ser verdadeiro.
+type TwoFactorState struct {
+	Secret   string
+	Enabled  bool
+	LastStep int64
+}
+
+type TwoFactorSetupResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+	QRCodePNG  string `json:"qr_code_png"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code"`
+}
+
+type DisableTwoFactorRequest struct {
+	Password string `json:"password"`
+	Code     string `json:"code"`
+}
+
+// Os códigos de recuperação só são exibidos quando gerados.
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type MFALoginRequest struct {
+	MFAToken string `json:"mfa_token"`
+	Code     string `json:"code"`
+}
+
+type TwoFactorSettings struct {
+	Required bool `json:"required"`
+}
+
+const (
+	SettingRequireTwoFactor = "require_2fa"
+
+	TokenPurposeMFAChallenge  = "mfa_challenge"
+	TokenPurposeMFAEnrollment = "mfa_enrollment"
+)
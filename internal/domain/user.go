@@ -3,11 +3,15 @@ package domain
 import "time"
 
 type User struct {
-    ID           int       `json:"id"`
-    Name         string    `json:"name"`
-    Email        string    `json:"email"`
-    PasswordHash string    `json:"-"`
-    CreatedAt    time.Time `json:"created_at"`
+    ID               int       `json:"id"`
+    OrgID            int       `json:"org_id"`
+    Name             string    `json:"name"`
+    Email            string    `json:"email"`
+    PasswordHash     string    `json:"-"`
+    Role             string    `json:"role"`
+    EmailVerified    bool      `json:"email_verified"`
+    TwoFactorEnabled bool      `json:"two_factor_enabled"`
+    CreatedAt        time.Time `json:"created_at"`
 }
 
 type LoginRequest struct {
@@ -16,12 +20,94 @@ type LoginRequest struct {
 }
 
 type RegisterRequest struct {
+    Organization string `json:"organization"`
+    Name         string `json:"name"`
+    Email        string `json:"email"`
+    Password     string `json:"password"`
+}
+
+// AuthResponse traz o token de sessão ou, quando a conta usa 2FA, apenas o
+// MFAToken de curta duração que deve ser trocado em POST /login/2fa. Com
+// EnrollmentRequired o token só dá acesso à configuração do 2FA.
+type AuthResponse struct {
+    Token              string `json:"token,omitempty"`
+    User               *User  `json:"user,omitempty"`
+    MFARequired        bool   `json:"mfa_required,omitempty"`
+    MFAToken           string `json:"mfa_token,omitempty"`
+    EnrollmentRequired bool   `json:"mfa_enrollment_required,omitempty"`
+}
+
+// CreateUserRequest é usado por administradores para criar contas na própria
+// organização, sem passar pelo cadastro público.
+type CreateUserRequest struct {
     Name     string `json:"name"`
     Email    string `json:"email"`
     Password string `json:"password"`
 }
 
-type AuthResponse struct {
+type ForgotPasswordRequest struct {
+    Email string `json:"email"`
+}
+
+type ResetPasswordRequest struct {
+    Token    string `json:"token"`
+    Password string `json:"password"`
+}
+
+type VerifyEmailRequest struct {
     Token string `json:"token"`
-    User  User   `json:"user"`
-} 
\ No newline at end of file
+}
+
+type UpdateUserRequest struct {
+    Name  *string `json:"name,omitempty"`
+    Email *string `json:"email,omitempty"`
+    Role  *string `json:"role,omitempty"`
+}
+
+type ChangePasswordRequest struct {
+    CurrentPassword string `json:"current_password"`
+    NewPassword     string `json:"new_password"`
+}
+
+type UserListParams struct {
+    Page      int
+    PageSize  int
+    Search    string
+    SortBy    string
+    SortOrder string
+}
+
+type UserListResponse struct {
+    Users      []User `json:"users"`
+    Page       int    `json:"page"`
+    PageSize   int    `json:"page_size"`
+    Total      int    `json:"total"`
+    TotalPages int    `json:"total_pages"`
+}
+
+// Actor identifica quem está executando uma operação autenticada.
+type Actor struct {
+    UserID int
+    OrgID  int
+    Role   string
+}
+
+func (a Actor) IsAdmin() bool {
+    return a.Role == RoleAdmin
+}
+
+const (
+    RoleAdmin = "admin"
+    RoleUser  = "user"
+)
+
+// LoginAttempts acumula as falhas de login de uma conta ou IP.
+type LoginAttempts struct {
+    Failures    int
+    LastFailure time.Time
+}
+
+const (
+    TokenPurposePasswordReset     = "password_reset"
+    TokenPurposeEmailVerification = "email_verification"
+)
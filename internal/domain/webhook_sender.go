@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+type WebhookSender struct {
+	ID        int        `json:"id"`
+	UserID    int        `json:"user_id"`
+	OrgID     int        `json:"org_id"`
+	Name      string     `json:"name"`
+	KeyID     string     `json:"key_id"`
+	Secret    string     `json:"-"`
+	Sites     []string   `json:"sites"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type CreateWebhookSenderRequest struct {
+	Name  string   `json:"name"`
+	Sites []string `json:"sites"`
+}
+
+// O segredo só é devolvido na criação do remetente.
+type WebhookSenderCreatedResponse struct {
+	Secret string        `json:"secret"`
+	Sender WebhookSender `json:"sender"`
+}
+
+// SignedRequest reúne os headers de assinatura e o corpo bruto de uma requisição.
+type SignedRequest struct {
+	KeyID     string
+	Timestamp string
+	Nonce     string
+	Signature string
+	Body      []byte
+}
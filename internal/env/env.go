@@ -0,0 +1,77 @@
+// Package env lê a configuração das variáveis de ambiente. Variável vazia ou
+// ausente usa o padrão; valor inválido também, com um aviso no log, para que
+// todos os pacotes tratem erros de configuração da mesma forma.
+package env
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+)
+
+func String(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// Int aceita apenas inteiros não negativos.
+func Int(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		invalid(key, value, defaultValue)
+		return defaultValue
+	}
+	return n
+}
+
+// Duration aceita durações não negativas no formato de time.ParseDuration.
+func Duration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		invalid(key, value, defaultValue)
+		return defaultValue
+	}
+	return d
+}
+
+// Float aceita apenas números não negativos.
+func Float(key string, defaultValue float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || f < 0 {
+		invalid(key, value, defaultValue)
+		return defaultValue
+	}
+	return f
+}
+
+func Bool(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		invalid(key, value, defaultValue)
+		return defaultValue
+	}
+	return b
+}
+
+func invalid(key, value string, defaultValue interface{}) {
+	slog.Warn("Valor inválido em variável de ambiente, usando o padrão", "key", key, "value", value, "default", defaultValue)
+}
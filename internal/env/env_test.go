@@ -0,0 +1,38 @@
+package env
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultsOnEmptyOrInvalidValues(t *testing.T) {
+	t.Setenv("ENV_TEST_INT", "")
+	assert.Equal(t, 5, Int("ENV_TEST_INT", 5))
+	t.Setenv("ENV_TEST_INT", "x")
+	assert.Equal(t, 5, Int("ENV_TEST_INT", 5))
+	t.Setenv("ENV_TEST_INT", "-1")
+	assert.Equal(t, 5, Int("ENV_TEST_INT", 5))
+	t.Setenv("ENV_TEST_INT", "0")
+	assert.Equal(t, 0, Int("ENV_TEST_INT", 5))
+
+	t.Setenv("ENV_TEST_DURATION", "-1s")
+	assert.Equal(t, time.Minute, Duration("ENV_TEST_DURATION", time.Minute))
+	t.Setenv("ENV_TEST_DURATION", "90s")
+	assert.Equal(t, 90*time.Second, Duration("ENV_TEST_DURATION", time.Minute))
+
+	t.Setenv("ENV_TEST_FLOAT", "-0.5")
+	assert.Equal(t, 1.0, Float("ENV_TEST_FLOAT", 1))
+	t.Setenv("ENV_TEST_FLOAT", "abc")
+	assert.Equal(t, 1.0, Float("ENV_TEST_FLOAT", 1))
+	t.Setenv("ENV_TEST_FLOAT", "0.25")
+	assert.Equal(t, 0.25, Float("ENV_TEST_FLOAT", 1))
+
+	t.Setenv("ENV_TEST_BOOL", "talvez")
+	assert.True(t, Bool("ENV_TEST_BOOL", true))
+	t.Setenv("ENV_TEST_BOOL", "false")
+	assert.False(t, Bool("ENV_TEST_BOOL", true))
+
+	assert.Equal(t, "padrão", String("ENV_TEST_UNSET", "padrão"))
+}
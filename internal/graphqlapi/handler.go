@@ -0,0 +1,175 @@
+package graphqlapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/handler"
+	"github.com/nathaliaoliveira/goapp/internal/i18n"
+	"github.com/nathaliaoliveira/goapp/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const maxRequestBytes = 1 << 20
+
+// Handler atende POST /graphql. Deve ser montado depois do AuthMiddleware,
+// que põe no contexto a organização e os sites permitidos da credencial.
+type Handler struct {
+	schema graphql.Schema
+	limits Limits
+}
+
+func NewHandler(services Services, limits Limits) (*Handler, error) {
+	schema, err := NewSchema(services)
+	if err != nil {
+		return nil, err
+	}
+	return &Handler{schema: schema, limits: limits}, nil
+}
+
+// ServeHTTP responde 200 mesmo quando a consulta tem erros, como de costume
+// em GraphQL; o código de cada erro vem em extensions.code, com os mesmos
+// valores do campo code da API REST.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req domain.GraphQLRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes)).Decode(&req); err != nil {
+		writeResult(w, http.StatusBadRequest, &graphql.Result{Errors: []gqlerrors.FormattedError{
+			codedError(domain.ProblemInvalidBody, i18n.Translate(r.Context(), "request.invalid_body")),
+		}})
+		return
+	}
+
+	orgID, _ := r.Context().Value("org_id").(int)
+	principal := Principal{OrgID: orgID}
+	principal.Sites, _ = r.Context().Value("allowed_sites").([]string)
+	if key, ok := r.Context().Value("api_key").(*domain.APIKey); ok {
+		principal.Scopes = append([]string{}, key.Scopes...)
+	}
+
+	writeResult(w, http.StatusOK, h.Execute(r.Context(), principal, req))
+}
+
+func writeResult(w http.ResponseWriter, status int, result *graphql.Result) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(result)
+}
+
+// Execute valida a consulta, aplica os limites de profundidade e custo e só
+// então executa os resolvers.
+func (h *Handler) Execute(ctx context.Context, principal Principal, req domain.GraphQLRequest) *graphql.Result {
+	ctx, span := tracing.Start(ctx, "GraphQL.Execute", attribute.String("graphql.operation", req.OperationName))
+	defer span.End()
+
+	if req.Query == "" {
+		return &graphql.Result{Errors: []gqlerrors.FormattedError{
+			codedError(domain.ProblemInvalidQuery, i18n.Translate(ctx, "graphql.query_required")),
+		}}
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"})})
+	if err != nil {
+		return &graphql.Result{Errors: invalidQuery(gqlerrors.FormatErrors(err))}
+	}
+	if validation := graphql.ValidateDocument(&h.schema, doc, nil); !validation.IsValid {
+		return &graphql.Result{Errors: invalidQuery(validation.Errors)}
+	}
+
+	depth, cost, err := analyze(&h.schema, doc, req.OperationName, req.Variables)
+	if err != nil {
+		return &graphql.Result{Errors: []gqlerrors.FormattedError{classify(ctx, gqlerrors.FormatError(err))}}
+	}
+	span.SetAttributes(attribute.Int("graphql.depth", depth), attribute.Int("graphql.cost", cost))
+	if h.limits.MaxDepth > 0 && depth > h.limits.MaxDepth {
+		slog.WarnContext(ctx, "Consulta GraphQL recusada pela profundidade", "depth", depth, "limit", h.limits.MaxDepth)
+		return &graphql.Result{Errors: []gqlerrors.FormattedError{
+			codedError(domain.ProblemQueryTooDeep, i18n.Translate(ctx, "graphql.query_too_deep", depth, h.limits.MaxDepth)),
+		}}
+	}
+	if h.limits.MaxCost > 0 && cost > h.limits.MaxCost {
+		slog.WarnContext(ctx, "Consulta GraphQL recusada pelo custo", "cost", cost, "limit", h.limits.MaxCost)
+		return &graphql.Result{Errors: []gqlerrors.FormattedError{
+			codedError(domain.ProblemQueryTooComplex, i18n.Translate(ctx, "graphql.query_too_complex", cost, h.limits.MaxCost)),
+		}}
+	}
+
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        h.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       withPrincipal(ctx, principal),
+	})
+	for i, resolveErr := range result.Errors {
+		result.Errors[i] = classify(ctx, resolveErr)
+	}
+	if result.Extensions == nil {
+		result.Extensions = map[string]interface{}{}
+	}
+	result.Extensions["cost"] = cost
+	return result
+}
+
+// requestError é um erro detectado na camada GraphQL, fora dos serviços.
+type requestError struct {
+	code string
+	key  string
+	args []interface{}
+}
+
+func (e *requestError) Error() string {
+	return i18n.T(i18n.DefaultLanguage, e.key, e.args...)
+}
+
+func codedError(code, message string) gqlerrors.FormattedError {
+	formatted := gqlerrors.NewFormattedError(message)
+	formatted.Extensions = map[string]interface{}{"code": code}
+	return formatted
+}
+
+// invalidQuery marca erros de sintaxe e de validação do documento.
+func invalidQuery(errs []gqlerrors.FormattedError) []gqlerrors.FormattedError {
+	for i := range errs {
+		errs[i].Extensions = map[string]interface{}{"code": domain.ProblemInvalidQuery}
+	}
+	return errs
+}
+
+// classify troca a mensagem dos erros dos resolvers pela da classificação
+// comum da API (handler.ProblemFor), para não expor detalhes internos.
+func classify(ctx context.Context, formatted gqlerrors.FormattedError) gqlerrors.FormattedError {
+	original := formatted.OriginalError()
+	var located *gqlerrors.Error
+	if errors.As(original, &located) && located.OriginalError != nil {
+		original = located.OriginalError
+	}
+
+	var local *requestError
+	if errors.As(original, &local) {
+		formatted.Message = i18n.Translate(ctx, local.key, local.args...)
+		formatted.Extensions = map[string]interface{}{"code": local.code}
+		return formatted
+	}
+
+	problem := handler.ProblemFor(ctx, original)
+	if problem.Status >= http.StatusInternalServerError {
+		slog.ErrorContext(ctx, "Erro ao resolver campo GraphQL", "path", formatted.Path, "error", original)
+	}
+	formatted.Message = problem.Detail
+	if formatted.Message == "" {
+		formatted.Message = problem.Title
+	}
+	formatted.Extensions = map[string]interface{}{"code": problem.Code}
+	if len(problem.Errors) > 0 {
+		formatted.Extensions["errors"] = problem.Errors
+	}
+	return formatted
+}
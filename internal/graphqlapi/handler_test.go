@@ -0,0 +1,262 @@
+package graphqlapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/i18n"
+	"github.com/nathaliaoliveira/goapp/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var day = time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+
+// fakeAnalytics registra os filtros recebidos, para conferir como os
+// argumentos e os objetos pais chegam ao serviço.
+type fakeAnalytics struct {
+	mu      sync.Mutex
+	filters []domain.AnalyticsFilter
+	queries []domain.StatsQuery
+	err     error
+}
+
+func (f *fakeAnalytics) record(filter domain.AnalyticsFilter) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.filters = append(f.filters, filter)
+}
+
+func (f *fakeAnalytics) ListEvents(ctx context.Context, orgID int, filter domain.AnalyticsFilter, page domain.AnalyticsPage) ([]domain.StoredEvent, error) {
+	f.record(filter)
+	return []domain.StoredEvent{{ID: "e1", Type: "open", Email: "a@exemplo.com", Site: "exemplo.com", Timestamp: day, CampaignID: filter.CampaignID}}, f.err
+}
+
+func (f *fakeAnalytics) ListCampaigns(ctx context.Context, orgID int, filter domain.AnalyticsFilter, page domain.AnalyticsPage) ([]domain.CampaignSummary, error) {
+	f.record(filter)
+	return []domain.CampaignSummary{{ID: "black-friday", TotalEvents: 10, UniqueEmails: 4, FirstSeen: day, LastSeen: day}}, f.err
+}
+
+func (f *fakeAnalytics) ListContacts(ctx context.Context, orgID int, filter domain.AnalyticsFilter, page domain.AnalyticsPage) ([]domain.ContactSummary, error) {
+	f.record(filter)
+	return []domain.ContactSummary{{Email: "a@exemplo.com", TotalEvents: 3, FirstSeen: day, LastSeen: day}}, f.err
+}
+
+func (f *fakeAnalytics) AggregateStats(ctx context.Context, orgID int, query domain.StatsQuery) ([]domain.StatsBucket, error) {
+	f.mu.Lock()
+	f.queries = append(f.queries, query)
+	f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	return []domain.StatsBucket{{Period: "2026-01-01", EventType: "open", Count: 7, UniqueEmails: 5}}, nil
+}
+
+type fakeOrganizations struct {
+	service.OrganizationService
+}
+
+func (fakeOrganizations) ListSites(ctx context.Context, orgID int) ([]domain.Site, error) {
+	return []domain.Site{{ID: 1, OrgID: orgID, Domain: "exemplo.com", CreatedAt: day}, {ID: 2, OrgID: orgID, Domain: "outro.com", CreatedAt: day}}, nil
+}
+
+type response struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+	Extensions map[string]interface{} `json:"extensions"`
+}
+
+func newHandler(t *testing.T, analytics *fakeAnalytics, limits Limits) *Handler {
+	t.Helper()
+	h, err := NewHandler(Services{AnalyticsService: analytics, OrganizationService: fakeOrganizations{}}, limits)
+	require.NoError(t, err)
+	return h
+}
+
+// post simula a requisição depois do AuthMiddleware; sites vazio é um usuário
+// com acesso a todos os sites.
+func post(t *testing.T, h *Handler, sites []string, query string, variables map[string]interface{}) response {
+	t.Helper()
+	body, err := json.Marshal(domain.GraphQLRequest{Query: query, Variables: variables})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	ctx := context.WithValue(req.Context(), "org_id", 9)
+	if sites != nil {
+		ctx = context.WithValue(ctx, "allowed_sites", sites)
+	}
+	ctx = i18n.WithLanguage(ctx, "en")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req.WithContext(ctx))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp response
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp
+}
+
+func TestQuery_StatsArguments(t *testing.T) {
+	analytics := &fakeAnalytics{}
+	h := newHandler(t, analytics, Limits{})
+
+	resp := post(t, h, nil, `{
+		stats(filter: {startDate: "2026-01-01", endDate: "2026-01-31", eventTypes: ["open"]}, granularity: WEEK, groupBy: [EVENT_TYPE], limit: 10) {
+			period eventType site count uniqueEmails
+		}
+	}`, nil)
+	require.Empty(t, resp.Errors)
+
+	assert.JSONEq(t, `[{"period": "2026-01-01", "eventType": "open", "site": null, "count": 7, "uniqueEmails": 5}]`, string(resp.Data["stats"]))
+	require.Len(t, analytics.queries, 1)
+	assert.Equal(t, domain.StatsQuery{
+		Filter:      domain.AnalyticsFilter{StartDate: "2026-01-01", EndDate: "2026-01-31", Sites: []string{}, EventTypes: []string{"open"}},
+		Granularity: domain.GranularityWeek,
+		GroupBy:     []string{domain.DimensionEventType},
+		Limit:       10,
+	}, analytics.queries[0])
+	assert.EqualValues(t, 1+10*5, resp.Extensions["cost"])
+}
+
+func TestQuery_NestedFieldsInheritParentFilter(t *testing.T) {
+	analytics := &fakeAnalytics{}
+	h := newHandler(t, analytics, Limits{})
+
+	resp := post(t, h, nil, `query($from: String) {
+		campaigns(filter: {startDate: $from}, limit: 5) {
+			id totalEvents
+			events(limit: 2) { id campaignId }
+		}
+	}`, map[string]interface{}{"from": "2026-01-01"})
+	require.Empty(t, resp.Errors)
+
+	assert.JSONEq(t, `[{"id": "black-friday", "totalEvents": 10, "events": [{"id": "e1", "campaignId": "black-friday"}]}]`, string(resp.Data["campaigns"]))
+	require.Len(t, analytics.filters, 2)
+	assert.Equal(t, "2026-01-01", analytics.filters[1].StartDate)
+	assert.Equal(t, "black-friday", analytics.filters[1].CampaignID)
+}
+
+func TestQuery_RestrictsAPIKeySites(t *testing.T) {
+	analytics := &fakeAnalytics{}
+	h := newHandler(t, analytics, Limits{})
+	allowed := []string{"exemplo.com"}
+
+	resp := post(t, h, allowed, `{ sites { domain } events { id } }`, nil)
+	require.Empty(t, resp.Errors)
+	assert.JSONEq(t, `[{"domain": "exemplo.com"}]`, string(resp.Data["sites"]))
+	assert.Equal(t, allowed, analytics.filters[0].Sites)
+
+	resp = post(t, h, allowed, `{ contacts(filter: {sites: ["outro.com"]}) { email } }`, nil)
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, domain.ProblemSiteForbidden, resp.Errors[0].Extensions["code"])
+	assert.Equal(t, "No permission for site: outro.com", resp.Errors[0].Message)
+}
+
+func TestQuery_RejectsDeepAndExpensiveQueries(t *testing.T) {
+	analytics := &fakeAnalytics{}
+	h := newHandler(t, analytics, Limits{MaxDepth: 2, MaxCost: 1000})
+
+	resp := post(t, h, nil, `{ contacts(limit: 1) { ...history } }
+		fragment history on Contact { events(limit: 1) { id } }`, nil)
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, domain.ProblemQueryTooDeep, resp.Errors[0].Extensions["code"])
+
+	resp = post(t, h, nil, `query($n: Int) { events(limit: $n) { id type email } }`, map[string]interface{}{"n": 500})
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, domain.ProblemQueryTooComplex, resp.Errors[0].Extensions["code"])
+	assert.Equal(t, "Query too expensive: cost 1501, limit 1000", resp.Errors[0].Message)
+
+	// Sem limit, vale o padrão do campo (50 eventos).
+	resp = post(t, h, nil, `{ events { id type email } }`, nil)
+	require.Empty(t, resp.Errors)
+	assert.EqualValues(t, 151, resp.Extensions["cost"])
+
+	assert.Empty(t, analytics.queries)
+	assert.Len(t, analytics.filters, 1, "consultas recusadas não chegam ao serviço")
+}
+
+func TestQuery_CostUsesServiceLimitRules(t *testing.T) {
+	analytics := &fakeAnalytics{}
+	h := newHandler(t, analytics, Limits{MaxCost: 1000})
+
+	// limit: 0 busca o padrão do serviço, então custa como se fosse 50.
+	resp := post(t, h, nil, `{ campaigns(limit: 0) { events(limit: 0) { id } } }`, nil)
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, domain.ProblemQueryTooComplex, resp.Errors[0].Extensions["code"])
+	assert.Equal(t, "Query too expensive: cost 2551, limit 1000", resp.Errors[0].Message)
+
+	resp = post(t, h, nil, `query($n: Int) { events(limit: $n) { id } }`, map[string]interface{}{"n": 0})
+	require.Empty(t, resp.Errors)
+	assert.EqualValues(t, 1+service.DefaultAnalyticsLimit, resp.Extensions["cost"])
+
+	resp = post(t, h, nil, `query($n: Int = 3) { stats(limit: $n) { count } }`, nil)
+	require.Empty(t, resp.Errors)
+	assert.EqualValues(t, 1+3, resp.Extensions["cost"])
+
+	for _, n := range []int{-1, service.MaxAnalyticsLimit + 1} {
+		resp = post(t, h, nil, `query($n: Int) { events(limit: $n) { id } }`, map[string]interface{}{"n": n})
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, domain.ProblemValidationFailed, resp.Errors[0].Extensions["code"])
+	}
+	assert.Len(t, analytics.filters, 1, "consultas recusadas não chegam ao serviço")
+}
+
+func TestQuery_IntrospectionIsFree(t *testing.T) {
+	h := newHandler(t, &fakeAnalytics{}, Limits{MaxDepth: 2, MaxCost: 10})
+
+	resp := post(t, h, nil, `{ __schema { queryType { fields { name type { ofType { ofType { name } } } } } } }`, nil)
+	require.Empty(t, resp.Errors)
+	assert.Contains(t, string(resp.Data["__schema"]), `"campaigns"`)
+}
+
+func TestQuery_ErrorClassification(t *testing.T) {
+	analytics := &fakeAnalytics{err: &service.ValidationError{Key: "analytics.invalid_granularity", Args: []interface{}{"hour"}}}
+	h := newHandler(t, analytics, Limits{})
+
+	resp := post(t, h, nil, `{ stats { count } }`, nil)
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, domain.ProblemValidationFailed, resp.Errors[0].Extensions["code"])
+	assert.Equal(t, "Invalid granularity: hour", resp.Errors[0].Message)
+
+	resp = post(t, h, nil, `{ stats { nope } }`, nil)
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, domain.ProblemInvalidQuery, resp.Errors[0].Extensions["code"])
+
+	resp = post(t, h, nil, ``, nil)
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, "The query is required", resp.Errors[0].Message)
+}
+
+func TestQuery_EventsRequireEventsReadScope(t *testing.T) {
+	analytics := &fakeAnalytics{}
+	h := newHandler(t, analytics, Limits{})
+	ctx := i18n.WithLanguage(context.Background(), "en")
+	statsOnly := Principal{OrgID: 9, Scopes: []string{domain.ScopeStatsRead}}
+
+	result := h.Execute(ctx, statsOnly, domain.GraphQLRequest{Query: `{ stats { count } campaigns { id } }`})
+	require.Empty(t, result.Errors)
+
+	for _, query := range []string{
+		`{ events { id } }`,
+		`{ contacts { email } }`,
+		`{ campaigns { id events { email } } }`,
+	} {
+		result = h.Execute(ctx, statsOnly, domain.GraphQLRequest{Query: query})
+		require.Len(t, result.Errors, 1, query)
+		assert.Equal(t, domain.ProblemInsufficientScope, result.Errors[0].Extensions["code"], query)
+		assert.Equal(t, "API key is missing the required scope: events:read", result.Errors[0].Message)
+	}
+
+	withEvents := Principal{OrgID: 9, Scopes: []string{domain.ScopeStatsRead, domain.ScopeEventsRead}}
+	result = h.Execute(ctx, withEvents, domain.GraphQLRequest{Query: `{ events { id } contacts { email } }`})
+	assert.Empty(t, result.Errors)
+}
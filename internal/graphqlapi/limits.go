@@ -0,0 +1,204 @@
+package graphqlapi
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/nathaliaoliveira/goapp/internal/service"
+)
+
+// Limits protege o banco de consultas caras. As duas verificações analisam o
+// documento já validado, antes de qualquer resolver rodar.
+type Limits struct {
+	// MaxDepth é o maior aninhamento de campos aceito; 0 desliga a verificação.
+	MaxDepth int
+	// MaxCost é o maior custo estimado aceito; 0 desliga a verificação.
+	MaxCost int
+}
+
+const (
+	DefaultMaxDepth = 5
+	DefaultMaxCost  = 20000
+	// unboundedListSize estima listas sem argumento limit (ex.: sites).
+	unboundedListSize = 100
+)
+
+// listLimits aplica ao argumento limit, pelo tipo dos itens da lista, a mesma
+// regra dos serviços: 0 vale o padrão e valores fora do intervalo recusam a
+// consulta. Assim o custo estimado é o que os resolvers vão de fato buscar.
+var listLimits = map[string]func(int) (int, error){
+	"Event":       service.AnalyticsPageLimit,
+	"Campaign":    service.AnalyticsPageLimit,
+	"Contact":     service.AnalyticsPageLimit,
+	"StatsBucket": service.StatsLimit,
+}
+
+// queryCost estima a consulta: cada campo custa 1 e campos de lista
+// multiplicam o custo da sua seleção pelo número de itens pedidos (argumento
+// limit, ou o padrão do campo). Campos de introspecção (__schema, __type...)
+// não consultam o banco e ficam de fora.
+type queryCost struct {
+	schema    *graphql.Schema
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+}
+
+// analyze devolve profundidade e custo da operação escolhida. Sem operação
+// correspondente devolve zero; a execução reporta o erro. Um limit inválido
+// devolve o erro de validação do serviço.
+func analyze(schema *graphql.Schema, doc *ast.Document, operationName string, variables map[string]interface{}) (depth, cost int, err error) {
+	c := &queryCost{schema: schema, fragments: map[string]*ast.FragmentDefinition{}, variables: map[string]interface{}{}}
+
+	var operation *ast.OperationDefinition
+	for _, definition := range doc.Definitions {
+		switch def := definition.(type) {
+		case *ast.FragmentDefinition:
+			c.fragments[def.Name.Value] = def
+		case *ast.OperationDefinition:
+			name := ""
+			if def.Name != nil {
+				name = def.Name.Value
+			}
+			if operation == nil && (operationName == "" || name == operationName) {
+				operation = def
+			}
+		}
+	}
+	if operation == nil || operation.Operation != ast.OperationTypeQuery {
+		return 0, 0, nil
+	}
+
+	for _, definition := range operation.VariableDefinitions {
+		if definition.DefaultValue != nil {
+			c.variables[definition.Variable.Name.Value] = definition.DefaultValue.GetValue()
+		}
+	}
+	for name, value := range variables {
+		c.variables[name] = value
+	}
+
+	return c.selectionSet(schema.QueryType(), operation.SelectionSet)
+}
+
+func (c *queryCost) selectionSet(parent *graphql.Object, set *ast.SelectionSet) (depth, cost int, err error) {
+	if set == nil {
+		return 0, 0, nil
+	}
+	for _, selection := range set.Selections {
+		var d, n int
+		switch s := selection.(type) {
+		case *ast.Field:
+			d, n, err = c.field(parent, s)
+		case *ast.InlineFragment:
+			d, n, err = c.selectionSet(c.conditionType(parent, s.TypeCondition), s.SelectionSet)
+		case *ast.FragmentSpread:
+			if fragment, ok := c.fragments[s.Name.Value]; ok {
+				d, n, err = c.selectionSet(c.conditionType(parent, fragment.TypeCondition), fragment.SelectionSet)
+			}
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+		if d > depth {
+			depth = d
+		}
+		cost += n
+	}
+	return depth, cost, nil
+}
+
+func (c *queryCost) field(parent *graphql.Object, field *ast.Field) (depth, cost int, err error) {
+	if parent == nil || strings.HasPrefix(field.Name.Value, "__") {
+		return 0, 0, nil
+	}
+	definition, ok := parent.Fields()[field.Name.Value]
+	if !ok {
+		return 0, 0, nil
+	}
+
+	multiplier, err := c.multiplier(definition, field)
+	if err != nil {
+		return 0, 0, err
+	}
+	child, _ := graphql.GetNamed(definition.Type).(*graphql.Object)
+	childDepth, childCost, err := c.selectionSet(child, field.SelectionSet)
+	if err != nil {
+		return 0, 0, err
+	}
+	return 1 + childDepth, 1 + multiplier*childCost, nil
+}
+
+func (c *queryCost) conditionType(parent *graphql.Object, condition *ast.Named) *graphql.Object {
+	if condition == nil {
+		return parent
+	}
+	object, _ := c.schema.Type(condition.Name.Value).(*graphql.Object)
+	return object
+}
+
+func (c *queryCost) multiplier(definition *graphql.FieldDefinition, field *ast.Field) (int, error) {
+	if !isList(definition.Type) {
+		return 1, nil
+	}
+	check, ok := listLimits[graphql.GetNamed(definition.Type).String()]
+	if !ok {
+		return unboundedListSize, nil
+	}
+
+	// Sem o argumento (ou com variável ausente), vale o padrão do campo.
+	limit := 0
+	for _, arg := range definition.Args {
+		if arg.Name() == "limit" {
+			limit, _ = arg.DefaultValue.(int)
+		}
+	}
+	for _, arg := range field.Arguments {
+		if arg.Name.Value == "limit" {
+			if value, ok := c.intValue(arg.Value); ok {
+				limit = value
+			}
+		}
+	}
+	return check(limit)
+}
+
+func (c *queryCost) intValue(value ast.Value) (int, bool) {
+	switch v := value.(type) {
+	case *ast.IntValue:
+		n, err := strconv.Atoi(v.Value)
+		return n, err == nil
+	case *ast.Variable:
+		return toInt(c.variables[v.Name.Value])
+	}
+	return 0, false
+}
+
+// toInt aceita os tipos que chegam em variáveis: números do JSON (float64) e
+// literais do documento (string, em valores padrão de variáveis).
+func toInt(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case float64:
+		return int(v), true
+	case string:
+		n, err := strconv.Atoi(v)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+func isList(t graphql.Type) bool {
+	for {
+		switch wrapped := t.(type) {
+		case *graphql.List:
+			return true
+		case *graphql.NonNull:
+			t = wrapped.OfType
+		default:
+			return false
+		}
+	}
+}
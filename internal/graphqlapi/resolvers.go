@@ -0,0 +1,198 @@
+package graphqlapi
+
+import (
+	"context"
+
+	"github.com/graphql-go/graphql"
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+)
+
+type resolver struct {
+	Services
+}
+
+func (r *resolver) sites(p graphql.ResolveParams) (interface{}, error) {
+	principal := principalFrom(p.Context)
+	sites, err := r.OrganizationService.ListSites(p.Context, principal.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	if len(principal.Sites) == 0 {
+		return sites, nil
+	}
+	visible := []domain.Site{}
+	for _, site := range sites {
+		if siteAllowed(principal.Sites, site.Domain) {
+			visible = append(visible, site)
+		}
+	}
+	return visible, nil
+}
+
+func (r *resolver) campaigns(p graphql.ResolveParams) (interface{}, error) {
+	filter, err := restrictSites(p.Context, filterArg(p.Args, domain.AnalyticsFilter{}))
+	if err != nil {
+		return nil, err
+	}
+	campaigns, err := r.AnalyticsService.ListCampaigns(p.Context, principalFrom(p.Context).OrgID, filter, pageArgs(p.Args))
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]campaignNode, len(campaigns))
+	for i, campaign := range campaigns {
+		nodes[i] = campaignNode{CampaignSummary: campaign, filter: filter}
+	}
+	return nodes, nil
+}
+
+func (r *resolver) contacts(p graphql.ResolveParams) (interface{}, error) {
+	if err := requireScope(p.Context, domain.ScopeEventsRead); err != nil {
+		return nil, err
+	}
+	filter, err := restrictSites(p.Context, filterArg(p.Args, domain.AnalyticsFilter{}))
+	if err != nil {
+		return nil, err
+	}
+	contacts, err := r.AnalyticsService.ListContacts(p.Context, principalFrom(p.Context).OrgID, filter, pageArgs(p.Args))
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]contactNode, len(contacts))
+	for i, contact := range contacts {
+		nodes[i] = contactNode{ContactSummary: contact, filter: filter}
+	}
+	return nodes, nil
+}
+
+func (r *resolver) events(p graphql.ResolveParams) (interface{}, error) {
+	return r.listEvents(p, filterArg(p.Args, domain.AnalyticsFilter{}))
+}
+
+func (r *resolver) campaignEvents(p graphql.ResolveParams) (interface{}, error) {
+	campaign := p.Source.(campaignNode)
+	filter := filterArg(p.Args, campaign.filter)
+	filter.CampaignID = campaign.ID
+	return r.listEvents(p, filter)
+}
+
+func (r *resolver) contactEvents(p graphql.ResolveParams) (interface{}, error) {
+	contact := p.Source.(contactNode)
+	filter := filterArg(p.Args, contact.filter)
+	filter.Email = contact.Email
+	return r.listEvents(p, filter)
+}
+
+// listEvents recebe o filtro já combinado com o do objeto pai.
+func (r *resolver) listEvents(p graphql.ResolveParams, filter domain.AnalyticsFilter) (interface{}, error) {
+	if err := requireScope(p.Context, domain.ScopeEventsRead); err != nil {
+		return nil, err
+	}
+	filter, err := restrictSites(p.Context, filter)
+	if err != nil {
+		return nil, err
+	}
+	return r.AnalyticsService.ListEvents(p.Context, principalFrom(p.Context).OrgID, filter, pageArgs(p.Args))
+}
+
+func (r *resolver) stats(p graphql.ResolveParams) (interface{}, error) {
+	return r.aggregate(p, filterArg(p.Args, domain.AnalyticsFilter{}))
+}
+
+func (r *resolver) siteStats(p graphql.ResolveParams) (interface{}, error) {
+	filter := filterArg(p.Args, domain.AnalyticsFilter{})
+	filter.Sites = []string{p.Source.(domain.Site).Domain}
+	return r.aggregate(p, filter)
+}
+
+func (r *resolver) campaignStats(p graphql.ResolveParams) (interface{}, error) {
+	campaign := p.Source.(campaignNode)
+	filter := filterArg(p.Args, campaign.filter)
+	filter.CampaignID = campaign.ID
+	return r.aggregate(p, filter)
+}
+
+func (r *resolver) aggregate(p graphql.ResolveParams, filter domain.AnalyticsFilter) (interface{}, error) {
+	filter, err := restrictSites(p.Context, filter)
+	if err != nil {
+		return nil, err
+	}
+	query := domain.StatsQuery{Filter: filter}
+	query.Granularity, _ = p.Args["granularity"].(string)
+	query.Limit, _ = p.Args["limit"].(int)
+	query.GroupBy = stringsArg(p.Args["groupBy"])
+	return r.AnalyticsService.AggregateStats(p.Context, principalFrom(p.Context).OrgID, query)
+}
+
+// filterArg lê o argumento filter; sem ele, vale fallback (o filtro do objeto pai).
+func filterArg(args map[string]interface{}, fallback domain.AnalyticsFilter) domain.AnalyticsFilter {
+	raw, ok := args["filter"].(map[string]interface{})
+	if !ok {
+		return fallback
+	}
+	var filter domain.AnalyticsFilter
+	filter.StartDate, _ = raw["startDate"].(string)
+	filter.EndDate, _ = raw["endDate"].(string)
+	filter.Sites = stringsArg(raw["sites"])
+	filter.EventTypes = stringsArg(raw["eventTypes"])
+	filter.CampaignID, _ = raw["campaignId"].(string)
+	filter.Email, _ = raw["email"].(string)
+	return filter
+}
+
+func pageArgs(args map[string]interface{}) domain.AnalyticsPage {
+	var page domain.AnalyticsPage
+	page.Limit, _ = args["limit"].(int)
+	page.Offset, _ = args["offset"].(int)
+	return page
+}
+
+func stringsArg(value interface{}) []string {
+	items, _ := value.([]interface{})
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			values = append(values, s)
+		}
+	}
+	return values
+}
+
+// requireScope recusa chaves de API sem o escopo. Estatísticas agregadas só
+// pedem stats:read, exigido na rota; eventos e destinatários expõem emails.
+func requireScope(ctx context.Context, scope string) error {
+	if principalFrom(ctx).HasScope(scope) {
+		return nil
+	}
+	return &requestError{code: domain.ProblemInsufficientScope, key: "auth.insufficient_scope", args: []interface{}{scope}}
+}
+
+// restrictSites aplica os sites permitidos da credencial: sem sites no
+// filtro, a consulta fica limitada a eles; sites de fora são recusados.
+func restrictSites(ctx context.Context, filter domain.AnalyticsFilter) (domain.AnalyticsFilter, error) {
+	allowed := principalFrom(ctx).Sites
+	if len(allowed) == 0 {
+		return filter, nil
+	}
+	if len(filter.Sites) == 0 {
+		filter.Sites = allowed
+		return filter, nil
+	}
+	for _, site := range filter.Sites {
+		if !siteAllowed(allowed, site) {
+			return filter, &requestError{code: domain.ProblemSiteForbidden, key: "event.site_forbidden", args: []interface{}{site}}
+		}
+	}
+	return filter, nil
+}
+
+func siteAllowed(sites []string, site string) bool {
+	if len(sites) == 0 {
+		return true
+	}
+	for _, allowed := range sites {
+		if allowed == site {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,242 @@
+// Package graphqlapi expõe as consultas do painel em GraphQL (POST /graphql):
+// sites, campanhas, contatos, eventos e estatísticas agregadas. É só leitura;
+// os resolvers usam AnalyticsService e OrganizationService, com as mesmas
+// regras de organização e de sites permitidos da API REST.
+package graphqlapi
+
+import (
+	"context"
+
+	"github.com/graphql-go/graphql"
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/service"
+)
+
+// Services reúne os serviços usados pelos resolvers.
+type Services struct {
+	AnalyticsService    service.AnalyticsService
+	OrganizationService service.OrganizationService
+}
+
+// campaignNode e contactNode guardam o filtro da consulta que os listou, usado
+// pelos campos aninhados (stats, events) quando eles não recebem filtro próprio.
+type campaignNode struct {
+	domain.CampaignSummary
+	filter domain.AnalyticsFilter
+}
+
+type contactNode struct {
+	domain.ContactSummary
+	filter domain.AnalyticsFilter
+}
+
+var (
+	nonNullString = graphql.NewNonNull(graphql.String)
+	nonNullInt    = graphql.NewNonNull(graphql.Int)
+	nonNullTime   = graphql.NewNonNull(graphql.DateTime)
+	stringList    = graphql.NewList(nonNullString)
+)
+
+var granularityEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "Granularity",
+	Description: "Tamanho do período de cada linha das estatísticas. Semanas começam na segunda-feira.",
+	Values: graphql.EnumValueConfigMap{
+		"DAY":   &graphql.EnumValueConfig{Value: domain.GranularityDay},
+		"WEEK":  &graphql.EnumValueConfig{Value: domain.GranularityWeek},
+		"MONTH": &graphql.EnumValueConfig{Value: domain.GranularityMonth},
+	},
+})
+
+var dimensionEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "StatsDimension",
+	Description: "Dimensões de agrupamento das estatísticas, além do período.",
+	Values: graphql.EnumValueConfigMap{
+		"SITE":       &graphql.EnumValueConfig{Value: domain.DimensionSite},
+		"EVENT_TYPE": &graphql.EnumValueConfig{Value: domain.DimensionEventType},
+		"CAMPAIGN":   &graphql.EnumValueConfig{Value: domain.DimensionCampaign},
+	},
+})
+
+var filterInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "EventFilter",
+	Description: "Sem datas, vale o período dos últimos 30 dias; o período máximo é de 366 dias.",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"startDate":  &graphql.InputObjectFieldConfig{Type: graphql.String, Description: "Data inicial, AAAA-MM-DD (inclusiva)"},
+		"endDate":    &graphql.InputObjectFieldConfig{Type: graphql.String, Description: "Data final, AAAA-MM-DD (inclusiva)"},
+		"sites":      &graphql.InputObjectFieldConfig{Type: stringList},
+		"eventTypes": &graphql.InputObjectFieldConfig{Type: stringList},
+		"campaignId": &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"email":      &graphql.InputObjectFieldConfig{Type: graphql.String},
+	},
+})
+
+func listArgs() graphql.FieldConfigArgument {
+	return graphql.FieldConfigArgument{
+		"filter": &graphql.ArgumentConfig{Type: filterInput},
+		"limit":  &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: service.DefaultAnalyticsLimit},
+		"offset": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 0},
+	}
+}
+
+func statsArgs() graphql.FieldConfigArgument {
+	return graphql.FieldConfigArgument{
+		"filter":      &graphql.ArgumentConfig{Type: filterInput},
+		"granularity": &graphql.ArgumentConfig{Type: granularityEnum, DefaultValue: domain.GranularityDay},
+		"groupBy":     &graphql.ArgumentConfig{Type: graphql.NewList(graphql.NewNonNull(dimensionEnum))},
+		"limit":       &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: service.DefaultStatsLimit},
+	}
+}
+
+// prop cria um campo lido do objeto pai, que precisa ser do tipo S.
+func prop[S any](t graphql.Output, get func(S) interface{}) *graphql.Field {
+	return &graphql.Field{
+		Type: t,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return get(p.Source.(S)), nil
+		},
+	}
+}
+
+// optional devolve null para strings vazias.
+func optional(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// NewSchema monta o schema GraphQL; erros aqui são de programação.
+func NewSchema(services Services) (graphql.Schema, error) {
+	r := &resolver{Services: services}
+
+	eventType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Event",
+		Fields: graphql.Fields{
+			"id":         prop(nonNullString, func(e domain.StoredEvent) interface{} { return e.ID }),
+			"type":       prop(nonNullString, func(e domain.StoredEvent) interface{} { return e.Type }),
+			"email":      prop(nonNullString, func(e domain.StoredEvent) interface{} { return e.Email }),
+			"site":       prop(nonNullString, func(e domain.StoredEvent) interface{} { return e.Site }),
+			"timestamp":  prop(nonNullTime, func(e domain.StoredEvent) interface{} { return e.Timestamp }),
+			"campaignId": prop(graphql.String, func(e domain.StoredEvent) interface{} { return optional(e.CampaignID) }),
+			"subject":    prop(graphql.String, func(e domain.StoredEvent) interface{} { return optional(e.Subject) }),
+		},
+	})
+
+	bucketType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "StatsBucket",
+		Description: "Uma linha das estatísticas. As dimensões fora de groupBy vêm nulas.",
+		Fields: graphql.Fields{
+			"period":       prop(nonNullString, func(b domain.StatsBucket) interface{} { return b.Period }),
+			"site":         prop(graphql.String, func(b domain.StatsBucket) interface{} { return optional(b.Site) }),
+			"eventType":    prop(graphql.String, func(b domain.StatsBucket) interface{} { return optional(b.EventType) }),
+			"campaignId":   prop(graphql.String, func(b domain.StatsBucket) interface{} { return optional(b.CampaignID) }),
+			"count":        prop(nonNullInt, func(b domain.StatsBucket) interface{} { return b.Count }),
+			"uniqueEmails": prop(nonNullInt, func(b domain.StatsBucket) interface{} { return b.UniqueEmails }),
+		},
+	})
+	bucketList := graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(bucketType)))
+	eventList := graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(eventType)))
+
+	siteType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Site",
+		Fields: graphql.Fields{
+			"id":        prop(nonNullInt, func(s domain.Site) interface{} { return s.ID }),
+			"domain":    prop(nonNullString, func(s domain.Site) interface{} { return s.Domain }),
+			"createdAt": prop(nonNullTime, func(s domain.Site) interface{} { return s.CreatedAt }),
+			"stats":     &graphql.Field{Type: bucketList, Args: statsArgs(), Resolve: r.siteStats},
+		},
+	})
+
+	campaignType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Campaign",
+		Fields: graphql.Fields{
+			"id":           prop(nonNullString, func(c campaignNode) interface{} { return c.ID }),
+			"totalEvents":  prop(nonNullInt, func(c campaignNode) interface{} { return c.TotalEvents }),
+			"uniqueEmails": prop(nonNullInt, func(c campaignNode) interface{} { return c.UniqueEmails }),
+			"firstSeen":    prop(nonNullTime, func(c campaignNode) interface{} { return c.FirstSeen }),
+			"lastSeen":     prop(nonNullTime, func(c campaignNode) interface{} { return c.LastSeen }),
+			"stats":        &graphql.Field{Type: bucketList, Args: statsArgs(), Resolve: r.campaignStats},
+			"events":       &graphql.Field{Type: eventList, Args: listArgs(), Resolve: r.campaignEvents},
+		},
+	})
+
+	contactType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Contact",
+		Fields: graphql.Fields{
+			"email":       prop(nonNullString, func(c contactNode) interface{} { return c.Email }),
+			"totalEvents": prop(nonNullInt, func(c contactNode) interface{} { return c.TotalEvents }),
+			"firstSeen":   prop(nonNullTime, func(c contactNode) interface{} { return c.FirstSeen }),
+			"lastSeen":    prop(nonNullTime, func(c contactNode) interface{} { return c.LastSeen }),
+			"events":      &graphql.Field{Type: eventList, Args: listArgs(), Resolve: r.contactEvents},
+		},
+	})
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"sites": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(siteType))),
+				Description: "Sites da organização visíveis para a credencial.",
+				Resolve:     r.sites,
+			},
+			"campaigns": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(campaignType))),
+				Description: "Campanhas com eventos no período, da mais recente para a mais antiga.",
+				Args:        listArgs(),
+				Resolve:     r.campaigns,
+			},
+			"contacts": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(contactType))),
+				Description: "Destinatários com eventos no período, do mais recente para o mais antigo.",
+				Args:        listArgs(),
+				Resolve:     r.contacts,
+			},
+			"events": &graphql.Field{
+				Type:        eventList,
+				Description: "Eventos do período, do mais recente para o mais antigo.",
+				Args:        listArgs(),
+				Resolve:     r.events,
+			},
+			"stats": &graphql.Field{
+				Type:        bucketList,
+				Description: "Contagens por período e pelas dimensões de groupBy.",
+				Args:        statsArgs(),
+				Resolve:     r.stats,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: queryType})
+}
+
+// Principal é a credencial da requisição: a organização consultada e, para
+// chaves de API e remetentes, os sites permitidos (vazio libera todos) e os
+// escopos. Scopes nil é uma sessão de usuário, sem restrição de escopo.
+type Principal struct {
+	OrgID  int
+	Sites  []string
+	Scopes []string
+}
+
+func (p Principal) HasScope(scope string) bool {
+	if p.Scopes == nil {
+		return true
+	}
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type principalKey struct{}
+
+func withPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+func principalFrom(ctx context.Context) Principal {
+	p, _ := ctx.Value(principalKey{}).(Principal)
+	return p
+}
@@ -0,0 +1,134 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/i18n"
+	"github.com/nathaliaoliveira/goapp/internal/service"
+	eventsv1 "github.com/nathaliaoliveira/goapp/proto/events/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+// methodScopes é o escopo exigido de chaves de API em cada método, como nas
+// rotas REST equivalentes. Métodos fora do mapa não aceitam chaves de API.
+var methodScopes = map[string]string{
+	eventsv1.EventService_IngestEvents_FullMethodName:  domain.ScopeEventsWrite,
+	eventsv1.EventService_GetDailyStats_FullMethodName: domain.ScopeStatsRead,
+}
+
+// principal é quem fez a chamada. Sites vazio libera todos os sites da
+// organização; keyID é a chave de API usada, ou zero em sessões.
+type principal struct {
+	actor domain.Actor
+	sites []string
+	keyID int
+}
+
+type principalKey struct{}
+
+func principalFrom(ctx context.Context) principal {
+	p, _ := ctx.Value(principalKey{}).(principal)
+	return p
+}
+
+type authenticator struct {
+	jwtSecret     []byte
+	userService   service.UserService
+	apiKeyService service.APIKeyService
+}
+
+func (a *authenticator) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := a.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (a *authenticator) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := a.authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+}
+
+// authenticate aceita nos metadados "authorization: Bearer <jwt>",
+// "authorization: ApiKey <chave>" ou "x-api-key: <chave>". Também escolhe o
+// idioma das mensagens de erro pelo "accept-language".
+func (a *authenticator) authenticate(ctx context.Context, method string) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = i18n.WithLanguage(ctx, i18n.Negotiate(first(md, "accept-language")))
+
+	authorization := first(md, "authorization")
+	rawKey := first(md, "x-api-key")
+	if rawKey == "" && strings.HasPrefix(authorization, "ApiKey ") {
+		rawKey = strings.TrimSpace(strings.TrimPrefix(authorization, "ApiKey "))
+	}
+
+	if rawKey != "" {
+		scope, ok := methodScopes[method]
+		if !ok || a.apiKeyService == nil {
+			return nil, problemStatus(ctx, codes.PermissionDenied, domain.ProblemAPIKeyNotAllowed, "auth.api_key_not_allowed")
+		}
+		key, err := a.apiKeyService.Authenticate(ctx, rawKey)
+		if err != nil {
+			slog.WarnContext(ctx, "Chave de API rejeitada", "method", method, "error", err)
+			return nil, toStatus(ctx, err)
+		}
+		if !key.HasScope(scope) {
+			slog.WarnContext(ctx, "Chave de API sem escopo", "key_prefix", key.Prefix, "scope", scope, "method", method)
+			return nil, problemStatus(ctx, codes.PermissionDenied, domain.ProblemInsufficientScope, "auth.insufficient_scope", scope)
+		}
+		return context.WithValue(ctx, principalKey{}, principal{
+			actor: domain.Actor{UserID: key.UserID, OrgID: key.OrgID},
+			sites: key.Sites,
+			keyID: key.ID,
+		}), nil
+	}
+
+	if authorization == "" {
+		return nil, problemStatus(ctx, codes.Unauthenticated, domain.ProblemUnauthenticated, "auth.token_missing")
+	}
+	token, ok := strings.CutPrefix(authorization, "Bearer ")
+	if !ok {
+		return nil, problemStatus(ctx, codes.Unauthenticated, domain.ProblemUnauthenticated, "auth.token_format")
+	}
+	actor, err := service.ParseSessionToken(a.jwtSecret, strings.TrimSpace(token))
+	if err != nil {
+		slog.WarnContext(ctx, "Token inválido", "method", method, "error", err)
+		return nil, problemStatus(ctx, codes.Unauthenticated, domain.ProblemUnauthenticated, "auth.token_invalid")
+	}
+	if err := a.userService.CheckSession(ctx, actor.UserID); err != nil {
+		var authErr *service.AuthenticationError
+		if !errors.As(err, &authErr) {
+			return nil, toStatus(ctx, err)
+		}
+		slog.WarnContext(ctx, "Token de usuário removido", "user_id", actor.UserID, "method", method)
+		return nil, problemStatus(ctx, codes.Unauthenticated, domain.ProblemUnauthenticated, "auth.token_invalid")
+	}
+	return context.WithValue(ctx, principalKey{}, principal{actor: actor}), nil
+}
+
+func first(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// contextStream troca o contexto do stream pelo contexto autenticado.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,29 @@
+package grpcserver
+
+import (
+	"time"
+
+	"github.com/nathaliaoliveira/goapp/internal/env"
+)
+
+// DefaultBatchSize é quantos eventos do fluxo são gravados de cada vez.
+const DefaultBatchSize = 500
+
+type Config struct {
+	Addr string
+
+	// BatchSize limita os eventos acumulados antes de chamar o EventService.
+	BatchSize int
+
+	// ShutdownTimeout é o prazo para as chamadas em andamento terminarem ao
+	// desligar; depois dele as conexões são fechadas.
+	ShutdownTimeout time.Duration
+}
+
+func NewConfig() *Config {
+	return &Config{
+		Addr:            ":" + env.String("GRPC_PORT", "9090"),
+		BatchSize:       env.Int("GRPC_BATCH_SIZE", DefaultBatchSize),
+		ShutdownTimeout: env.Duration("SHUTDOWN_TIMEOUT", 30*time.Second),
+	}
+}
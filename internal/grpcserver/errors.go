@@ -0,0 +1,71 @@
+package grpcserver
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/handler"
+	"github.com/nathaliaoliveira/goapp/internal/i18n"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// errorDomain identifica a origem dos códigos em ErrorInfo.
+const errorDomain = "goapp"
+
+// httpCodes traduz o status HTTP da classificação da API REST.
+var httpCodes = map[int]codes.Code{
+	http.StatusBadRequest:         codes.InvalidArgument,
+	http.StatusUnauthorized:       codes.Unauthenticated,
+	http.StatusForbidden:          codes.PermissionDenied,
+	http.StatusNotFound:           codes.NotFound,
+	http.StatusConflict:           codes.AlreadyExists,
+	http.StatusTooManyRequests:    codes.ResourceExhausted,
+	http.StatusServiceUnavailable: codes.Unavailable,
+	http.StatusGatewayTimeout:     codes.DeadlineExceeded,
+	499:                           codes.Canceled,
+}
+
+// toStatus converte erros de serviço com a mesma classificação da API REST.
+// O código estável da API (ex.: "validation_failed") vai em ErrorInfo.Reason
+// e as falhas por campo em BadRequest.
+func toStatus(ctx context.Context, err error) error {
+	problem := handler.ProblemFor(ctx, err)
+	code, ok := httpCodes[problem.Status]
+	if !ok {
+		code = codes.Internal
+	}
+	return newStatus(code, problem)
+}
+
+// problemStatus é o equivalente de writeProblem para erros detectados aqui.
+func problemStatus(ctx context.Context, code codes.Code, problemCode, key string, args ...interface{}) error {
+	return newStatus(code, domain.Problem{Code: problemCode, Detail: i18n.Translate(ctx, key, args...)})
+}
+
+func newStatus(code codes.Code, problem domain.Problem) error {
+	message := problem.Detail
+	if message == "" {
+		message = problem.Title
+	}
+	st := status.New(code, message)
+
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: problem.Code, Domain: errorDomain}}
+	if len(problem.Errors) > 0 {
+		badRequest := &errdetails.BadRequest{}
+		for _, fieldErr := range problem.Errors {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       fieldErr.Field,
+				Description: fieldErr.Message,
+			})
+		}
+		details = append(details, badRequest)
+	}
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
@@ -0,0 +1,155 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/service"
+	eventsv1 "github.com/nathaliaoliveira/goapp/proto/events/v1"
+	"google.golang.org/grpc/codes"
+)
+
+// maxResponseEvents limita os resultados por evento na resposta de
+// IngestEvents, para que ela não cresça com o fluxo nem passe do limite de
+// mensagem do gRPC (4 MB); os contadores cobrem todos os eventos.
+const maxResponseEvents = 1000
+
+type eventServer struct {
+	eventsv1.UnimplementedEventServiceServer
+	eventService service.EventService
+	batchSize    int
+}
+
+// IngestEvents grava os eventos em lotes de batchSize conforme chegam, para
+// não acumular o fluxo inteiro em memória, e responde com o resumo ao fim:
+// os contadores de todo o fluxo e o resultado dos primeiros maxResponseEvents.
+func (s *eventServer) IngestEvents(stream eventsv1.EventService_IngestEventsServer) error {
+	ctx := stream.Context()
+	p := principalFrom(ctx)
+
+	total := &eventsv1.IngestEventsResponse{}
+	batch := make([]domain.EmailEvent, 0, s.batchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		result, err := s.eventService.ProcessEvents(ctx, p.actor.OrgID, batch)
+		if err != nil {
+			return toStatus(ctx, err)
+		}
+		total.Processed += int32(result.Processed)
+		total.Duplicates += int32(result.Duplicates)
+		total.Errors += int32(result.Errors)
+		for _, event := range result.Events {
+			if len(total.Events) >= maxResponseEvents {
+				break
+			}
+			total.Events = append(total.Events, processedToProto(event))
+		}
+		batch = make([]domain.EmailEvent, 0, s.batchSize)
+		return nil
+	}
+
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			if err := flush(); err != nil {
+				return err
+			}
+			return stream.SendAndClose(total)
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, event := range req.GetEvents() {
+			if !siteAllowed(p.sites, event.GetSite()) {
+				return problemStatus(ctx, codes.PermissionDenied, domain.ProblemSiteForbidden, "event.site_forbidden", event.GetSite())
+			}
+			batch = append(batch, eventFromProto(event))
+			if len(batch) >= s.batchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+func (s *eventServer) GetDailyStats(ctx context.Context, req *eventsv1.GetDailyStatsRequest) (*eventsv1.GetDailyStatsResponse, error) {
+	p := principalFrom(ctx)
+
+	site := req.GetSite()
+	if len(p.sites) > 0 {
+		if site == "" && len(p.sites) == 1 {
+			site = p.sites[0]
+		}
+		if site == "" || !siteAllowed(p.sites, site) {
+			return nil, problemStatus(ctx, codes.PermissionDenied, domain.ProblemSiteForbidden, "event.site_required")
+		}
+	}
+
+	stats, err := s.eventService.GetDailyStats(ctx, p.actor.OrgID, req.GetStartDate(), req.GetEndDate(), site)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return statsToProto(stats), nil
+}
+
+func siteAllowed(sites []string, site string) bool {
+	if len(sites) == 0 {
+		return true
+	}
+	for _, allowed := range sites {
+		if allowed == site {
+			return true
+		}
+	}
+	return false
+}
+
+func eventFromProto(e *eventsv1.EmailEvent) domain.EmailEvent {
+	event := domain.EmailEvent{
+		Type:       e.GetType(),
+		Email:      e.GetEmail(),
+		Site:       e.GetSite(),
+		Timestamp:  e.GetTimestamp(),
+		CampaignID: e.GetCampaignId(),
+		Subject:    e.GetSubject(),
+		IPAddress:  e.GetIpAddress(),
+		UserAgent:  e.GetUserAgent(),
+	}
+	if e.GetMetadata() != nil {
+		event.Metadata = e.GetMetadata().AsMap()
+	}
+	return event
+}
+
+func processedToProto(e domain.ProcessedEvent) *eventsv1.ProcessedEvent {
+	return &eventsv1.ProcessedEvent{Id: e.ID, Type: e.Type, Email: e.Email, Site: e.Site, Status: e.Status}
+}
+
+func statsToProto(stats *domain.StatsResponse) *eventsv1.GetDailyStatsResponse {
+	resp := &eventsv1.GetDailyStatsResponse{
+		Period:     stats.Period,
+		SiteFilter: stats.SiteFilter,
+		TotalDays:  int32(stats.TotalDays),
+		TotalSites: int32(stats.TotalSites),
+	}
+	for _, day := range stats.Stats {
+		daily := &eventsv1.DailyStats{
+			Date:              day.Date,
+			Site:              day.Site,
+			TotalEvents:       int32(day.TotalEvents),
+			TotalUniqueEmails: int32(day.TotalUniqueEmails),
+			Events:            map[string]*eventsv1.EventStats{},
+		}
+		for eventType, s := range day.Events {
+			daily.Events[eventType] = &eventsv1.EventStats{Count: int32(s.Count), UniqueEmails: int32(s.UniqueEmails)}
+		}
+		resp.Stats = append(resp.Stats, daily)
+	}
+	return resp
+}
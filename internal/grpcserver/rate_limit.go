@@ -0,0 +1,124 @@
+package grpcserver
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/i18n"
+	"github.com/nathaliaoliveira/goapp/internal/service"
+	eventsv1 "github.com/nathaliaoliveira/goapp/proto/events/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// methodGroups é o grupo do limitador de cada método, o mesmo da rota REST
+// equivalente. Métodos fora do mapa não são limitados.
+var methodGroups = map[string]string{
+	eventsv1.EventService_IngestEvents_FullMethodName:  service.RateLimitGroupEvents,
+	eventsv1.EventService_GetDailyStats_FullMethodName: service.RateLimitGroupAPI,
+}
+
+// rateLimits aplica o service.RateLimiter da API REST às chamadas gRPC: por IP
+// antes da autenticação e por chave de API ou usuário depois dela. Nos
+// streams, cada mensagem recebida conta como uma requisição.
+type rateLimits struct {
+	limiter *service.RateLimiter
+}
+
+func (l *rateLimits) unaryIP(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := l.allow(ctx, info.FullMethod, domain.PrincipalIP, peerIP(ctx)); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (l *rateLimits) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	kind, id := rateLimitPrincipal(principalFrom(ctx))
+	if err := l.allow(ctx, info.FullMethod, kind, id); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (l *rateLimits) streamIP(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := l.allow(ss.Context(), info.FullMethod, domain.PrincipalIP, peerIP(ss.Context())); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+func (l *rateLimits) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	kind, id := rateLimitPrincipal(principalFrom(ss.Context()))
+	return handler(srv, &limitedStream{ServerStream: ss, limits: l, method: info.FullMethod, kind: kind, id: id})
+}
+
+func (l *rateLimits) allow(ctx context.Context, method, kind, id string) error {
+	group, ok := methodGroups[method]
+	if !ok || l.limiter == nil {
+		return nil
+	}
+	result := l.limiter.Allow(ctx, group, kind, id)
+	if result == nil || result.Allowed {
+		return nil
+	}
+	slog.WarnContext(ctx, "Limite de requisições excedido", "group", group, "principal", kind, "method", method)
+	return rateLimitedStatus(ctx, result.RetryAfter)
+}
+
+// limitedStream consome uma ficha do principal a cada mensagem recebida.
+type limitedStream struct {
+	grpc.ServerStream
+	limits *rateLimits
+	method string
+	kind   string
+	id     string
+}
+
+func (s *limitedStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return s.limits.allow(s.Context(), s.method, s.kind, s.id)
+}
+
+func rateLimitPrincipal(p principal) (kind, id string) {
+	if p.keyID != 0 {
+		return domain.PrincipalAPIKey, strconv.Itoa(p.keyID)
+	}
+	return domain.PrincipalUser, strconv.Itoa(p.actor.UserID)
+}
+
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// rateLimitedStatus é o equivalente do 429 da API REST: ResourceExhausted com
+// ErrorInfo "rate_limited" e o tempo de espera em RetryInfo.
+func rateLimitedStatus(ctx context.Context, retryAfter time.Duration) error {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	st := status.New(codes.ResourceExhausted, i18n.Translate(ctx, "request.rate_limited", seconds))
+	if withDetails, err := st.WithDetails(
+		&errdetails.ErrorInfo{Reason: domain.ProblemRateLimited, Domain: errorDomain},
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(time.Duration(seconds) * time.Second)},
+	); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
@@ -0,0 +1,95 @@
+// Package grpcserver expõe a ingestão e a consulta de eventos por gRPC, com os
+// mesmos serviços e a mesma autenticação da API REST, em uma porta própria.
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"time"
+
+	"github.com/nathaliaoliveira/goapp/internal/service"
+	eventsv1 "github.com/nathaliaoliveira/goapp/proto/events/v1"
+	"google.golang.org/grpc"
+)
+
+// Services reúne as dependências da API gRPC.
+type Services struct {
+	JWTSecret     []byte
+	UserService   service.UserService
+	EventService  service.EventService
+	APIKeyService service.APIKeyService
+	// Limites de requisições, os mesmos da API REST. Opcional: sem ele as
+	// chamadas não são limitadas.
+	RateLimiter *service.RateLimiter
+}
+
+type Server struct {
+	grpc            *grpc.Server
+	addr            string
+	shutdownTimeout time.Duration
+}
+
+func New(config *Config, services Services) *Server {
+	auth := &authenticator{jwtSecret: services.JWTSecret, userService: services.UserService, apiKeyService: services.APIKeyService}
+	limits := &rateLimits{limiter: services.RateLimiter}
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(limits.unaryIP, auth.unary, limits.unary),
+		grpc.ChainStreamInterceptor(limits.streamIP, auth.stream, limits.stream),
+	)
+
+	batchSize := config.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	if batchSize > service.MaxEventsPerBatch {
+		batchSize = service.MaxEventsPerBatch
+	}
+	eventsv1.RegisterEventServiceServer(s, &eventServer{eventService: services.EventService, batchSize: batchSize})
+
+	return &Server{grpc: s, addr: config.Addr, shutdownTimeout: config.ShutdownTimeout}
+}
+
+// Run escuta em Config.Addr até ctx terminar e então desliga o servidor.
+func (s *Server) Run(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, ln)
+}
+
+// Serve atende conexões de ln até ctx terminar. Ao desligar, espera as chamadas
+// em andamento por até Config.ShutdownTimeout e então encerra as restantes.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- s.grpc.Serve(ln)
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.InfoContext(ctx, "Desligando servidor gRPC, aguardando chamadas em andamento", "timeout", s.shutdownTimeout)
+	stopped := make(chan struct{})
+	go func() {
+		s.grpc.GracefulStop()
+		close(stopped)
+	}()
+
+	var err error
+	select {
+	case <-stopped:
+	case <-time.After(s.shutdownTimeout):
+		s.grpc.Stop()
+		err = errors.New("chamadas gRPC não finalizadas a tempo")
+	}
+	if serr := <-serveErr; serr != nil && !errors.Is(serr, grpc.ErrServerStopped) {
+		slog.ErrorContext(ctx, "Erro no servidor gRPC", "error", serr)
+	}
+	return err
+}
@@ -0,0 +1,305 @@
+package grpcserver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/repository"
+	"github.com/nathaliaoliveira/goapp/internal/service"
+	eventsv1 "github.com/nathaliaoliveira/goapp/proto/events/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+var testSecret = []byte("test-secret")
+
+type fakeEventService struct {
+	mu      sync.Mutex
+	orgID   int
+	batches [][]domain.EmailEvent
+	err     error
+}
+
+func (s *fakeEventService) ProcessEvents(ctx context.Context, orgID int, events []domain.EmailEvent) (*domain.EventsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.orgID = orgID
+	s.batches = append(s.batches, events)
+	resp := &domain.EventsResponse{Processed: len(events)}
+	for _, event := range events {
+		resp.Events = append(resp.Events, domain.ProcessedEvent{Email: event.Email, Site: event.Site, Status: "processed"})
+	}
+	return resp, nil
+}
+
+func (s *fakeEventService) GetDailyStats(ctx context.Context, orgID int, startDate, endDate, site string) (*domain.StatsResponse, error) {
+	return &domain.StatsResponse{
+		Period:     map[string]string{"start_date": startDate, "end_date": endDate},
+		SiteFilter: site,
+		TotalDays:  1,
+		Stats: []domain.DailyStats{{
+			Date:        startDate,
+			Site:        site,
+			TotalEvents: 3,
+			Events:      map[string]domain.EventStats{"open": {Count: 3, UniqueEmails: 2}},
+		}},
+	}, nil
+}
+
+// fakeUserService trata o usuário 2 como removido.
+type fakeUserService struct {
+	service.UserService
+}
+
+func (fakeUserService) CheckSession(ctx context.Context, userID int) error {
+	if userID == 2 {
+		return &service.AuthenticationError{Key: "auth.token_invalid"}
+	}
+	return nil
+}
+
+type fakeAPIKeyService struct {
+	service.APIKeyService
+	key *domain.APIKey
+}
+
+func (s fakeAPIKeyService) Authenticate(ctx context.Context, rawKey string) (*domain.APIKey, error) {
+	if s.key == nil || rawKey != "gk_valida" {
+		return nil, &service.AuthenticationError{Key: "api_key.invalid"}
+	}
+	return s.key, nil
+}
+
+func newClient(t *testing.T, events service.EventService, key *domain.APIKey) eventsv1.EventServiceClient {
+	t.Helper()
+	return serve(t, Services{
+		JWTSecret:     testSecret,
+		UserService:   fakeUserService{},
+		EventService:  events,
+		APIKeyService: fakeAPIKeyService{key: key},
+	})
+}
+
+func serve(t *testing.T, services Services) eventsv1.EventServiceClient {
+	t.Helper()
+
+	ln := bufconn.Listen(1 << 20)
+	srv := New(&Config{BatchSize: 4, ShutdownTimeout: time.Second}, services)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.Serve(ctx, ln) }()
+	t.Cleanup(func() {
+		cancel()
+		assert.NoError(t, <-done)
+	})
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return ln.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return eventsv1.NewEventServiceClient(conn)
+}
+
+func withToken(t *testing.T, claims jwt.MapClaims) context.Context {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(testSecret)
+	require.NoError(t, err)
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func sessionContext(t *testing.T) context.Context {
+	return withToken(t, jwt.MapClaims{"user_id": 1, "org_id": 9, "email": "a@b.com", "role": domain.RoleUser, "exp": time.Now().Add(time.Hour).Unix()})
+}
+
+func reason(t *testing.T, err error) string {
+	t.Helper()
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info.Reason
+		}
+	}
+	return ""
+}
+
+func event(email, site string) *eventsv1.EmailEvent {
+	return &eventsv1.EmailEvent{Type: "open", Email: email, Site: site, Timestamp: "2026-01-02T10:00:00Z"}
+}
+
+func TestIngestEvents_BatchesStream(t *testing.T) {
+	events := &fakeEventService{}
+	client := newClient(t, events, nil)
+
+	stream, err := client.IngestEvents(sessionContext(t))
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, stream.Send(&eventsv1.IngestEventsRequest{Events: []*eventsv1.EmailEvent{
+			event("a@exemplo.com", "exemplo.com"),
+			event("b@exemplo.com", "exemplo.com"),
+		}}))
+	}
+	resp, err := stream.CloseAndRecv()
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 6, resp.Processed)
+	assert.Len(t, resp.Events, 6)
+	assert.Equal(t, 9, events.orgID)
+	require.Len(t, events.batches, 2)
+	assert.Len(t, events.batches[0], 4)
+	assert.Len(t, events.batches[1], 2)
+}
+
+func TestIngestEvents_CapsPerEventResults(t *testing.T) {
+	client := newClient(t, &fakeEventService{}, nil)
+
+	stream, err := client.IngestEvents(sessionContext(t))
+	require.NoError(t, err)
+	for i := 0; i < 11; i++ {
+		req := &eventsv1.IngestEventsRequest{}
+		for j := 0; j < 100; j++ {
+			req.Events = append(req.Events, event(fmt.Sprintf("%d-%d@exemplo.com", i, j), "exemplo.com"))
+		}
+		require.NoError(t, stream.Send(req))
+	}
+	resp, err := stream.CloseAndRecv()
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 1100, resp.Processed, "os contadores cobrem o fluxo inteiro")
+	assert.Len(t, resp.Events, maxResponseEvents)
+}
+
+func TestIngestEvents_EnforcesAPIKeySites(t *testing.T) {
+	key := &domain.APIKey{UserID: 1, OrgID: 9, Scopes: []string{domain.ScopeEventsWrite}, Sites: []string{"exemplo.com"}}
+	client := newClient(t, &fakeEventService{}, key)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "gk_valida")
+
+	stream, err := client.IngestEvents(ctx)
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&eventsv1.IngestEventsRequest{Events: []*eventsv1.EmailEvent{event("a@outro.com", "outro.com")}}))
+	_, err = stream.CloseAndRecv()
+
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Equal(t, domain.ProblemSiteForbidden, reason(t, err))
+}
+
+func TestAuthentication(t *testing.T) {
+	key := &domain.APIKey{UserID: 1, OrgID: 9, Scopes: []string{domain.ScopeEventsWrite}}
+	client := newClient(t, &fakeEventService{}, key)
+	req := &eventsv1.GetDailyStatsRequest{StartDate: "2026-01-02"}
+
+	_, err := client.GetDailyStats(context.Background(), req)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Equal(t, domain.ProblemUnauthenticated, reason(t, err))
+
+	mfa := withToken(t, jwt.MapClaims{"user_id": 1, "org_id": 9, "purpose": domain.TokenPurposeMFAChallenge, "exp": time.Now().Add(time.Hour).Unix()})
+	_, err = client.GetDailyStats(mfa, req)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	apiKey := metadata.AppendToOutgoingContext(context.Background(), "authorization", "ApiKey gk_valida")
+	_, err = client.GetDailyStats(apiKey, req)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Equal(t, domain.ProblemInsufficientScope, reason(t, err))
+
+	invalid := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "gk_invalida")
+	_, err = client.GetDailyStats(invalid, req)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	deleted := withToken(t, jwt.MapClaims{"user_id": 2, "org_id": 9, "email": "x@b.com", "exp": time.Now().Add(time.Hour).Unix()})
+	_, err = client.GetDailyStats(deleted, req)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Equal(t, domain.ProblemUnauthenticated, reason(t, err))
+}
+
+func TestGetDailyStats(t *testing.T) {
+	client := newClient(t, &fakeEventService{}, nil)
+
+	resp, err := client.GetDailyStats(sessionContext(t), &eventsv1.GetDailyStatsRequest{StartDate: "2026-01-02", Site: "exemplo.com"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "exemplo.com", resp.SiteFilter)
+	assert.Equal(t, "2026-01-02", resp.Period["start_date"])
+	require.Len(t, resp.Stats, 1)
+	assert.EqualValues(t, 2, resp.Stats[0].Events["open"].UniqueEmails)
+}
+
+func TestServiceErrorsUseAPIClassification(t *testing.T) {
+	events := &fakeEventService{err: &service.ValidationError{
+		Key:     "event.empty_batch",
+		Details: []domain.FieldError{{Field: "email", Rule: "required", Message: "Email é obrigatório"}},
+	}}
+	client := newClient(t, events, nil)
+	ctx := metadata.AppendToOutgoingContext(sessionContext(t), "accept-language", "en")
+
+	stream, err := client.IngestEvents(ctx)
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&eventsv1.IngestEventsRequest{Events: []*eventsv1.EmailEvent{event("", "exemplo.com")}}))
+	_, err = stream.CloseAndRecv()
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, "The event list cannot be empty", status.Convert(err).Message())
+	assert.Equal(t, domain.ProblemValidationFailed, reason(t, err))
+	var violations []*errdetails.BadRequest_FieldViolation
+	for _, detail := range status.Convert(err).Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			violations = badRequest.FieldViolations
+		}
+	}
+	require.Len(t, violations, 1)
+	assert.Equal(t, "email", violations[0].Field)
+}
+
+func TestRateLimits(t *testing.T) {
+	key := &domain.APIKey{ID: 7, UserID: 1, OrgID: 9, Scopes: []string{domain.ScopeEventsWrite}}
+	client := serve(t, Services{
+		JWTSecret:     testSecret,
+		UserService:   fakeUserService{},
+		EventService:  &fakeEventService{},
+		APIKeyService: fakeAPIKeyService{key: key},
+		RateLimiter: service.NewRateLimiter(repository.NewMemoryRateLimitStore(), service.RateLimiterConfig{
+			service.RateLimitGroupEvents: {domain.PrincipalAPIKey: {Requests: 2, Period: time.Minute}},
+			service.RateLimitGroupAPI:    {domain.PrincipalIP: {Requests: 2, Period: time.Minute}},
+		}),
+	})
+
+	stream, err := client.IngestEvents(metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "gk_valida"))
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, stream.Send(&eventsv1.IngestEventsRequest{Events: []*eventsv1.EmailEvent{event("a@exemplo.com", "exemplo.com")}}))
+	}
+	_, err = stream.CloseAndRecv()
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), "cada mensagem do stream consome uma ficha da chave")
+	assert.Equal(t, domain.ProblemRateLimited, reason(t, err))
+	var retry *errdetails.RetryInfo
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			retry = info
+		}
+	}
+	require.NotNil(t, retry)
+	assert.Equal(t, 30*time.Second, retry.RetryDelay.AsDuration())
+
+	invalid := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "gk_invalida")
+	req := &eventsv1.GetDailyStatsRequest{StartDate: "2026-01-02"}
+	for i := 0; i < 2; i++ {
+		_, err = client.GetDailyStats(invalid, req)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	}
+	_, err = client.GetDailyStats(invalid, req)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), "credenciais inválidas também consomem o balde do IP")
+}
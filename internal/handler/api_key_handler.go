@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/i18n"
+	"github.com/nathaliaoliveira/goapp/internal/service"
+)
+
+type APIKeyHandler struct {
+	apiKeyService service.APIKeyService
+}
+
+func NewAPIKeyHandler(apiKeyService service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+func (h *APIKeyHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	slog.DebugContext(r.Context(), "Requisição de criação de chave de API recebida", "remote_addr", r.RemoteAddr)
+
+	var req domain.CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		slog.WarnContext(r.Context(), "Dados inválidos para criar chave de API", "error", err)
+		writeProblem(w, r, http.StatusBadRequest, domain.ProblemInvalidBody, "request.invalid_body")
+		return
+	}
+
+	created, err := h.apiKeyService.Create(r.Context(), actorFromRequest(r), req)
+	if err != nil {
+		slog.WarnContext(r.Context(), "Erro ao criar chave de API", "error", err)
+		writeError(w, r, err)
+		return
+	}
+	auditTarget(r, created.APIKey.ID)
+
+	response := domain.Response{
+		Message: i18n.Translate(r.Context(), "api_key.created"),
+		Data:    created,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *APIKeyHandler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(int)
+
+	keys, err := h.apiKeyService.List(r.Context(), userID)
+	if err != nil {
+		slog.WarnContext(r.Context(), "Erro ao listar chaves de API", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	response := domain.Response{
+		Message: i18n.Translate(r.Context(), "api_key.listed"),
+		Data:    keys,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *APIKeyHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, domain.ProblemInvalidID, "request.invalid_id")
+		return
+	}
+
+	userID := r.Context().Value("user_id").(int)
+
+	if err := h.apiKeyService.Revoke(r.Context(), userID, id); err != nil {
+		slog.WarnContext(r.Context(), "Erro ao revogar chave de API", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	response := domain.Response{
+		Message: i18n.Translate(r.Context(), "api_key.revoked"),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
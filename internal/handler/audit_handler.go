@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/i18n"
+	"github.com/nathaliaoliveira/goapp/internal/service"
+)
+
+type AuditHandler struct {
+	auditService service.AuditService
+}
+
+func NewAuditHandler(auditService service.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+func (h *AuditHandler) ListAudit(w http.ResponseWriter, r *http.Request) {
+	filter, err := auditFilterFromQuery(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	result, err := h.auditService.List(r.Context(), actorFromRequest(r), filter)
+	if err != nil {
+		slog.WarnContext(r.Context(), "Erro ao consultar auditoria", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	response := domain.Response{
+		Message: i18n.Translate(r.Context(), "audit.listed"),
+		Data:    result,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *AuditHandler) ExportAudit(w http.ResponseWriter, r *http.Request) {
+	filter, err := auditFilterFromQuery(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = service.AuditExportCSV
+	}
+	contentType := "text/csv; charset=utf-8"
+	if format == service.AuditExportJSON {
+		contentType = "application/json"
+	}
+
+	// Os erros de validação acontecem antes da primeira escrita, então ainda podem virar status HTTP.
+	out := &lazyHeaderWriter{ResponseWriter: w, contentType: contentType,
+		filename: "audit-" + time.Now().UTC().Format("20060102-150405") + "." + format}
+	if err := h.auditService.Export(r.Context(), actorFromRequest(r), filter, format, out); err != nil {
+		slog.WarnContext(r.Context(), "Erro ao exportar auditoria", "error", err)
+		if !out.started {
+			writeError(w, r, err)
+		}
+	}
+}
+
+// auditFilterFromQuery aceita from/to em RFC 3339 ou no formato 2006-01-02.
+func auditFilterFromQuery(r *http.Request) (domain.AuditFilter, error) {
+	query := r.URL.Query()
+	filter := domain.AuditFilter{
+		Action:   query.Get("action"),
+		Outcome:  query.Get("outcome"),
+		TargetID: query.Get("target_id"),
+	}
+	filter.Page, _ = strconv.Atoi(query.Get("page"))
+	filter.PageSize, _ = strconv.Atoi(query.Get("page_size"))
+
+	if v := query.Get("actor_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return filter, &service.ValidationError{Key: "audit.invalid_actor_id"}
+		}
+		filter.ActorID = id
+	}
+	for name, dst := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		v := query.Get(name)
+		if v == "" {
+			continue
+		}
+		t, err := parseAuditTime(v)
+		if err != nil {
+			return filter, &service.ValidationError{Key: "audit.invalid_date", Args: []interface{}{name}}
+		}
+		*dst = &t
+	}
+	return filter, nil
+}
+
+func parseAuditTime(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", v)
+}
+
+// lazyHeaderWriter só envia os headers de download na primeira escrita.
+type lazyHeaderWriter struct {
+	http.ResponseWriter
+	contentType string
+	filename    string
+	started     bool
+}
+
+func (w *lazyHeaderWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.Header().Set("Content-Type", w.contentType)
+		w.Header().Set("Content-Disposition", `attachment; filename="`+w.filename+`"`)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Audit registra a ação depois que o handler responde. Em rotas autenticadas deve
+// ficar dentro do AuthMiddleware para enxergar o usuário do token.
+func Audit(auditService service.AuditService, action string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			actor := actorFromRequest(r)
+			email, _ := r.Context().Value("email").(string)
+			entry := &domain.AuditEntry{
+				OrgID:      actor.OrgID,
+				ActorID:    actor.UserID,
+				ActorEmail: email,
+				Action:     action,
+				TargetType: auditTargetType(action),
+				TargetID:   mux.Vars(r)["id"],
+				IP:         clientIP(r),
+				UserAgent:  r.UserAgent(),
+			}
+
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r.WithContext(context.WithValue(r.Context(), "audit_entry", entry)))
+
+			entry.StatusCode = rec.status
+			entry.Outcome = auditOutcome(rec.status)
+			auditService.Record(r.Context(), *entry)
+		}
+	}
+}
+
+func auditTargetType(action string) string {
+	prefix, _, _ := strings.Cut(action, ".")
+	if prefix == "auth" {
+		return "user"
+	}
+	return prefix
+}
+
+func auditOutcome(status int) string {
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden || status == http.StatusTooManyRequests:
+		return domain.AuditOutcomeDenied
+	case status >= 400:
+		return domain.AuditOutcomeFailure
+	default:
+		return domain.AuditOutcomeSuccess
+	}
+}
+
+// auditEntryFrom devolve nil quando a rota não passa pelo middleware Audit.
+func auditEntryFrom(r *http.Request) *domain.AuditEntry {
+	entry, _ := r.Context().Value("audit_entry").(*domain.AuditEntry)
+	return entry
+}
+
+// auditUser identifica o usuário em rotas públicas, como login e cadastro,
+// depois que ele se autenticou.
+func auditUser(r *http.Request, user *domain.User) {
+	entry := auditEntryFrom(r)
+	if entry == nil || user == nil {
+		return
+	}
+	entry.ActorID = user.ID
+	entry.OrgID = user.OrgID
+	entry.ActorEmail = user.Email
+	entry.TargetID = strconv.Itoa(user.ID)
+}
+
+// auditEmail registra a conta informada como alvo enquanto ninguém se
+// autenticou; o repositório troca o email pelo id quando a conta existe.
+func auditEmail(r *http.Request, email string) {
+	if entry := auditEntryFrom(r); entry != nil && entry.ActorID == 0 {
+		entry.TargetID = strings.TrimSpace(email)
+	}
+}
+
+func auditTarget(r *http.Request, id int) {
+	if entry := auditEntryFrom(r); entry != nil {
+		entry.TargetID = strconv.Itoa(id)
+	}
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	if !s.wroteHeader {
+		s.status = code
+		s.wroteHeader = true
+	}
+	s.ResponseWriter.WriteHeader(code)
+}
+
+func (s *statusRecorder) Write(p []byte) (int, error) {
+	s.wroteHeader = true
+	return s.ResponseWriter.Write(p)
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingAudit struct {
+	entries []domain.AuditEntry
+}
+
+func (a *recordingAudit) Record(ctx context.Context, entry domain.AuditEntry) {
+	a.entries = append(a.entries, entry)
+}
+
+func (a *recordingAudit) List(ctx context.Context, actor domain.Actor, filter domain.AuditFilter) (*domain.AuditListResponse, error) {
+	return nil, nil
+}
+
+func (a *recordingAudit) Export(ctx context.Context, actor domain.Actor, filter domain.AuditFilter, format string, w io.Writer) error {
+	return nil
+}
+
+func TestAudit_AnonymousEmailIsTargetNotActor(t *testing.T) {
+	audit := &recordingAudit{}
+	failed := Audit(audit, domain.AuditLogin)(func(w http.ResponseWriter, r *http.Request) {
+		auditEmail(r, " vitima@test.com ")
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	failed(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/auth/login", nil))
+
+	assert.Len(t, audit.entries, 1)
+	entry := audit.entries[0]
+	assert.Zero(t, entry.ActorID)
+	assert.Empty(t, entry.ActorEmail)
+	assert.Equal(t, "user", entry.TargetType)
+	assert.Equal(t, "vitima@test.com", entry.TargetID)
+	assert.Equal(t, domain.AuditOutcomeDenied, entry.Outcome)
+}
+
+func TestAudit_AuthenticatedUserIsActorAndTarget(t *testing.T) {
+	audit := &recordingAudit{}
+	login := Audit(audit, domain.AuditLogin)(func(w http.ResponseWriter, r *http.Request) {
+		auditEmail(r, "dono@test.com")
+		auditUser(r, &domain.User{ID: 9, OrgID: 3, Email: "dono@test.com"})
+	})
+
+	login(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/auth/login", nil))
+
+	entry := audit.entries[0]
+	assert.Equal(t, 9, entry.ActorID)
+	assert.Equal(t, 3, entry.OrgID)
+	assert.Equal(t, "9", entry.TargetID)
+}
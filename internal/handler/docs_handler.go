@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/nathaliaoliveira/goapp/internal/openapi"
+)
+
+type DocsHandler struct{}
+
+func NewDocsHandler() *DocsHandler {
+	return &DocsHandler{}
+}
+
+// Spec serve a especificação OpenAPI 3 da API.
+func (h *DocsHandler) Spec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openapi.JSON())
+}
+
+// UI serve a documentação interativa (Swagger UI) sobre /openapi.json.
+func (h *DocsHandler) UI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(openapi.DocsPage())
+}
+
+// Asset serve os arquivos do Swagger UI embutidos no binário, para que /docs
+// não dependa de CDN.
+func (h *DocsHandler) Asset(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["file"]
+	data, err := openapi.DocsAsset(name)
+	if err != nil {
+		NotFound(w, r)
+		return
+	}
+	http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(data))
+}
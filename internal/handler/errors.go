@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/i18n"
+	"github.com/nathaliaoliveira/goapp/internal/logging"
+	"github.com/nathaliaoliveira/goapp/internal/repository"
+	"github.com/nathaliaoliveira/goapp/internal/service"
+)
+
+const problemContentType = "application/problem+json"
+
+// statusClientClosedRequest segue a convenção do nginx para requisições
+// abandonadas pelo cliente antes da resposta.
+const statusClientClosedRequest = 499
+
+// writeError é o único ponto que converte erros de serviço e de repositório
+// em respostas HTTP. Erros desconhecidos viram 500 sem expor a mensagem.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	var tooMany *service.TooManyAttemptsError
+	if errors.As(err, &tooMany) {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(tooMany.RetryAfter.Seconds()))))
+	}
+	writeProblemBody(w, r, ProblemFor(r.Context(), err))
+}
+
+// writeProblem responde erros detectados no próprio handler, antes de chegar
+// ao serviço (corpo inválido, ID malformado, site não permitido...). key é a
+// chave da mensagem no catálogo i18n.
+func writeProblem(w http.ResponseWriter, r *http.Request, status int, code, key string, args ...interface{}) {
+	writeProblemBody(w, r, domain.Problem{Status: status, Code: code, Detail: i18n.Translate(r.Context(), key, args...)})
+}
+
+// ProblemFor classifica um erro de serviço ou de repositório, com a mensagem
+// no idioma de ctx. Também é usado pela API gRPC, para que os dois
+// transportes devolvam os mesmos códigos.
+func ProblemFor(ctx context.Context, err error) domain.Problem {
+	var (
+		validation     *service.ValidationError
+		authentication *service.AuthenticationError
+		forbidden      *service.ForbiddenError
+		tooMany        *service.TooManyAttemptsError
+		internal       *service.InternalError
+		duplicateEmail *repository.DuplicateEmailError
+		duplicateSite  *repository.DuplicateSiteError
+		duplicateEvent *repository.DuplicateEventError
+		invalidToken   *repository.InvalidTokenError
+		userNotFound   *repository.UserNotFoundError
+		orgNotFound    *repository.OrganizationNotFoundError
+		siteNotFound   *repository.SiteNotFoundError
+		keyNotFound    *repository.APIKeyNotFoundError
+		senderNotFound *repository.WebhookSenderNotFoundError
+		identity       *repository.IdentityNotFoundError
+	)
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return domain.Problem{Status: http.StatusGatewayTimeout, Code: domain.ProblemRequestTimeout, Detail: i18n.Translate(ctx, "request.timeout")}
+	case errors.Is(err, context.Canceled):
+		return domain.Problem{Status: statusClientClosedRequest, Code: domain.ProblemClientClosed, Title: "Client Closed Request"}
+	case errors.As(err, &validation):
+		return domain.Problem{Status: http.StatusBadRequest, Code: domain.ProblemValidationFailed, Detail: i18n.Translate(ctx, validation.Key, validation.Args...), Errors: translateDetails(ctx, validation.Details)}
+	case errors.As(err, &authentication):
+		return domain.Problem{Status: http.StatusUnauthorized, Code: domain.ProblemAuthFailed, Detail: i18n.Translate(ctx, authentication.Key, authentication.Args...)}
+	case errors.As(err, &forbidden):
+		return domain.Problem{Status: http.StatusForbidden, Code: domain.ProblemForbidden, Detail: i18n.Translate(ctx, forbidden.Key, forbidden.Args...)}
+	case errors.As(err, &tooMany):
+		return domain.Problem{Status: http.StatusTooManyRequests, Code: domain.ProblemTooManyAttempts, Detail: i18n.Translate(ctx, "auth.too_many_attempts")}
+	case errors.As(err, &internal):
+		// Só a mensagem: a causa pode trazer detalhes do banco ou da rede.
+		return domain.Problem{Status: http.StatusInternalServerError, Code: domain.ProblemInternal, Detail: i18n.Translate(ctx, internal.Key)}
+	case errors.As(err, &duplicateEmail):
+		return domain.Problem{Status: http.StatusConflict, Code: domain.ProblemEmailTaken, Detail: i18n.Translate(ctx, "user.email_taken", duplicateEmail.Email)}
+	case errors.As(err, &duplicateSite):
+		return domain.Problem{Status: http.StatusConflict, Code: domain.ProblemSiteExists, Detail: i18n.Translate(ctx, "site.exists", duplicateSite.Domain)}
+	case errors.As(err, &duplicateEvent):
+		return domain.Problem{Status: http.StatusConflict, Code: domain.ProblemDuplicateEvent, Detail: i18n.Translate(ctx, "event.duplicate")}
+	case errors.As(err, &invalidToken):
+		return domain.Problem{Status: http.StatusBadRequest, Code: domain.ProblemInvalidToken, Detail: i18n.Translate(ctx, "token.invalid_or_expired")}
+	case errors.As(err, &userNotFound):
+		return domain.Problem{Status: http.StatusNotFound, Code: domain.ProblemUserNotFound, Detail: i18n.Translate(ctx, "user.not_found")}
+	case errors.As(err, &orgNotFound):
+		return domain.Problem{Status: http.StatusNotFound, Code: domain.ProblemOrgNotFound, Detail: i18n.Translate(ctx, "organization.not_found")}
+	case errors.As(err, &siteNotFound):
+		return domain.Problem{Status: http.StatusNotFound, Code: domain.ProblemSiteNotFound, Detail: i18n.Translate(ctx, "site.not_found")}
+	case errors.As(err, &keyNotFound):
+		return domain.Problem{Status: http.StatusNotFound, Code: domain.ProblemAPIKeyNotFound, Detail: i18n.Translate(ctx, "api_key.not_found")}
+	case errors.As(err, &senderNotFound):
+		return domain.Problem{Status: http.StatusNotFound, Code: domain.ProblemSenderNotFound, Detail: i18n.Translate(ctx, "sender.not_found")}
+	case errors.As(err, &identity):
+		return domain.Problem{Status: http.StatusNotFound, Code: domain.ProblemIdentityNotFound, Detail: i18n.Translate(ctx, "identity.not_found")}
+	default:
+		return domain.Problem{Status: http.StatusInternalServerError, Code: domain.ProblemInternal, Detail: i18n.Translate(ctx, "internal.error")}
+	}
+}
+
+func translateDetails(ctx context.Context, details []domain.FieldError) []domain.FieldError {
+	translated := make([]domain.FieldError, len(details))
+	for i, detail := range details {
+		translated[i] = detail
+		if detail.Key != "" {
+			translated[i].Message = i18n.Translate(ctx, detail.Key, detail.Args...)
+		}
+	}
+	return translated
+}
+
+func writeProblemBody(w http.ResponseWriter, r *http.Request, problem domain.Problem) {
+	problem.Type = "about:blank"
+	if problem.Title == "" {
+		problem.Title = http.StatusText(problem.Status)
+	}
+	problem.Instance = r.URL.Path
+	problem.RequestID = logging.RequestID(r.Context())
+
+	w.Header().Set("Content-Type", problemContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(problem.Status)
+	json.NewEncoder(w).Encode(problem)
+}
+
+// NotFound substitui a resposta em texto do roteador para rotas inexistentes.
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	writeProblem(w, r, http.StatusNotFound, domain.ProblemNotFound, "request.not_found")
+}
+
+// MethodNotAllowed substitui a resposta do roteador para métodos não aceitos.
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	writeProblem(w, r, http.StatusMethodNotAllowed, domain.ProblemMethodNotAllowed, "request.method_not_allowed")
+}
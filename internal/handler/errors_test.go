@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/i18n"
+	"github.com/nathaliaoliveira/goapp/internal/logging"
+	"github.com/nathaliaoliveira/goapp/internal/repository"
+	"github.com/nathaliaoliveira/goapp/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveError(t *testing.T, err error) (*httptest.ResponseRecorder, domain.Problem) {
+	return serveErrorIn(t, i18n.DefaultLanguage, err)
+}
+
+func serveErrorIn(t *testing.T, lang string, err error) (*httptest.ResponseRecorder, domain.Problem) {
+	t.Helper()
+
+	r := httptest.NewRequest(http.MethodPost, "/users", nil)
+	r = r.WithContext(i18n.WithLanguage(logging.WithRequestID(r.Context(), "req-123"), lang))
+	w := httptest.NewRecorder()
+	writeError(w, r, err)
+
+	var problem domain.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	return w, problem
+}
+
+func TestWriteError_ValidationDetails(t *testing.T) {
+	err := &service.ValidationError{
+		Key:     "password.policy_violation",
+		Details: service.DefaultPasswordPolicy().Validate("curta", "joao@exemplo.com", "João"),
+	}
+
+	w, problem := serveError(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	assert.Equal(t, domain.ProblemValidationFailed, problem.Code)
+	assert.Equal(t, "Bad Request", problem.Title)
+	assert.Equal(t, "/users", problem.Instance)
+	assert.Equal(t, "req-123", problem.RequestID)
+	require.NotEmpty(t, problem.Errors)
+	assert.Equal(t, "password", problem.Errors[0].Field)
+	assert.Equal(t, "A senha deve ter pelo menos 10 caracteres", problem.Errors[0].Message)
+}
+
+func TestWriteError_MapsWrappedRepositoryErrors(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{&repository.DuplicateEmailError{Email: "a@b.com"}, http.StatusConflict, domain.ProblemEmailTaken},
+		{fmt.Errorf("atualizar: %w", &repository.UserNotFoundError{ID: 42}), http.StatusNotFound, domain.ProblemUserNotFound},
+		{&repository.SiteNotFoundError{ID: 1}, http.StatusNotFound, domain.ProblemSiteNotFound},
+		{&repository.DuplicateEventError{}, http.StatusConflict, domain.ProblemDuplicateEvent},
+		{&service.AuthenticationError{Key: "auth.invalid_credentials"}, http.StatusUnauthorized, domain.ProblemAuthFailed},
+		{&service.ForbiddenError{Key: "user.delete_forbidden"}, http.StatusForbidden, domain.ProblemForbidden},
+		{fmt.Errorf("consulta: %w", context.DeadlineExceeded), http.StatusGatewayTimeout, domain.ProblemRequestTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			w, problem := serveError(t, tt.err)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.status, problem.Status)
+			assert.Equal(t, tt.code, problem.Code)
+		})
+	}
+}
+
+func TestWriteError_HidesInternalCause(t *testing.T) {
+	w, problem := serveError(t, &service.InternalError{Key: "password.hash_failed", Cause: fmt.Errorf("pq: senha do banco expirada")})
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "Erro ao processar senha", problem.Detail)
+	assert.NotContains(t, w.Body.String(), "pq:")
+
+	_, problem = serveError(t, fmt.Errorf("dial tcp 10.0.0.5:5432: connection refused"))
+	assert.Equal(t, domain.ProblemInternal, problem.Code)
+	assert.Equal(t, "Erro interno do servidor", problem.Detail)
+}
+
+func TestWriteError_TooManyAttemptsSetsRetryAfter(t *testing.T) {
+	w, problem := serveError(t, &service.TooManyAttemptsError{RetryAfter: 90500 * time.Millisecond})
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "91", w.Header().Get("Retry-After"))
+	assert.Equal(t, domain.ProblemTooManyAttempts, problem.Code)
+}
+
+func TestWriteError_TranslatesToRequestLanguage(t *testing.T) {
+	err := &service.ValidationError{
+		Key:     "password.policy_violation",
+		Details: service.DefaultPasswordPolicy().Validate("curta", "joao@exemplo.com", "João"),
+	}
+
+	_, problem := serveErrorIn(t, i18n.English, err)
+
+	assert.Equal(t, domain.ProblemValidationFailed, problem.Code)
+	assert.Equal(t, "The password does not meet the password policy", problem.Detail)
+	assert.Equal(t, "The password must be at least 10 characters long", problem.Errors[0].Message)
+
+	_, problem = serveErrorIn(t, i18n.English, &repository.DuplicateEmailError{Email: "a@b.com"})
+	assert.Equal(t, "Email already registered: a@b.com", problem.Detail)
+}
@@ -2,12 +2,21 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/nathaliaoliveira/goapp/internal/domain"
+
+	"github.com/nathaliaoliveira/goapp/internal/i18n"
 	"github.com/nathaliaoliveira/goapp/internal/service"
+	"github.com/nathaliaoliveira/goapp/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+// maxEventsBodyBytes limita o corpo de POST /api/events, o mesmo limite das
+// requisições assinadas.
+const maxEventsBodyBytes = maxSignedBodyBytes
+
 type EventHandler struct {
     eventService service.EventService
 }
@@ -20,14 +29,34 @@ func NewEventHandler(eventService service.EventService) *EventHandler {
 
 func (h *EventHandler) CreateEvents(w http.ResponseWriter, r *http.Request) {
 	var eventsReq domain.EventsRequest
-	if err := json.NewDecoder(r.Body).Decode(&eventsReq); err != nil {
-		http.Error(w, "Dados inválidos", http.StatusBadRequest)
+	_, span := tracing.Start(r.Context(), "EventHandler.DecodeEvents")
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxEventsBodyBytes)).Decode(&eventsReq)
+	tracing.RecordError(span, err)
+	span.SetAttributes(attribute.Int("events.count", len(eventsReq.Events)))
+	span.End()
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeProblem(w, r, http.StatusRequestEntityTooLarge, domain.ProblemBodyTooLarge, "request.body_too_large")
 		return
 	}
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, domain.ProblemInvalidBody, "request.invalid_body")
+		return
+	}
+	
+	sites := allowedSites(r)
+	for _, event := range eventsReq.Events {
+		if !siteAllowed(sites, event.Site) {
+			writeProblem(w, r, http.StatusForbidden, domain.ProblemSiteForbidden, "event.site_forbidden", event.Site)
+			return
+		}
+	}
 	
-	result, err := h.eventService.ProcessEvents(eventsReq.Events)
+	orgID := r.Context().Value("org_id").(int)
+	
+	result, err := h.eventService.ProcessEvents(r.Context(), orgID, eventsReq.Events)
 	if err != nil {
-		h.handleServiceError(w, err)
+		writeError(w, r, err)
 		return
 	}
 	
@@ -41,26 +70,29 @@ func (h *EventHandler) GetDailyStats(w http.ResponseWriter, r *http.Request) {
 	endDate := r.URL.Query().Get("end_date")
 	site := r.URL.Query().Get("site")
 	
-	stats, err := h.eventService.GetDailyStats(startDate, endDate, site)
+	if sites := allowedSites(r); len(sites) > 0 {
+		if site == "" && len(sites) == 1 {
+			site = sites[0]
+		}
+		if site == "" || !siteAllowed(sites, site) {
+			writeProblem(w, r, http.StatusForbidden, domain.ProblemSiteForbidden, "event.site_required")
+			return
+		}
+	}
+	
+	orgID := r.Context().Value("org_id").(int)
+	
+	stats, err := h.eventService.GetDailyStats(r.Context(), orgID, startDate, endDate, site)
 	if err != nil {
-		h.handleServiceError(w, err)
+		writeError(w, r, err)
 		return
 	}
 	
 	response := domain.Response{
-		Message: "Estatísticas diárias por site",
+		Message: i18n.Translate(r.Context(), "event.daily_stats"),
 		Data:    stats,
 	}
 	
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
-
-func (h *EventHandler) handleServiceError(w http.ResponseWriter, err error) {
-    switch e := err.(type) {
-    case *service.ValidationError:
-        http.Error(w, e.Error(), http.StatusBadRequest)
-    default:
-        http.Error(w, "Erro interno do servidor", http.StatusInternalServerError)
-    }
-} 
\ No newline at end of file
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/repository"
+	"github.com/nathaliaoliveira/goapp/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateEvents_RejectsOversizedBody(t *testing.T) {
+	body := `{"events":[{"type":"` + strings.Repeat("a", maxEventsBodyBytes) + `"}]}`
+	r := httptest.NewRequest(http.MethodPost, "/api/events", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	NewEventHandler(nil).CreateEvents(w, r)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Contains(t, w.Body.String(), domain.ProblemBodyTooLarge)
+}
+
+func TestNewRouter_LimitsInvalidCredentialsByIP(t *testing.T) {
+	limiter := service.NewRateLimiter(repository.NewMemoryRateLimitStore(), service.RateLimiterConfig{
+		service.RateLimitGroupAPI: {domain.PrincipalIP: {Requests: 2, Period: time.Minute}},
+	})
+	router := NewRouter(RouterConfig{
+		JWTSecret:     testJWTSecret,
+		UserService:   stubUsers{},
+		APIKeyService: service.NewAPIKeyService(stubAPIKeys{}),
+		RateLimiter:   limiter,
+	})
+
+	request := func(remoteAddr string) int {
+		r := httptest.NewRequest(http.MethodGet, "/api/stats/daily", nil)
+		r.RemoteAddr = remoteAddr
+		r.Header.Set("X-API-Key", "gk_chave-revogada")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	require.Equal(t, http.StatusUnauthorized, request("10.0.0.1:5000"))
+	require.Equal(t, http.StatusUnauthorized, request("10.0.0.1:5001"))
+	assert.Equal(t, http.StatusTooManyRequests, request("10.0.0.1:5002"), "credenciais inválidas também consomem o balde do IP")
+	assert.Equal(t, http.StatusUnauthorized, request("10.0.0.2:5000"))
+}
@@ -2,10 +2,12 @@ package handler
 
 import (
     "encoding/json"
-    "log"
+    "log/slog"
     "net/http"
 
     "github.com/nathaliaoliveira/goapp/internal/domain"
+
+    "github.com/nathaliaoliveira/goapp/internal/i18n"
     "github.com/nathaliaoliveira/goapp/internal/service"
 )
 
@@ -20,25 +22,43 @@ func NewHealthHandler(healthService service.HealthService) *HealthHandler {
 }
 
 func (h *HealthHandler) GetHealth(w http.ResponseWriter, r *http.Request) {
-    log.Printf("🏥 Requisição de health check recebida de: %s", r.RemoteAddr)
+    slog.DebugContext(r.Context(), "Requisição de health check recebida", "remote_addr", r.RemoteAddr)
     
-    health, err := h.healthService.GetHealth()
+    health, err := h.healthService.GetHealth(r.Context())
     if err != nil {
-        log.Printf("❌ Erro no health check: %v", err)
-        http.Error(w, "Erro interno do servidor", http.StatusInternalServerError)
+        slog.WarnContext(r.Context(), "Erro no health check", "error", err)
+        writeError(w, r, err)
         return
     }
     
     response := domain.Response{
-        Message: "API funcionando normalmente",
+        Message: i18n.Translate(r.Context(), "health.ok"),
         Data:    health,
     }
     
     w.Header().Set("Content-Type", "application/json")
     
-    if health.Database.Status == "unhealthy" {
+    if health.Database.Status == domain.HealthStatusUnhealthy {
         w.WriteHeader(http.StatusServiceUnavailable)
     }
     
     json.NewEncoder(w).Encode(response)
-} 
\ No newline at end of file
+} 
+
+// Livez é a liveness probe: responde 200 enquanto o processo atende requisições.
+func (h *HealthHandler) Livez(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(h.healthService.Live(r.Context()))
+}
+
+// Readyz é a readiness probe: 503 quando algum check de dependência falha. O
+// HealthService já registra no log o erro de cada check.
+func (h *HealthHandler) Readyz(w http.ResponseWriter, r *http.Request) {
+    readiness := h.healthService.Ready(r.Context())
+
+    w.Header().Set("Content-Type", "application/json")
+    if readiness.Status != domain.HealthStatusHealthy {
+        w.WriteHeader(http.StatusServiceUnavailable)
+    }
+    json.NewEncoder(w).Encode(readiness)
+}
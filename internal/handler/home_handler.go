@@ -2,11 +2,13 @@ package handler
 
 import (
     "encoding/json"
-    "log"
+    "log/slog"
     "net/http"
     "time"
 
     "github.com/nathaliaoliveira/goapp/internal/domain"
+
+    "github.com/nathaliaoliveira/goapp/internal/i18n"
 )
 
 type HomeHandler struct{}
@@ -16,15 +18,16 @@ func NewHomeHandler() *HomeHandler {
 }
 
 func (h *HomeHandler) Home(w http.ResponseWriter, r *http.Request) {
-    log.Printf("📄 Página inicial acessada por: %s", r.RemoteAddr)
+    slog.InfoContext(r.Context(), "Página inicial acessada", "remote_addr", r.RemoteAddr)
     
     response := domain.Response{
-        Message: "Bem-vindo à API Go com PostgreSQL e JWT!",
+        Message: i18n.Translate(r.Context(), "home.welcome"),
         Data: map[string]string{
             "status": "running",
             "time":   time.Now().Format(time.RFC3339),
             "auth_required": "Para acessar rotas protegidas, faça login e use o token JWT",
-            "endpoints": "POST /login, POST /register, GET /users (protegido), GET /profile (protegido)",
+            "docs":    "/docs",
+            "openapi": "/openapi.json",
         },
     }
     
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/metrics"
+	"github.com/nathaliaoliveira/goapp/internal/service"
+)
+
+// Metrics conta requisições e mede a latência por rota. O rótulo route é o
+// template do caminho ("/users/{id}"), nunca o caminho com IDs, para manter a
+// cardinalidade fixa.
+func Metrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := "unmatched"
+		if current := mux.CurrentRoute(r); current != nil {
+			if tpl, err := current.GetPathTemplate(); err == nil {
+				route = tpl
+			}
+		}
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+		next.ServeHTTP(rec, r)
+
+		metrics.HTTPDuration.WithLabelValues(route, r.Method).Observe(time.Since(start).Seconds())
+		metrics.HTTPRequests.WithLabelValues(route, r.Method, strconv.Itoa(rec.status)).Inc()
+	})
+}
+
+// observeLogin classifica o resultado de um login para a métrica de tentativas.
+func observeLogin(method string, resp *domain.AuthResponse, err error) {
+	result := metrics.LoginSuccess
+	switch err.(type) {
+	case nil:
+		if resp != nil && (resp.MFARequired || resp.EnrollmentRequired) {
+			result = metrics.LoginMFARequired
+		}
+	case *service.AuthenticationError, *service.ValidationError, *service.ForbiddenError:
+		result = metrics.LoginFailure
+	case *service.TooManyAttemptsError:
+		result = metrics.LoginLocked
+	default:
+		result = metrics.LoginError
+	}
+	metrics.ObserveLogin(method, result)
+}
@@ -1,53 +1,265 @@
 package handler
 
 import (
+    "bytes"
     "context"
-    "log"
+    "errors"
+    "io"
+    "log/slog"
+    "net"
     "net/http"
     "strings"
+    "time"
 
     "github.com/golang-jwt/jwt/v5"
+    "github.com/nathaliaoliveira/goapp/internal/domain"
+    "github.com/nathaliaoliveira/goapp/internal/service"
 )
 
-func AuthMiddleware(jwtSecret []byte) func(http.HandlerFunc) http.HandlerFunc {
+const maxSignedBodyBytes = 10 << 20
+
+// AuthMiddleware aceita tokens JWT e, quando scopes for informado, chaves de API
+// (X-API-Key ou Authorization: ApiKey) que possuam todos os escopos exigidos.
+// Tokens de sessão de usuários removidos são recusados (userService.CheckSession).
+func AuthMiddleware(jwtSecret []byte, userService service.UserService, apiKeyService service.APIKeyService, scopes ...string) func(http.HandlerFunc) http.HandlerFunc {
     return func(next http.HandlerFunc) http.HandlerFunc {
         return func(w http.ResponseWriter, r *http.Request) {
             authHeader := r.Header.Get("Authorization")
-            if authHeader == "" {
-                log.Printf("❌ Tentativa de acesso sem token: %s %s", r.Method, r.URL.Path)
-                http.Error(w, "Token não fornecido", http.StatusUnauthorized)
+            apiKeyHeader := r.Header.Get("X-API-Key")
+
+            if apiKeyHeader != "" || strings.HasPrefix(authHeader, "ApiKey ") {
+                rawKey := apiKeyHeader
+                if rawKey == "" {
+                    rawKey = strings.TrimSpace(strings.TrimPrefix(authHeader, "ApiKey "))
+                }
+                authenticateAPIKey(w, r, next, apiKeyService, rawKey, scopes)
                 return
             }
 
-            tokenParts := strings.Split(authHeader, " ")
-            if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
-                log.Printf("❌ Formato de token inválido: %s %s", r.Method, r.URL.Path)
-                http.Error(w, "Formato de token inválido", http.StatusUnauthorized)
+            authenticateJWT(w, r, next, jwtSecret, userService, authHeader, "")
+        }
+    }
+}
+
+// TwoFactorEnrollmentMiddleware aceita, além dos tokens de sessão, o token de
+// cadastro emitido no login quando o 2FA é obrigatório e a conta ainda não o
+// configurou. Deve envolver apenas as rotas de configuração do 2FA.
+func TwoFactorEnrollmentMiddleware(jwtSecret []byte, userService service.UserService) func(http.HandlerFunc) http.HandlerFunc {
+    return func(next http.HandlerFunc) http.HandlerFunc {
+        return func(w http.ResponseWriter, r *http.Request) {
+            authenticateJWT(w, r, next, jwtSecret, userService, r.Header.Get("Authorization"), domain.TokenPurposeMFAEnrollment)
+        }
+    }
+}
+
+// authenticateJWT aceita tokens de sessão e, se allowedPurpose for informado,
+// também tokens emitidos para esse propósito.
+func authenticateJWT(w http.ResponseWriter, r *http.Request, next http.HandlerFunc, jwtSecret []byte, userService service.UserService, authHeader, allowedPurpose string) {
+    if authHeader == "" {
+        slog.WarnContext(r.Context(), "Tentativa de acesso sem token", "method", r.Method, "path", r.URL.Path)
+        writeProblem(w, r, http.StatusUnauthorized, domain.ProblemUnauthenticated, "auth.token_missing")
+        return
+    }
+
+    tokenParts := strings.Split(authHeader, " ")
+    if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
+        slog.WarnContext(r.Context(), "Formato de token inválido", "method", r.Method, "path", r.URL.Path)
+        writeProblem(w, r, http.StatusUnauthorized, domain.ProblemUnauthenticated, "auth.token_format")
+        return
+    }
+
+    tokenString := tokenParts[1]
+
+    claims := jwt.MapClaims{}
+    token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+        return jwtSecret, nil
+    })
+
+    if err != nil || !token.Valid {
+        slog.WarnContext(r.Context(), "Token inválido", "method", r.Method, "path", r.URL.Path, "error", err)
+        writeProblem(w, r, http.StatusUnauthorized, domain.ProblemUnauthenticated, "auth.token_invalid")
+        return
+    }
+
+    // Tokens de desafio e de cadastro do 2FA não valem como sessão.
+    if purpose, _ := claims["purpose"].(string); purpose != "" && purpose != allowedPurpose {
+        slog.WarnContext(r.Context(), "Token de propósito restrito usado como sessão", "purpose", purpose, "method", r.Method, "path", r.URL.Path)
+        writeProblem(w, r, http.StatusUnauthorized, domain.ProblemUnauthenticated, "auth.token_invalid")
+        return
+    }
+
+    // Tokens emitidos antes das organizações não têm org_id e precisam ser renovados.
+    orgID, ok := claims["org_id"].(float64)
+    if !ok {
+        slog.WarnContext(r.Context(), "Token sem organização", "method", r.Method, "path", r.URL.Path)
+        writeProblem(w, r, http.StatusUnauthorized, domain.ProblemUnauthenticated, "auth.token_invalid")
+        return
+    }
+
+    userID := int(claims["user_id"].(float64))
+    if err := userService.CheckSession(r.Context(), userID); err != nil {
+        var authErr *service.AuthenticationError
+        if !errors.As(err, &authErr) {
+            writeError(w, r, err)
+            return
+        }
+        slog.WarnContext(r.Context(), "Token de usuário removido", "user_id", userID, "method", r.Method, "path", r.URL.Path)
+        writeProblem(w, r, http.StatusUnauthorized, domain.ProblemUnauthenticated, "auth.token_invalid")
+        return
+    }
+
+    email := claims["email"].(string)
+    slog.InfoContext(r.Context(), "Acesso autorizado", "method", r.Method, "path", r.URL.Path, "email", email)
+
+    ctx := r.Context()
+    ctx = context.WithValue(ctx, "user_id", userID)
+    ctx = context.WithValue(ctx, "org_id", int(orgID))
+    ctx = context.WithValue(ctx, "email", email)
+    if role, ok := claims["role"].(string); ok {
+        ctx = context.WithValue(ctx, "role", role)
+    }
+    // Tokens emitidos antes das renovações não têm auth_time; iat é o login.
+    authTime, ok := claims["auth_time"].(float64)
+    if !ok {
+        authTime, _ = claims["iat"].(float64)
+    }
+    if authTime > 0 {
+        ctx = context.WithValue(ctx, "auth_time", time.Unix(int64(authTime), 0))
+    }
+    r = r.WithContext(ctx)
+
+    next.ServeHTTP(w, r)
+}
+
+func authenticateAPIKey(w http.ResponseWriter, r *http.Request, next http.HandlerFunc, apiKeyService service.APIKeyService, rawKey string, scopes []string) {
+    if apiKeyService == nil || len(scopes) == 0 {
+        slog.WarnContext(r.Context(), "Chave de API não aceita nesta rota", "method", r.Method, "path", r.URL.Path)
+        writeProblem(w, r, http.StatusForbidden, domain.ProblemAPIKeyNotAllowed, "auth.api_key_not_allowed")
+        return
+    }
+
+    key, err := apiKeyService.Authenticate(r.Context(), rawKey)
+    if err != nil {
+        slog.WarnContext(r.Context(), "Chave de API rejeitada", "method", r.Method, "path", r.URL.Path, "error", err)
+        writeError(w, r, err)
+        return
+    }
+
+    for _, scope := range scopes {
+        if !key.HasScope(scope) {
+            slog.WarnContext(r.Context(), "Chave de API sem escopo", "key_prefix", key.Prefix, "scope", scope, "method", r.Method, "path", r.URL.Path)
+            writeProblem(w, r, http.StatusForbidden, domain.ProblemInsufficientScope, "auth.insufficient_scope", scope)
+            return
+        }
+    }
+
+    slog.InfoContext(r.Context(), "Acesso autorizado", "method", r.Method, "path", r.URL.Path, "key_prefix", key.Prefix)
+
+    ctx := r.Context()
+    ctx = context.WithValue(ctx, "user_id", key.UserID)
+    ctx = context.WithValue(ctx, "org_id", key.OrgID)
+    ctx = context.WithValue(ctx, "api_key", key)
+    ctx = context.WithValue(ctx, "allowed_sites", key.Sites)
+    r = r.WithContext(ctx)
+
+    next.ServeHTTP(w, r)
+}
+
+// SignatureMiddleware autentica requisições assinadas com HMAC (X-Signature-Key-Id,
+// X-Signature-Timestamp, X-Signature-Nonce e X-Signature). Requisições sem assinatura
+// seguem para o middleware fallback, normalmente o AuthMiddleware da rota.
+func SignatureMiddleware(signatureService service.SignatureService, fallback func(http.HandlerFunc) http.HandlerFunc) func(http.HandlerFunc) http.HandlerFunc {
+    return func(next http.HandlerFunc) http.HandlerFunc {
+        unsigned := fallback(next)
+
+        return func(w http.ResponseWriter, r *http.Request) {
+            signature := r.Header.Get("X-Signature")
+            if signature == "" {
+                unsigned(w, r)
                 return
             }
 
-            tokenString := tokenParts[1]
+            body, err := io.ReadAll(io.LimitReader(r.Body, maxSignedBodyBytes+1))
+            if err != nil {
+                writeProblem(w, r, http.StatusBadRequest, domain.ProblemInvalidBody, "request.body_unreadable")
+                return
+            }
+            if len(body) > maxSignedBodyBytes {
+                writeProblem(w, r, http.StatusRequestEntityTooLarge, domain.ProblemBodyTooLarge, "request.body_too_large")
+                return
+            }
+            r.Body = io.NopCloser(bytes.NewReader(body))
 
-            claims := jwt.MapClaims{}
-            token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-                return jwtSecret, nil
+            sender, err := signatureService.Verify(r.Context(), domain.SignedRequest{
+                KeyID:     r.Header.Get("X-Signature-Key-Id"),
+                Timestamp: r.Header.Get("X-Signature-Timestamp"),
+                Nonce:     r.Header.Get("X-Signature-Nonce"),
+                Signature: signature,
+                Body:      body,
             })
-
-            if err != nil || !token.Valid {
-                log.Printf("❌ Token inválido: %s %s - %v", r.Method, r.URL.Path, err)
-                http.Error(w, "Token inválido", http.StatusUnauthorized)
+            if err != nil {
+                slog.WarnContext(r.Context(), "Assinatura rejeitada", "method", r.Method, "path", r.URL.Path, "error", err)
+                writeError(w, r, err)
                 return
             }
 
-            email := claims["email"].(string)
-            log.Printf("✅ Acesso autorizado: %s %s - Usuário: %s", r.Method, r.URL.Path, email)
+            slog.InfoContext(r.Context(), "Acesso autorizado", "method", r.Method, "path", r.URL.Path, "key_id", sender.KeyID)
 
             ctx := r.Context()
-            ctx = context.WithValue(ctx, "user_id", int(claims["user_id"].(float64)))
-            ctx = context.WithValue(ctx, "email", email)
+            ctx = context.WithValue(ctx, "user_id", sender.UserID)
+            ctx = context.WithValue(ctx, "org_id", sender.OrgID)
+            ctx = context.WithValue(ctx, "webhook_sender", sender)
+            ctx = context.WithValue(ctx, "allowed_sites", sender.Sites)
             r = r.WithContext(ctx)
 
             next.ServeHTTP(w, r)
         }
     }
-} 
\ No newline at end of file
+}
+
+// RequireAdmin deve ser aplicado depois do AuthMiddleware.
+func RequireAdmin(next http.HandlerFunc) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        if role, _ := r.Context().Value("role").(string); role != domain.RoleAdmin {
+            slog.WarnContext(r.Context(), "Acesso restrito a administradores", "method", r.Method, "path", r.URL.Path)
+            writeProblem(w, r, http.StatusForbidden, domain.ProblemAdminRequired, "auth.admin_required")
+            return
+        }
+        next.ServeHTTP(w, r)
+    }
+}
+
+func actorFromRequest(r *http.Request) domain.Actor {
+    userID, _ := r.Context().Value("user_id").(int)
+    orgID, _ := r.Context().Value("org_id").(int)
+    role, _ := r.Context().Value("role").(string)
+    return domain.Actor{UserID: userID, OrgID: orgID, Role: role}
+}
+
+// clientIP usa o endereço da conexão; headers como X-Forwarded-For podem ser forjados pelo cliente.
+func clientIP(r *http.Request) string {
+    host, _, err := net.SplitHostPort(r.RemoteAddr)
+    if err != nil {
+        return r.RemoteAddr
+    }
+    return host
+}
+
+// allowedSites devolve os sites permitidos para o principal autenticado;
+// uma lista vazia significa acesso a todos os sites.
+func allowedSites(r *http.Request) []string {
+    sites, _ := r.Context().Value("allowed_sites").([]string)
+    return sites
+}
+
+func siteAllowed(sites []string, site string) bool {
+    if len(sites) == 0 {
+        return true
+    }
+    for _, allowed := range sites {
+        if allowed == site {
+            return true
+        }
+    }
+    return false
+}
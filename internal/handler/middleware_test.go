@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/repository"
+	"github.com/nathaliaoliveira/goapp/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testJWTSecret = []byte("test-secret")
+
+const deletedUserID = 2
+
+// stubUsers trata deletedUserID como usuário removido.
+type stubUsers struct {
+	service.UserService
+}
+
+func (stubUsers) CheckSession(ctx context.Context, userID int) error {
+	if userID == deletedUserID {
+		return &service.AuthenticationError{Key: "auth.token_invalid"}
+	}
+	return nil
+}
+
+// stubAPIKeys devolve as chaves como ficam depois de SoftDelete: as do
+// usuário removido, revogadas.
+type stubAPIKeys struct {
+	repository.APIKeyRepository
+}
+
+func (stubAPIKeys) GetByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	revokedAt := time.Now()
+	return &domain.APIKey{ID: 5, UserID: deletedUserID, OrgID: 1, Prefix: "gk_", Scopes: []string{domain.ScopeStatsRead}, RevokedAt: &revokedAt}, nil
+}
+
+func serveAuthenticated(t *testing.T, prepare func(r *http.Request)) (*httptest.ResponseRecorder, bool) {
+	t.Helper()
+	called := false
+	h := AuthMiddleware(testJWTSecret, stubUsers{}, service.NewAPIKeyService(stubAPIKeys{}), domain.ScopeStatsRead)(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/api/stats/daily", nil)
+	prepare(r)
+	w := httptest.NewRecorder()
+	h(w, r)
+	return w, called
+}
+
+func sessionToken(t *testing.T, userID int) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": userID, "org_id": 1, "email": "a@b.com", "role": domain.RoleUser, "exp": time.Now().Add(time.Hour).Unix(),
+	}).SignedString(testJWTSecret)
+	require.NoError(t, err)
+	return token
+}
+
+func TestAuthMiddleware_RejectsDeletedUsers(t *testing.T) {
+	w, called := serveAuthenticated(t, func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+sessionToken(t, 1)) })
+	assert.True(t, called)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w, called = serveAuthenticated(t, func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+sessionToken(t, deletedUserID)) })
+	assert.False(t, called)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	var problem domain.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, domain.ProblemUnauthenticated, problem.Code)
+
+	w, called = serveAuthenticated(t, func(r *http.Request) { r.Header.Set("X-API-Key", "gk_chave-do-removido") })
+	assert.False(t, called)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/i18n"
+	"github.com/nathaliaoliveira/goapp/internal/service"
+)
+
+type OrganizationHandler struct {
+	orgService service.OrganizationService
+}
+
+func NewOrganizationHandler(orgService service.OrganizationService) *OrganizationHandler {
+	return &OrganizationHandler{
+		orgService: orgService,
+	}
+}
+
+func (h *OrganizationHandler) GetOrganization(w http.ResponseWriter, r *http.Request) {
+	orgID := r.Context().Value("org_id").(int)
+
+	org, err := h.orgService.Get(r.Context(), orgID)
+	if err != nil {
+		slog.WarnContext(r.Context(), "Erro ao buscar organização", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	response := domain.Response{
+		Message: i18n.Translate(r.Context(), "organization.found"),
+		Data:    org,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *OrganizationHandler) ListSites(w http.ResponseWriter, r *http.Request) {
+	orgID := r.Context().Value("org_id").(int)
+
+	sites, err := h.orgService.ListSites(r.Context(), orgID)
+	if err != nil {
+		slog.WarnContext(r.Context(), "Erro ao listar sites", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	response := domain.Response{
+		Message: i18n.Translate(r.Context(), "site.listed"),
+		Data:    sites,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *OrganizationHandler) CreateSite(w http.ResponseWriter, r *http.Request) {
+	var req domain.CreateSiteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, domain.ProblemInvalidBody, "request.invalid_body")
+		return
+	}
+
+	site, err := h.orgService.CreateSite(r.Context(), actorFromRequest(r), req)
+	if err != nil {
+		slog.WarnContext(r.Context(), "Erro ao cadastrar site", "error", err)
+		writeError(w, r, err)
+		return
+	}
+	auditTarget(r, site.ID)
+
+	slog.InfoContext(r.Context(), "Site cadastrado na organização", "domain", site.Domain, "org_id", site.OrgID)
+
+	response := domain.Response{
+		Message: i18n.Translate(r.Context(), "site.created"),
+		Data:    site,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *OrganizationHandler) DeleteSite(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, domain.ProblemInvalidID, "request.invalid_id")
+		return
+	}
+
+	if err := h.orgService.DeleteSite(r.Context(), actorFromRequest(r), id); err != nil {
+		slog.WarnContext(r.Context(), "Erro ao remover site", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	response := domain.Response{
+		Message: i18n.Translate(r.Context(), "site.deleted"),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
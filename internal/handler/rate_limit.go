@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/service"
+)
+
+// RateLimit aplica o limite do grupo de rotas ao principal da requisição: a
+// chave de API ou o remetente assinado, senão o usuário autenticado e, sem
+// autenticação, o IP. Nas rotas autenticadas entra antes do AuthMiddleware,
+// limitando por IP, e de novo depois dele, quando o principal já está no
+// contexto. Sem limiter não limita.
+func RateLimit(limiter *service.RateLimiter, group string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		if limiter == nil {
+			return next
+		}
+		return func(w http.ResponseWriter, r *http.Request) {
+			kind, id := rateLimitPrincipal(r)
+			result := limiter.Allow(r.Context(), group, kind, id)
+			if result == nil {
+				next(w, r)
+				return
+			}
+
+			limit := limiter.Limit(group, kind)
+			w.Header().Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Requests, ceilSeconds(limit.Period)))
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+
+			if !result.Allowed {
+				retryAfter := ceilSeconds(result.RetryAfter)
+				slog.WarnContext(r.Context(), "Limite de requisições excedido", "group", group, "principal", kind, "method", r.Method, "path", r.URL.Path)
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+				writeProblem(w, r, http.StatusTooManyRequests, domain.ProblemRateLimited, "request.rate_limited", retryAfter)
+				return
+			}
+			next(w, r)
+		}
+	}
+}
+
+func rateLimitPrincipal(r *http.Request) (kind, id string) {
+	ctx := r.Context()
+	if key, ok := ctx.Value("api_key").(*domain.APIKey); ok {
+		return domain.PrincipalAPIKey, strconv.Itoa(key.ID)
+	}
+	if sender, ok := ctx.Value("webhook_sender").(*domain.WebhookSender); ok {
+		return domain.PrincipalAPIKey, "sender:" + sender.KeyID
+	}
+	if userID, ok := ctx.Value("user_id").(int); ok {
+		return domain.PrincipalUser, strconv.Itoa(userID)
+	}
+	return domain.PrincipalIP, clientIP(r)
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/i18n"
+	"github.com/nathaliaoliveira/goapp/internal/repository"
+	"github.com/nathaliaoliveira/goapp/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRateLimiter() *service.RateLimiter {
+	return service.NewRateLimiter(repository.NewMemoryRateLimitStore(), service.RateLimiterConfig{
+		service.RateLimitGroupAuth: {domain.PrincipalIP: {Requests: 2, Period: time.Minute}},
+		service.RateLimitGroupEvents: {
+			domain.PrincipalAPIKey: {Requests: 1, Period: time.Minute},
+			domain.PrincipalUser:   {Requests: 1, Period: time.Minute},
+		},
+	})
+}
+
+func limitedRequest(h http.HandlerFunc, remoteAddr string, values map[string]interface{}) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, "/login", nil)
+	r.RemoteAddr = remoteAddr
+	ctx := i18n.WithLanguage(r.Context(), "en")
+	for key, value := range values {
+		ctx = context.WithValue(ctx, key, value)
+	}
+	w := httptest.NewRecorder()
+	h(w, r.WithContext(ctx))
+	return w
+}
+
+func TestRateLimit_ByIPWithHeaders(t *testing.T) {
+	h := RateLimit(newTestRateLimiter(), service.RateLimitGroupAuth)(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	w := limitedRequest(h, "10.0.0.1:5000", nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", w.Header().Get("RateLimit-Reset"))
+	assert.Equal(t, "2;w=60", w.Header().Get("RateLimit-Policy"))
+
+	limitedRequest(h, "10.0.0.1:5001", nil)
+	w = limitedRequest(h, "10.0.0.1:5002", nil)
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "30", w.Header().Get("Retry-After"))
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+
+	var problem domain.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, domain.ProblemRateLimited, problem.Code)
+	assert.Equal(t, "Too many requests. Try again in 30 seconds", problem.Detail)
+
+	assert.Equal(t, http.StatusNoContent, limitedRequest(h, "10.0.0.2:5000", nil).Code, "outro IP tem o seu balde")
+}
+
+func TestRateLimit_ByAuthenticatedPrincipal(t *testing.T) {
+	h := RateLimit(newTestRateLimiter(), service.RateLimitGroupEvents)(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+	key := map[string]interface{}{"user_id": 1, "api_key": &domain.APIKey{ID: 7, UserID: 1}}
+	user := map[string]interface{}{"user_id": 1}
+
+	assert.Equal(t, http.StatusCreated, limitedRequest(h, "10.0.0.1:5000", key).Code)
+	assert.Equal(t, http.StatusTooManyRequests, limitedRequest(h, "10.0.0.2:5000", key).Code, "o limite da chave vale em qualquer IP")
+	assert.Equal(t, http.StatusCreated, limitedRequest(h, "10.0.0.1:5000", user).Code, "a sessão do dono da chave tem balde próprio")
+
+	w := limitedRequest(h, "10.0.0.1:5000", nil)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Empty(t, w.Header().Get("RateLimit-Limit"), "grupo sem limite por IP")
+}
+
+func TestRateLimit_WithoutLimiter(t *testing.T) {
+	called := false
+	h := RateLimit(nil, service.RateLimitGroupAuth)(func(w http.ResponseWriter, r *http.Request) { called = true })
+
+	limitedRequest(h, "10.0.0.1:5000", nil)
+	assert.True(t, called)
+}
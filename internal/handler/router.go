@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/service"
+)
+
+// RouterConfig reúne os serviços usados pelas rotas da API.
+type RouterConfig struct {
+	JWTSecret []byte
+
+	UserService         service.UserService
+	AccountService      service.AccountService
+	EventService        service.EventService
+	HealthService       service.HealthService
+	APIKeyService       service.APIKeyService
+	SignatureService    service.SignatureService
+	TwoFactorService    service.TwoFactorService
+	OrganizationService service.OrganizationService
+	AuditService        service.AuditService
+	// Opcional: sem ele as rotas /auth/oidc/* não são registradas.
+	SSOService service.SSOService
+	// API GraphQL de consultas (pacote graphqlapi), montada atrás da
+	// autenticação. Opcional: sem ela a rota /graphql não é registrada.
+	GraphQL http.Handler
+	// Limites de requisições por grupo de rotas e principal. Opcional: sem
+	// ele nenhuma rota é limitada.
+	RateLimiter *service.RateLimiter
+
+	// Timeout é o prazo padrão das requisições; RouteTimeouts o sobrescreve
+	// pelo template do caminho (ex.: "/api/events").
+	Timeout       time.Duration
+	RouteTimeouts map[string]time.Duration
+}
+
+// NewRouter registra todas as rotas da API. Toda rota nova precisa constar na
+// especificação do pacote openapi; o teste do roteador falha caso contrário.
+func NewRouter(cfg RouterConfig) *mux.Router {
+	homeHandler := NewHomeHandler()
+	docsHandler := NewDocsHandler()
+	userHandler := NewUserHandler(cfg.UserService, cfg.AccountService)
+	eventHandler := NewEventHandler(cfg.EventService)
+	healthHandler := NewHealthHandler(cfg.HealthService)
+	apiKeyHandler := NewAPIKeyHandler(cfg.APIKeyService)
+	senderHandler := NewWebhookSenderHandler(cfg.SignatureService)
+	twoFactorHandler := NewTwoFactorHandler(cfg.TwoFactorService)
+	orgHandler := NewOrganizationHandler(cfg.OrganizationService)
+	auditHandler := NewAuditHandler(cfg.AuditService)
+
+	limit := func(group string) func(http.HandlerFunc) http.HandlerFunc {
+		return RateLimit(cfg.RateLimiter, group)
+	}
+	// authenticated aplica o limite do grupo duas vezes: por IP antes da
+	// autenticação, para que credenciais inválidas também sejam limitadas sem
+	// consultar o banco, e por chave de API ou usuário depois dela.
+	authenticated := func(authMiddleware func(http.HandlerFunc) http.HandlerFunc, group string) func(http.HandlerFunc) http.HandlerFunc {
+		return func(next http.HandlerFunc) http.HandlerFunc {
+			return limit(group)(authMiddleware(limit(group)(next)))
+		}
+	}
+
+	auth := authenticated(AuthMiddleware(cfg.JWTSecret, cfg.UserService, cfg.APIKeyService), service.RateLimitGroupAPI)
+	enrollAuth := authenticated(TwoFactorEnrollmentMiddleware(cfg.JWTSecret, cfg.UserService), service.RateLimitGroupAPI)
+	authLimit := limit(service.RateLimitGroupAuth)
+	audit := func(action string) func(http.HandlerFunc) http.HandlerFunc {
+		return Audit(cfg.AuditService, action)
+	}
+
+	// O mux não aplica r.Use quando nenhuma rota casa, então 404 e 405 recebem
+	// tracing e métricas aqui, com route="unmatched".
+	r := mux.NewRouter()
+	r.NotFoundHandler = Tracing(Metrics(http.HandlerFunc(NotFound)))
+	r.MethodNotAllowedHandler = Tracing(Metrics(http.HandlerFunc(MethodNotAllowed)))
+
+	r.HandleFunc("/", homeHandler.Home).Methods("GET")
+	r.HandleFunc("/openapi.json", docsHandler.Spec).Methods("GET")
+	r.HandleFunc("/docs", docsHandler.UI).Methods("GET")
+	r.HandleFunc("/docs/assets/{file}", docsHandler.Asset).Methods("GET")
+	r.HandleFunc("/health", healthHandler.GetHealth).Methods("GET")
+	r.HandleFunc("/livez", healthHandler.Livez).Methods("GET")
+	r.HandleFunc("/readyz", healthHandler.Readyz).Methods("GET")
+	r.HandleFunc("/login", authLimit(audit(domain.AuditLogin)(userHandler.Login))).Methods("POST")
+	r.HandleFunc("/login/2fa", authLimit(audit(domain.AuditLoginMFA)(userHandler.LoginMFA))).Methods("POST")
+	r.HandleFunc("/login/refresh", auth(userHandler.RefreshToken)).Methods("POST")
+	r.HandleFunc("/register", authLimit(audit(domain.AuditRegister)(userHandler.Register))).Methods("POST")
+	r.HandleFunc("/password/forgot", authLimit(audit(domain.AuditPasswordForgot)(userHandler.ForgotPassword))).Methods("POST")
+	r.HandleFunc("/password/reset", authLimit(audit(domain.AuditPasswordReset)(userHandler.ResetPassword))).Methods("POST")
+	r.HandleFunc("/email/verify", authLimit(userHandler.VerifyEmail)).Methods("POST")
+
+	if cfg.SSOService != nil {
+		ssoHandler := NewSSOHandler(cfg.SSOService)
+		r.HandleFunc("/auth/oidc/login", authLimit(ssoHandler.Login)).Methods("GET")
+		r.HandleFunc("/auth/oidc/callback", authLimit(audit(domain.AuditLoginSSO)(ssoHandler.Callback))).Methods("GET")
+	}
+
+	r.HandleFunc("/users", auth(userHandler.GetUsers)).Methods("GET")
+	r.HandleFunc("/users", auth(audit(domain.AuditUserCreate)(userHandler.CreateUser))).Methods("POST")
+	r.HandleFunc("/users/{id}", auth(audit(domain.AuditUserUpdate)(userHandler.UpdateUser))).Methods("PATCH")
+	r.HandleFunc("/users/{id}", auth(audit(domain.AuditUserDelete)(RequireAdmin(userHandler.DeleteUser)))).Methods("DELETE")
+	r.HandleFunc("/users/{id}/unlock", auth(audit(domain.AuditUserUnlock)(RequireAdmin(userHandler.UnlockUser)))).Methods("POST")
+	r.HandleFunc("/profile", auth(userHandler.GetProfile)).Methods("GET")
+	r.HandleFunc("/profile/password", auth(audit(domain.AuditPasswordChange)(userHandler.ChangePassword))).Methods("PUT")
+	r.HandleFunc("/email/verify/resend", auth(userHandler.ResendVerification)).Methods("POST")
+
+	r.HandleFunc("/profile/2fa/setup", enrollAuth(twoFactorHandler.Setup)).Methods("POST")
+	r.HandleFunc("/profile/2fa/enable", enrollAuth(audit(domain.AuditTwoFactorEnable)(twoFactorHandler.Enable))).Methods("POST")
+	r.HandleFunc("/profile/2fa/disable", auth(audit(domain.AuditTwoFactorDisable)(twoFactorHandler.Disable))).Methods("POST")
+	r.HandleFunc("/profile/2fa/recovery-codes", auth(audit(domain.AuditRecoveryCodes)(twoFactorHandler.RegenerateRecoveryCodes))).Methods("POST")
+	r.HandleFunc("/admin/settings/2fa", auth(RequireAdmin(twoFactorHandler.GetSettings))).Methods("GET")
+	r.HandleFunc("/admin/settings/2fa", auth(audit(domain.AuditSettingsUpdate)(RequireAdmin(twoFactorHandler.UpdateSettings)))).Methods("PUT")
+
+	r.HandleFunc("/organization", auth(orgHandler.GetOrganization)).Methods("GET")
+	r.HandleFunc("/api/sites", auth(orgHandler.ListSites)).Methods("GET")
+	r.HandleFunc("/api/sites", auth(audit(domain.AuditSiteCreate)(RequireAdmin(orgHandler.CreateSite)))).Methods("POST")
+	r.HandleFunc("/api/sites/{id}", auth(audit(domain.AuditSiteDelete)(RequireAdmin(orgHandler.DeleteSite)))).Methods("DELETE")
+
+	r.HandleFunc("/api/keys", auth(apiKeyHandler.ListAPIKeys)).Methods("GET")
+	r.HandleFunc("/api/keys", auth(audit(domain.AuditAPIKeyCreate)(apiKeyHandler.CreateAPIKey))).Methods("POST")
+	r.HandleFunc("/api/keys/{id}", auth(audit(domain.AuditAPIKeyRevoke)(apiKeyHandler.RevokeAPIKey))).Methods("DELETE")
+
+	r.HandleFunc("/api/senders", auth(senderHandler.ListSenders)).Methods("GET")
+	r.HandleFunc("/api/senders", auth(audit(domain.AuditSenderCreate)(senderHandler.CreateSender))).Methods("POST")
+	r.HandleFunc("/api/senders/{id}", auth(audit(domain.AuditSenderRevoke)(senderHandler.RevokeSender))).Methods("DELETE")
+
+	r.HandleFunc("/api/audit", auth(RequireAdmin(auditHandler.ListAudit))).Methods("GET")
+	r.HandleFunc("/api/audit/export", auth(audit(domain.AuditExport)(RequireAdmin(auditHandler.ExportAudit)))).Methods("GET")
+
+	ingestAuth := authenticated(SignatureMiddleware(cfg.SignatureService, AuthMiddleware(cfg.JWTSecret, cfg.UserService, cfg.APIKeyService, domain.ScopeEventsWrite)), service.RateLimitGroupEvents)
+	r.HandleFunc("/api/events", ingestAuth(eventHandler.CreateEvents)).Methods("POST")
+
+	statsAuth := authenticated(AuthMiddleware(cfg.JWTSecret, cfg.UserService, cfg.APIKeyService, domain.ScopeStatsRead), service.RateLimitGroupAPI)
+	r.HandleFunc("/api/stats/daily", statsAuth(eventHandler.GetDailyStats)).Methods("GET")
+	if cfg.GraphQL != nil {
+		r.HandleFunc("/graphql", statsAuth(cfg.GraphQL.ServeHTTP)).Methods("POST")
+	}
+
+	r.Use(Tracing)
+	r.Use(Metrics)
+	r.Use(Timeout(cfg.Timeout, cfg.RouteTimeouts))
+
+	return r
+}
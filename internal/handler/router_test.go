@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/metrics"
+	"github.com/nathaliaoliveira/goapp/internal/openapi"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubSSOService só existe para que NewRouter registre as rotas /auth/oidc/*.
+type stubSSOService struct{}
+
+func (stubSSOService) Begin(ctx context.Context, clientIP string) (*domain.SSOStart, error) {
+	return nil, nil
+}
+
+func (stubSSOService) Complete(ctx context.Context, state, code string) (*domain.AuthResponse, error) {
+	return nil, nil
+}
+
+func registeredRoutes(t *testing.T, r *mux.Router) []string {
+	t.Helper()
+
+	var routes []string
+	err := r.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return err
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return err
+		}
+		for _, method := range methods {
+			routes = append(routes, method+" "+path)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	return routes
+}
+
+func TestNewRouter_EveryRouteIsDocumented(t *testing.T) {
+	routes := registeredRoutes(t, NewRouter(RouterConfig{SSOService: stubSSOService{}, GraphQL: http.NotFoundHandler()}))
+	require.NotEmpty(t, routes)
+
+	for _, route := range routes {
+		assert.Contains(t, openapi.Routes(), route, "rota sem documentação em internal/openapi/routes.go")
+	}
+	for _, documented := range openapi.Routes() {
+		assert.Contains(t, routes, documented, "rota documentada mas não registrada")
+	}
+}
+
+func TestNewRouter_OptionalRoutesOnlyWhenConfigured(t *testing.T) {
+	routes := registeredRoutes(t, NewRouter(RouterConfig{}))
+
+	assert.NotContains(t, routes, "GET /auth/oidc/login")
+	assert.NotContains(t, routes, "POST /graphql")
+	assert.Contains(t, routes, "POST /login")
+}
+
+func TestNewRouter_CountsUnmatchedRequests(t *testing.T) {
+	router := NewRouter(RouterConfig{})
+	notFound := metrics.HTTPRequests.WithLabelValues("unmatched", http.MethodGet, "404")
+	notAllowed := metrics.HTTPRequests.WithLabelValues("unmatched", http.MethodPut, "405")
+	before404, before405 := testutil.ToFloat64(notFound), testutil.ToFloat64(notAllowed)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/nao-existe", nil))
+	require.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/login", nil))
+	require.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+	assert.Equal(t, before404+1, testutil.ToFloat64(notFound))
+	assert.Equal(t, before405+1, testutil.ToFloat64(notAllowed))
+}
+
+func TestDocsHandler_ServesSpecAndUI(t *testing.T) {
+	r := NewRouter(RouterConfig{})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	var doc openapi.Document
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	assert.Equal(t, openapi.Version, doc.OpenAPI)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "/openapi.json")
+	assert.Contains(t, w.Body.String(), `src="/docs/assets/swagger-ui-bundle.js"`, "o Swagger UI é servido pelo binário, sem CDN")
+	assert.NotContains(t, w.Body.String(), "persistAuthorization", "credenciais não ficam no localStorage")
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs/assets/README.md", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs/assets/inexistente.js", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
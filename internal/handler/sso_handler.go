@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/metrics"
+	"github.com/nathaliaoliveira/goapp/internal/repository"
+	"github.com/nathaliaoliveira/goapp/internal/service"
+)
+
+const (
+	ssoStateCookie = "oidc_state"
+	ssoCookiePath  = "/auth/oidc"
+)
+
+type SSOHandler struct {
+	ssoService service.SSOService
+}
+
+func NewSSOHandler(ssoService service.SSOService) *SSOHandler {
+	return &SSOHandler{
+		ssoService: ssoService,
+	}
+}
+
+// Login redireciona para o provedor. O state também vai em um cookie, para que
+// o callback só seja aceito no mesmo navegador que iniciou o login.
+func (h *SSOHandler) Login(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Login SSO iniciado", "remote_addr", r.RemoteAddr)
+
+	start, err := h.ssoService.Begin(r.Context(), clientIP(r))
+	if err != nil {
+		slog.WarnContext(r.Context(), "Erro ao iniciar login SSO", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     ssoStateCookie,
+		Value:    start.State,
+		Path:     ssoCookiePath,
+		MaxAge:   600,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	http.Redirect(w, r, start.AuthURL, http.StatusFound)
+}
+
+func (h *SSOHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	if providerErr := query.Get("error"); providerErr != "" {
+		slog.WarnContext(r.Context(), "Provedor OIDC recusou o login", "provider_error", providerErr, "description", query.Get("error_description"))
+		writeProblem(w, r, http.StatusUnauthorized, domain.ProblemSSORejected, "sso.rejected")
+		return
+	}
+
+	state := query.Get("state")
+	cookie, err := r.Cookie(ssoStateCookie)
+	if err != nil || state == "" || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(state)) != 1 {
+		slog.WarnContext(r.Context(), "State do callback SSO não confere", "remote_addr", r.RemoteAddr)
+		writeProblem(w, r, http.StatusBadRequest, domain.ProblemSSOStateInvalid, "sso.state_invalid")
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     ssoStateCookie,
+		Path:     ssoCookiePath,
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	authResp, err := h.ssoService.Complete(r.Context(), state, query.Get("code"))
+	observeLogin(metrics.LoginSSO, authResp, err)
+	if err != nil {
+		slog.WarnContext(r.Context(), "Erro no login SSO", "error", err)
+		// A identidade aponta para um usuário removido: não é um 404 do cliente.
+		var notLinked *repository.UserNotFoundError
+		if errors.As(err, &notLinked) {
+			writeProblem(w, r, http.StatusForbidden, domain.ProblemSSOUserNotLinked, "sso.user_not_linked")
+			return
+		}
+		writeError(w, r, err)
+		return
+	}
+	auditUser(r, authResp.User)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(authResp)
+}
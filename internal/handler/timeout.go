@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Timeout limita o tempo de cada requisição pelo contexto repassado aos serviços.
+// O limite padrão vale para todas as rotas; routes permite sobrescrevê-lo pelo
+// template do caminho (ex.: "/api/audit/export"). Limites <= 0 desativam o prazo.
+func Timeout(defaultTimeout time.Duration, routes map[string]time.Duration) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			timeout := defaultTimeout
+			if route := mux.CurrentRoute(r); route != nil {
+				if tpl, err := route.GetPathTemplate(); err == nil {
+					if d, ok := routes[tpl]; ok {
+						timeout = d
+					}
+				}
+			}
+
+			if timeout <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+
+	"github.com/nathaliaoliveira/goapp/internal/i18n"
+	"github.com/nathaliaoliveira/goapp/internal/service"
+)
+
+type TwoFactorHandler struct {
+	twoFactorService service.TwoFactorService
+}
+
+func NewTwoFactorHandler(twoFactorService service.TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		twoFactorService: twoFactorService,
+	}
+}
+
+func (h *TwoFactorHandler) Setup(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(int)
+	slog.InfoContext(r.Context(), "Configuração de 2FA solicitada", "user_id", userID)
+
+	setup, err := h.twoFactorService.Setup(r.Context(), userID)
+	if err != nil {
+		slog.WarnContext(r.Context(), "Erro ao configurar 2FA", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	response := domain.Response{
+		Message: i18n.Translate(r.Context(), "two_factor.setup_started"),
+		Data:    setup,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *TwoFactorHandler) Enable(w http.ResponseWriter, r *http.Request) {
+	var req domain.TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, domain.ProblemInvalidBody, "request.invalid_body")
+		return
+	}
+
+	userID := r.Context().Value("user_id").(int)
+
+	codes, err := h.twoFactorService.Enable(r.Context(), userID, req.Code)
+	if err != nil {
+		slog.WarnContext(r.Context(), "Erro ao ativar 2FA", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "2FA ativado", "user_id", userID)
+
+	response := domain.Response{
+		Message: i18n.Translate(r.Context(), "two_factor.enabled"),
+		Data:    codes,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *TwoFactorHandler) Disable(w http.ResponseWriter, r *http.Request) {
+	var req domain.DisableTwoFactorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, domain.ProblemInvalidBody, "request.invalid_body")
+		return
+	}
+
+	userID := r.Context().Value("user_id").(int)
+
+	if err := h.twoFactorService.Disable(r.Context(), userID, req.Password, req.Code); err != nil {
+		slog.WarnContext(r.Context(), "Erro ao desativar 2FA", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	slog.WarnContext(r.Context(), "2FA desativado", "user_id", userID)
+
+	response := domain.Response{
+		Message: i18n.Translate(r.Context(), "two_factor.disabled"),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	var req domain.TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, domain.ProblemInvalidBody, "request.invalid_body")
+		return
+	}
+
+	userID := r.Context().Value("user_id").(int)
+
+	codes, err := h.twoFactorService.RegenerateRecoveryCodes(r.Context(), userID, req.Code)
+	if err != nil {
+		slog.WarnContext(r.Context(), "Erro ao gerar códigos de recuperação", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	response := domain.Response{
+		Message: i18n.Translate(r.Context(), "two_factor.recovery_codes_regenerated"),
+		Data:    codes,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *TwoFactorHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	required, err := h.twoFactorService.IsRequired(r.Context(), actorFromRequest(r).OrgID)
+	if err != nil {
+		slog.WarnContext(r.Context(), "Erro ao consultar configuração de 2FA", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	response := domain.Response{
+		Message: i18n.Translate(r.Context(), "two_factor.settings"),
+		Data:    domain.TwoFactorSettings{Required: required},
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *TwoFactorHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	var req domain.TwoFactorSettings
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, domain.ProblemInvalidBody, "request.invalid_body")
+		return
+	}
+
+	actor := actorFromRequest(r)
+
+	if err := h.twoFactorService.SetRequired(r.Context(), actor, req.Required); err != nil {
+		slog.WarnContext(r.Context(), "Erro ao alterar configuração de 2FA", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Configuração de 2FA obrigatório alterada", "required", req.Required, "actor_id", actor.UserID)
+
+	response := domain.Response{
+		Message: i18n.Translate(r.Context(), "two_factor.settings_updated"),
+		Data:    req,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
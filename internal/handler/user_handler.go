@@ -2,43 +2,56 @@ package handler
 
 import (
     "encoding/json"
-    "log"
+    "log/slog"
     "net/http"
+    "strconv"
+    "time"
 
+    "github.com/gorilla/mux"
     "github.com/nathaliaoliveira/goapp/internal/domain"
-    "github.com/nathaliaoliveira/goapp/internal/repository"
+    "github.com/nathaliaoliveira/goapp/internal/i18n"
+    "github.com/nathaliaoliveira/goapp/internal/metrics"
     "github.com/nathaliaoliveira/goapp/internal/service"
 )
 
 type UserHandler struct {
-    userService service.UserService
+    userService    service.UserService
+    accountService service.AccountService
 }
 
-func NewUserHandler(userService service.UserService) *UserHandler {
+func NewUserHandler(userService service.UserService, accountService service.AccountService) *UserHandler {
     return &UserHandler{
-        userService: userService,
+        userService:    userService,
+        accountService: accountService,
     }
 }
 
 func (h *UserHandler) Register(w http.ResponseWriter, r *http.Request) {
-    log.Printf("📝 Requisição de registro recebida de: %s", r.RemoteAddr)
+    slog.DebugContext(r.Context(), "Requisição de registro recebida", "remote_addr", r.RemoteAddr)
     
     var registerReq domain.RegisterRequest
     if err := json.NewDecoder(r.Body).Decode(&registerReq); err != nil {
-        log.Printf("❌ Dados de registro inválidos: %v", err)
-        http.Error(w, "Dados inválidos", http.StatusBadRequest)
+        slog.WarnContext(r.Context(), "Dados de registro inválidos", "error", err)
+        writeProblem(w, r, http.StatusBadRequest, domain.ProblemInvalidBody, "request.invalid_body")
         return
     }
     
-    user, err := h.userService.Register(registerReq.Name, registerReq.Email, registerReq.Password)
+    auditEmail(r, registerReq.Email)
+    user, err := h.userService.Register(r.Context(), registerReq.Organization, registerReq.Name, registerReq.Email, registerReq.Password)
     if err != nil {
-        log.Printf("❌ Erro no registro: %v", err)
-        h.handleServiceError(w, err)
+        slog.WarnContext(r.Context(), "Erro no registro", "error", err)
+        writeError(w, r, err)
         return
     }
     
+    auditUser(r, user)
+    
+    if err := h.accountService.SendVerification(r.Context(), user); err != nil {
+        slog.WarnContext(r.Context(), "Erro ao enviar confirmação de email", "error", err)
+    }
+    
     response := domain.Response{
-        Message: "Usuário criado com sucesso",
+        Message: i18n.Translate(r.Context(), "user.registered"),
         Data:    user,
     }
     
@@ -47,40 +60,180 @@ func (h *UserHandler) Register(w http.ResponseWriter, r *http.Request) {
     json.NewEncoder(w).Encode(response)
 }
 
+func (h *UserHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+    slog.DebugContext(r.Context(), "Requisição de recuperação de senha recebida", "remote_addr", r.RemoteAddr)
+    
+    var req domain.ForgotPasswordRequest
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        writeProblem(w, r, http.StatusBadRequest, domain.ProblemInvalidBody, "request.invalid_body")
+        return
+    }
+    
+    auditEmail(r, req.Email)
+    if err := h.accountService.RequestPasswordReset(r.Context(), req.Email); err != nil {
+        slog.WarnContext(r.Context(), "Erro na recuperação de senha", "error", err)
+        writeError(w, r, err)
+        return
+    }
+    
+    response := domain.Response{
+        Message: i18n.Translate(r.Context(), "password.reset_requested"),
+    }
+    
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(http.StatusAccepted)
+    json.NewEncoder(w).Encode(response)
+}
+
+func (h *UserHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+    slog.DebugContext(r.Context(), "Requisição de redefinição de senha recebida", "remote_addr", r.RemoteAddr)
+    
+    var req domain.ResetPasswordRequest
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        writeProblem(w, r, http.StatusBadRequest, domain.ProblemInvalidBody, "request.invalid_body")
+        return
+    }
+    
+    if err := h.accountService.ResetPassword(r.Context(), req.Token, req.Password); err != nil {
+        slog.WarnContext(r.Context(), "Erro na redefinição de senha", "error", err)
+        writeError(w, r, err)
+        return
+    }
+    
+    response := domain.Response{
+        Message: i18n.Translate(r.Context(), "password.reset_done"),
+    }
+    
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(response)
+}
+
+func (h *UserHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+    var req domain.VerifyEmailRequest
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        writeProblem(w, r, http.StatusBadRequest, domain.ProblemInvalidBody, "request.invalid_body")
+        return
+    }
+    
+    if err := h.accountService.VerifyEmail(r.Context(), req.Token); err != nil {
+        slog.WarnContext(r.Context(), "Erro na confirmação de email", "error", err)
+        writeError(w, r, err)
+        return
+    }
+    
+    response := domain.Response{
+        Message: i18n.Translate(r.Context(), "account.email_verified"),
+    }
+    
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(response)
+}
+
+func (h *UserHandler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+    userID := r.Context().Value("user_id").(int)
+    
+    if err := h.accountService.ResendVerification(r.Context(), userID); err != nil {
+        slog.WarnContext(r.Context(), "Erro ao reenviar confirmação de email", "error", err)
+        writeError(w, r, err)
+        return
+    }
+    
+    response := domain.Response{
+        Message: i18n.Translate(r.Context(), "account.verification_resent"),
+    }
+    
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(http.StatusAccepted)
+    json.NewEncoder(w).Encode(response)
+}
+
 func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
-    log.Printf("🔐 Requisição de login recebida de: %s", r.RemoteAddr)
+    slog.DebugContext(r.Context(), "Requisição de login recebida", "remote_addr", r.RemoteAddr)
     
     var loginReq domain.LoginRequest
     if err := json.NewDecoder(r.Body).Decode(&loginReq); err != nil {
-        log.Printf("❌ Dados de login inválidos: %v", err)
-        http.Error(w, "Dados inválidos", http.StatusBadRequest)
+        slog.WarnContext(r.Context(), "Dados de login inválidos", "error", err)
+        writeProblem(w, r, http.StatusBadRequest, domain.ProblemInvalidBody, "request.invalid_body")
+        return
+    }
+    
+    auditEmail(r, loginReq.Email)
+    authResp, err := h.userService.Login(r.Context(), loginReq.Email, loginReq.Password, clientIP(r))
+    observeLogin(metrics.LoginPassword, authResp, err)
+    if err != nil {
+        slog.WarnContext(r.Context(), "Erro no login", "error", err)
+        writeError(w, r, err)
+        return
+    }
+    auditUser(r, authResp.User)
+    
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(authResp)
+}
+
+func (h *UserHandler) LoginMFA(w http.ResponseWriter, r *http.Request) {
+    slog.DebugContext(r.Context(), "Requisição de login com 2FA recebida", "remote_addr", r.RemoteAddr)
+    
+    var req domain.MFALoginRequest
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        slog.WarnContext(r.Context(), "Dados de login com 2FA inválidos", "error", err)
+        writeProblem(w, r, http.StatusBadRequest, domain.ProblemInvalidBody, "request.invalid_body")
         return
     }
     
-    authResp, err := h.userService.Login(loginReq.Email, loginReq.Password)
+    authResp, err := h.userService.CompleteMFALogin(r.Context(), req.MFAToken, req.Code, clientIP(r))
+    observeLogin(metrics.LoginTwoFactor, authResp, err)
     if err != nil {
-        log.Printf("❌ Erro no login: %v", err)
-        h.handleServiceError(w, err)
+        slog.WarnContext(r.Context(), "Erro no login com 2FA", "error", err)
+        writeError(w, r, err)
         return
     }
+    auditUser(r, authResp.User)
     
     w.Header().Set("Content-Type", "application/json")
     json.NewEncoder(w).Encode(authResp)
 }
 
+// RefreshToken troca um token de sessão ainda válido por um novo. Chaves de API
+// não são aceitas: o AuthMiddleware da rota não recebe escopos.
+func (h *UserHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
+    actor := actorFromRequest(r)
+    authTime, _ := r.Context().Value("auth_time").(time.Time)
+
+    authResp, err := h.userService.RefreshSession(r.Context(), actor.UserID, authTime)
+    if err != nil {
+        slog.WarnContext(r.Context(), "Erro ao renovar token", "error", err)
+        writeError(w, r, err)
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(authResp)
+}
+
 func (h *UserHandler) GetUsers(w http.ResponseWriter, r *http.Request) {
-    log.Printf("👥 Requisição de listagem de usuários recebida de: %s", r.RemoteAddr)
+    slog.DebugContext(r.Context(), "Requisição de listagem de usuários recebida", "remote_addr", r.RemoteAddr)
     
-    users, err := h.userService.GetAll()
+    query := r.URL.Query()
+    page, _ := strconv.Atoi(query.Get("page"))
+    pageSize, _ := strconv.Atoi(query.Get("page_size"))
+    
+    result, err := h.userService.List(r.Context(), actorFromRequest(r), domain.UserListParams{
+        Page:      page,
+        PageSize:  pageSize,
+        Search:    query.Get("search"),
+        SortBy:    query.Get("sort"),
+        SortOrder: query.Get("order"),
+    })
     if err != nil {
-        log.Printf("❌ Erro ao listar usuários: %v", err)
-        h.handleServiceError(w, err)
+        slog.WarnContext(r.Context(), "Erro ao listar usuários", "error", err)
+        writeError(w, r, err)
         return
     }
     
     response := domain.Response{
-        Message: "Usuários encontrados",
-        Data:    users,
+        Message: i18n.Translate(r.Context(), "user.listed"),
+        Data:    result,
     }
     
     w.Header().Set("Content-Type", "application/json")
@@ -88,29 +241,26 @@ func (h *UserHandler) GetUsers(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
-    log.Printf("👤 Requisição de criação de usuário recebida de: %s", r.RemoteAddr)
+    slog.DebugContext(r.Context(), "Requisição de criação de usuário recebida", "remote_addr", r.RemoteAddr)
     
-    var createUserReq struct {
-        Name     string `json:"name"`
-        Email    string `json:"email"`
-        Password string `json:"password"`
-    }
+    var createUserReq domain.CreateUserRequest
     
     if err := json.NewDecoder(r.Body).Decode(&createUserReq); err != nil {
-        log.Printf("❌ Dados inválidos para criar usuário: %v", err)
-        http.Error(w, err.Error(), http.StatusBadRequest)
+        slog.WarnContext(r.Context(), "Dados inválidos para criar usuário", "error", err)
+        writeProblem(w, r, http.StatusBadRequest, domain.ProblemInvalidBody, "request.invalid_body")
         return
     }
     
-    user, err := h.userService.Create(createUserReq.Name, createUserReq.Email, createUserReq.Password)
+    user, err := h.userService.Create(r.Context(), actorFromRequest(r), createUserReq.Name, createUserReq.Email, createUserReq.Password)
     if err != nil {
-        log.Printf("❌ Erro ao criar usuário: %v", err)
-        h.handleServiceError(w, err)
+        slog.WarnContext(r.Context(), "Erro ao criar usuário", "error", err)
+        writeError(w, r, err)
         return
     }
+    auditTarget(r, user.ID)
     
     response := domain.Response{
-        Message: "Usuário criado com sucesso",
+        Message: i18n.Translate(r.Context(), "user.created"),
         Data:    user,
     }
     
@@ -120,19 +270,50 @@ func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *UserHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
-    log.Printf("👤 Requisição de perfil recebida de: %s", r.RemoteAddr)
+    slog.DebugContext(r.Context(), "Requisição de perfil recebida", "remote_addr", r.RemoteAddr)
     
     userID := r.Context().Value("user_id").(int)
     
-    user, err := h.userService.GetByID(userID)
+    user, err := h.userService.GetByID(r.Context(), userID)
+    if err != nil {
+        slog.WarnContext(r.Context(), "Erro ao buscar perfil", "error", err)
+        writeError(w, r, err)
+        return
+    }
+    
+    response := domain.Response{
+        Message: i18n.Translate(r.Context(), "user.profile"),
+        Data:    user,
+    }
+    
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(response)
+}
+
+func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
+    slog.DebugContext(r.Context(), "Requisição de atualização de usuário recebida", "remote_addr", r.RemoteAddr)
+    
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeProblem(w, r, http.StatusBadRequest, domain.ProblemInvalidID, "request.invalid_id")
+        return
+    }
+    
+    var req domain.UpdateUserRequest
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        writeProblem(w, r, http.StatusBadRequest, domain.ProblemInvalidBody, "request.invalid_body")
+        return
+    }
+    
+    user, err := h.userService.Update(r.Context(), actorFromRequest(r), id, req)
     if err != nil {
-        log.Printf("❌ Erro ao buscar perfil: %v", err)
-        h.handleServiceError(w, err)
+        slog.WarnContext(r.Context(), "Erro ao atualizar usuário", "error", err)
+        writeError(w, r, err)
         return
     }
     
     response := domain.Response{
-        Message: "Perfil do usuário",
+        Message: i18n.Translate(r.Context(), "user.updated"),
         Data:    user,
     }
     
@@ -140,21 +321,73 @@ func (h *UserHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
     json.NewEncoder(w).Encode(response)
 }
 
-func (h *UserHandler) handleServiceError(w http.ResponseWriter, err error) {
-    switch e := err.(type) {
-    case *service.ValidationError:
-        http.Error(w, e.Error(), http.StatusBadRequest)
-    case *service.AuthenticationError:
-        http.Error(w, e.Error(), http.StatusUnauthorized)
-    case *service.InternalError:
-        http.Error(w, e.Error(), http.StatusInternalServerError)
-    default:
-        if _, ok := err.(*repository.DuplicateEmailError); ok {
-            http.Error(w, err.Error(), http.StatusConflict)
-        } else if _, ok := err.(*repository.UserNotFoundError); ok {
-            http.Error(w, err.Error(), http.StatusNotFound)
-        } else {
-            http.Error(w, "Erro interno do servidor", http.StatusInternalServerError)
-        }
-    }
-} 
\ No newline at end of file
+func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+    slog.DebugContext(r.Context(), "Requisição de remoção de usuário recebida", "remote_addr", r.RemoteAddr)
+    
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeProblem(w, r, http.StatusBadRequest, domain.ProblemInvalidID, "request.invalid_id")
+        return
+    }
+    
+    if err := h.userService.Delete(r.Context(), actorFromRequest(r), id); err != nil {
+        slog.WarnContext(r.Context(), "Erro ao remover usuário", "error", err)
+        writeError(w, r, err)
+        return
+    }
+    
+    response := domain.Response{
+        Message: i18n.Translate(r.Context(), "user.deleted"),
+    }
+    
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(response)
+}
+
+func (h *UserHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+    slog.DebugContext(r.Context(), "Requisição de troca de senha recebida", "remote_addr", r.RemoteAddr)
+    
+    var req domain.ChangePasswordRequest
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        writeProblem(w, r, http.StatusBadRequest, domain.ProblemInvalidBody, "request.invalid_body")
+        return
+    }
+    
+    userID := r.Context().Value("user_id").(int)
+    
+    if err := h.userService.ChangePassword(r.Context(), userID, req.CurrentPassword, req.NewPassword); err != nil {
+        slog.WarnContext(r.Context(), "Erro ao trocar senha", "error", err)
+        writeError(w, r, err)
+        return
+    }
+    
+    response := domain.Response{
+        Message: i18n.Translate(r.Context(), "password.changed"),
+    }
+    
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(response)
+}
+
+func (h *UserHandler) UnlockUser(w http.ResponseWriter, r *http.Request) {
+    slog.DebugContext(r.Context(), "Requisição de desbloqueio de login recebida", "remote_addr", r.RemoteAddr)
+    
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeProblem(w, r, http.StatusBadRequest, domain.ProblemInvalidID, "request.invalid_id")
+        return
+    }
+    
+    if err := h.userService.UnlockLogin(r.Context(), actorFromRequest(r), id); err != nil {
+        slog.WarnContext(r.Context(), "Erro ao desbloquear login", "error", err)
+        writeError(w, r, err)
+        return
+    }
+    
+    response := domain.Response{
+        Message: i18n.Translate(r.Context(), "user.unlocked"),
+    }
+    
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(response)
+}
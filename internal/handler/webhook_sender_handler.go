@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/i18n"
+	"github.com/nathaliaoliveira/goapp/internal/service"
+)
+
+type WebhookSenderHandler struct {
+	signatureService service.SignatureService
+}
+
+func NewWebhookSenderHandler(signatureService service.SignatureService) *WebhookSenderHandler {
+	return &WebhookSenderHandler{
+		signatureService: signatureService,
+	}
+}
+
+func (h *WebhookSenderHandler) CreateSender(w http.ResponseWriter, r *http.Request) {
+	slog.DebugContext(r.Context(), "Requisição de criação de remetente recebida", "remote_addr", r.RemoteAddr)
+
+	var req domain.CreateWebhookSenderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		slog.WarnContext(r.Context(), "Dados inválidos para criar remetente", "error", err)
+		writeProblem(w, r, http.StatusBadRequest, domain.ProblemInvalidBody, "request.invalid_body")
+		return
+	}
+
+	created, err := h.signatureService.CreateSender(r.Context(), actorFromRequest(r), req)
+	if err != nil {
+		slog.WarnContext(r.Context(), "Erro ao criar remetente", "error", err)
+		writeError(w, r, err)
+		return
+	}
+	auditTarget(r, created.Sender.ID)
+
+	response := domain.Response{
+		Message: i18n.Translate(r.Context(), "sender.created"),
+		Data:    created,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *WebhookSenderHandler) ListSenders(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user_id").(int)
+
+	senders, err := h.signatureService.ListSenders(r.Context(), userID)
+	if err != nil {
+		slog.WarnContext(r.Context(), "Erro ao listar remetentes", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	response := domain.Response{
+		Message: i18n.Translate(r.Context(), "sender.listed"),
+		Data:    senders,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *WebhookSenderHandler) RevokeSender(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, domain.ProblemInvalidID, "request.invalid_id")
+		return
+	}
+
+	userID := r.Context().Value("user_id").(int)
+
+	if err := h.signatureService.RevokeSender(r.Context(), userID, id); err != nil {
+		slog.WarnContext(r.Context(), "Erro ao revogar remetente", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	response := domain.Response{
+		Message: i18n.Translate(r.Context(), "sender.revoked"),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
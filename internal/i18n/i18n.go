@@ -0,0 +1,111 @@
+// Package i18n traduz as mensagens exibidas aos clientes da API. Serviços e
+// handlers trabalham com chaves (ex.: "auth.invalid_credentials"); o texto vem
+// do catálogo do idioma escolhido pelo header Accept-Language.
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Idiomas com catálogo em locales/.
+const (
+	PortugueseBR = "pt-BR"
+	English      = "en"
+
+	// DefaultLanguage é usado quando o cliente não pede um idioma suportado e
+	// como reserva para chaves ausentes em outro catálogo.
+	DefaultLanguage = PortugueseBR
+)
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+var (
+	catalogs = mustLoad()
+	// A ordem define a preferência em caso de empate; o primeiro é o padrão.
+	supported = []language.Tag{language.BrazilianPortuguese, language.English}
+	matcher   = language.NewMatcher(supported)
+)
+
+func mustLoad() map[string]map[string]string {
+	entries, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+
+	loaded := map[string]map[string]string{}
+	for _, entry := range entries {
+		data, err := localeFiles.ReadFile(path.Join("locales", entry.Name()))
+		if err != nil {
+			panic(err)
+		}
+		messages := map[string]string{}
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("catálogo %s inválido: %v", entry.Name(), err))
+		}
+		loaded[strings.TrimSuffix(entry.Name(), ".json")] = messages
+	}
+	return loaded
+}
+
+// Negotiate escolhe o idioma suportado que melhor atende ao header
+// Accept-Language, respeitando os pesos q. Sem correspondência, devolve o padrão.
+func Negotiate(acceptLanguage string) string {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return DefaultLanguage
+	}
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return DefaultLanguage
+	}
+	switch supported[index] {
+	case language.English:
+		return English
+	default:
+		return PortugueseBR
+	}
+}
+
+// T devolve a mensagem da chave no idioma lang, formatada com args no estilo
+// fmt. Chaves ausentes caem no idioma padrão e, por último, na própria chave.
+func T(lang, key string, args ...interface{}) string {
+	message, ok := catalogs[lang][key]
+	if !ok {
+		message, ok = catalogs[DefaultLanguage][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+type languageKey struct{}
+
+// WithLanguage guarda no contexto o idioma negociado para a requisição.
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, languageKey{}, lang)
+}
+
+// Language devolve o idioma da requisição ou o padrão.
+func Language(ctx context.Context) string {
+	if lang, ok := ctx.Value(languageKey{}).(string); ok {
+		return lang
+	}
+	return DefaultLanguage
+}
+
+// Translate é T no idioma guardado em ctx.
+func Translate(ctx context.Context, key string, args ...interface{}) string {
+	return T(Language(ctx), key, args...)
+}
@@ -0,0 +1,55 @@
+package i18n
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var verbPattern = regexp.MustCompile(`%[a-z]`)
+
+func TestCatalogs_HaveSameKeysAndVerbs(t *testing.T) {
+	assert.Len(t, catalogs, 2)
+	for key, message := range catalogs[DefaultLanguage] {
+		translated, ok := catalogs[English][key]
+		if assert.True(t, ok, "chave sem tradução em inglês: %s", key) {
+			assert.Equal(t, verbPattern.FindAllString(message, -1), verbPattern.FindAllString(translated, -1), key)
+		}
+	}
+	for key := range catalogs[English] {
+		assert.Contains(t, catalogs[DefaultLanguage], key)
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := map[string]string{
+		"":                         PortugueseBR,
+		"en":                       English,
+		"en-US,en;q=0.9":           English,
+		"pt-BR,pt;q=0.9,en;q=0.8":  PortugueseBR,
+		"pt":                       PortugueseBR,
+		"fr-FR":                    PortugueseBR,
+		"fr-FR,en;q=0.5":           English,
+		"en;q=0.2,pt-BR;q=0.8":     PortugueseBR,
+		"isto não é um header ;;;": PortugueseBR,
+	}
+
+	for header, want := range tests {
+		assert.Equal(t, want, Negotiate(header), header)
+	}
+}
+
+func TestT_FallsBackToDefaultAndKey(t *testing.T) {
+	assert.Equal(t, "Invalid role: auditor", T(English, "user.invalid_role", "auditor"))
+	assert.Equal(t, "Credenciais inválidas", T("de", "auth.invalid_credentials"))
+	assert.Equal(t, "chave.inexistente", T(English, "chave.inexistente"))
+}
+
+func TestTranslate_UsesContextLanguage(t *testing.T) {
+	ctx := WithLanguage(context.Background(), English)
+
+	assert.Equal(t, "Invalid credentials", Translate(ctx, "auth.invalid_credentials"))
+	assert.Equal(t, "Credenciais inválidas", Translate(context.Background(), "auth.invalid_credentials"))
+}
@@ -0,0 +1,100 @@
+// Package logging configura o log estruturado (log/slog) da aplicação: nível e
+// formato por variável de ambiente, request ID em cada linha e mascaramento de
+// dados pessoais e credenciais.
+package logging
+
+import (
+	"context"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/nathaliaoliveira/goapp/internal/env"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+type Config struct {
+	Level  slog.Level
+	Format string
+}
+
+func NewConfig() *Config {
+	config := &Config{Level: slog.LevelInfo, Format: FormatJSON}
+
+	if value := env.String("LOG_LEVEL", ""); value != "" {
+		if err := config.Level.UnmarshalText([]byte(value)); err != nil {
+			log.Printf("Valor inválido para LOG_LEVEL: %q, usando %s", value, config.Level)
+		}
+	}
+	if value := strings.ToLower(env.String("LOG_FORMAT", "")); value != "" {
+		if value == FormatJSON || value == FormatText {
+			config.Format = value
+		} else {
+			log.Printf("Valor inválido para LOG_FORMAT: %q, usando %s", value, config.Format)
+		}
+	}
+	return config
+}
+
+// New cria um logger que escreve em w com mascaramento e request ID.
+func New(w io.Writer, config *Config) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: config.Level, ReplaceAttr: redactAttr}
+
+	var h slog.Handler
+	if config.Format == FormatText {
+		h = slog.NewTextHandler(w, opts)
+	} else {
+		h = slog.NewJSONHandler(w, opts)
+	}
+	return slog.New(&contextHandler{Handler: h})
+}
+
+// Setup instala o logger como padrão do slog e do pacote log, para que
+// bibliotecas que usam log.Printf passem pelo mesmo formato e mascaramento.
+func Setup(config *Config) *slog.Logger {
+	logger := New(os.Stderr, config)
+	slog.SetDefault(logger)
+	return logger
+}
+
+type requestIDKey struct{}
+
+// WithRequestID associa o ID da requisição ao contexto; os logs feitos com
+// slog.*Context passam a incluí-lo como request_id.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		record.AddAttrs(slog.String("request_id", id))
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		record.AddAttrs(slog.String("trace_id", sc.TraceID().String()), slog.String("span_id", sc.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,77 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func logLine(t *testing.T, fn func(logger *slog.Logger)) map[string]interface{} {
+	var buf bytes.Buffer
+	fn(New(&buf, &Config{Level: slog.LevelDebug, Format: FormatJSON}))
+
+	var line map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	return line
+}
+
+func TestRedact(t *testing.T) {
+	tests := map[string]string{
+		"Usuário joao.silva@exemplo.com criado":                      "Usuário j***@exemplo.com criado",
+		"Authorization: Bearer abc.def":                              "Authorization: Bearer [REDACTED]",
+		"token eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOjF9.c2lnbmF0dXJh usado": "token [REDACTED] usado",
+		"chave evk_0123456789abcdef0123 inválida":                    "chave evk_01234567[REDACTED] inválida",
+		"sem dados sensíveis":                                        "sem dados sensíveis",
+	}
+	for input, expected := range tests {
+		assert.Equal(t, expected, Redact(input), input)
+	}
+}
+
+func TestLogger_RedactsAttributesMessageAndErrors(t *testing.T) {
+	line := logLine(t, func(logger *slog.Logger) {
+		logger.Info("Login de maria@exemplo.com",
+			"email", "maria@exemplo.com",
+			"password", "segredo123",
+			"new_password", "outro",
+			"code", "123456",
+			"status_code", 200,
+			"error", errors.New("Email já cadastrado: maria@exemplo.com"))
+	})
+
+	assert.Equal(t, "Login de m***@exemplo.com", line["msg"])
+	assert.Equal(t, "m***@exemplo.com", line["email"])
+	assert.Equal(t, "[REDACTED]", line["password"])
+	assert.Equal(t, "[REDACTED]", line["new_password"])
+	assert.Equal(t, "[REDACTED]", line["code"])
+	assert.Equal(t, float64(200), line["status_code"])
+	assert.Equal(t, "Email já cadastrado: m***@exemplo.com", line["error"])
+}
+
+func TestLogger_AddsRequestID(t *testing.T) {
+	ctx := WithRequestID(context.Background(), "req-1")
+
+	line := logLine(t, func(logger *slog.Logger) {
+		logger.With("component", "teste").InfoContext(ctx, "mensagem")
+	})
+
+	assert.Equal(t, "req-1", line["request_id"])
+	assert.Equal(t, "teste", line["component"])
+}
+
+func TestLogger_RespectsLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, &Config{Level: slog.LevelWarn, Format: FormatText})
+
+	logger.Info("ignorada")
+	assert.Empty(t, buf.String())
+
+	logger.Warn("registrada")
+	assert.Contains(t, buf.String(), "msg=registrada")
+}
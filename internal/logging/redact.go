@@ -0,0 +1,61 @@
+package logging
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+var (
+	emailPattern  = regexp.MustCompile(`([A-Za-z0-9._%+\-])[A-Za-z0-9._%+\-]*@([A-Za-z0-9.\-]+\.[A-Za-z]{2,})`)
+	jwtPattern    = regexp.MustCompile(`eyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]*`)
+	bearerPattern = regexp.MustCompile(`(?i)\b(Bearer|ApiKey)\s+\S+`)
+	// Chaves de API completas mantêm só o prefixo público (evk_ + 8 caracteres).
+	apiKeyPattern = regexp.MustCompile(`(evk_[0-9a-f]{8})[0-9a-f]+`)
+)
+
+// Atributos cujo valor nunca vai para o log: secretKeys pelo nome exato e
+// secretFragments por qualquer nome que os contenha (new_password, id_token...).
+var (
+	secretKeys      = map[string]bool{"code": true, "api_key": true, "recovery_codes": true}
+	secretFragments = []string{"password", "senha", "secret", "token", "authorization", "cookie"}
+)
+
+// redactAttr é o ReplaceAttr dos handlers: remove credenciais por nome de
+// atributo e mascara emails e tokens encontrados em qualquer texto, inclusive
+// na mensagem e nos erros.
+func redactAttr(groups []string, a slog.Attr) slog.Attr {
+	key := strings.ToLower(a.Key)
+	if secretKeys[key] {
+		return slog.String(a.Key, redacted)
+	}
+	for _, fragment := range secretFragments {
+		if strings.Contains(key, fragment) {
+			return slog.String(a.Key, redacted)
+		}
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, Redact(a.Value.String()))
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok {
+			return slog.String(a.Key, Redact(err.Error()))
+		}
+	}
+	return a
+}
+
+// Redact mascara emails (j***@exemplo.com), tokens JWT, credenciais em
+// cabeçalhos Authorization e chaves de API presentes em s.
+func Redact(s string) string {
+	if s == "" {
+		return s
+	}
+	s = jwtPattern.ReplaceAllString(s, redacted)
+	s = bearerPattern.ReplaceAllString(s, "$1 "+redacted)
+	s = apiKeyPattern.ReplaceAllString(s, "$1"+redacted)
+	return emailPattern.ReplaceAllString(s, "$1***@$2")
+}
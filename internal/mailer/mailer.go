@@ -0,0 +1,26 @@
+package mailer
+
+import "log/slog"
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(msg Message) error
+}
+
+type logMailer struct{}
+
+// NewLogMailer é usado quando o SMTP não está configurado: registra apenas
+// destinatário e assunto, nunca o corpo, que pode conter tokens.
+func NewLogMailer() Mailer {
+	return &logMailer{}
+}
+
+func (m *logMailer) Send(msg Message) error {
+	slog.Info("SMTP não configurado, email descartado", "to", msg.To, "subject", msg.Subject)
+	return nil
+}
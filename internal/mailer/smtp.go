@@ -0,0 +1,71 @@
+package mailer
+
+import (
+	"fmt"
+	"mime"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/nathaliaoliveira/goapp/internal/env"
+)
+
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+func NewSMTPConfig() *SMTPConfig {
+	return &SMTPConfig{
+		Host:     env.String("SMTP_HOST", ""),
+		Port:     env.String("SMTP_PORT", "587"),
+		Username: env.String("SMTP_USERNAME", ""),
+		Password: env.String("SMTP_PASSWORD", ""),
+		From:     env.String("SMTP_FROM", "no-reply@localhost"),
+	}
+}
+
+type smtpMailer struct {
+	config *SMTPConfig
+	send   func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+func NewSMTPMailer(config *SMTPConfig) Mailer {
+	return &smtpMailer{
+		config: config,
+		send:   smtp.SendMail,
+	}
+}
+
+func (m *smtpMailer) Send(msg Message) error {
+	var auth smtp.Auth
+	if m.config.Username != "" {
+		auth = smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+	}
+
+	addr := m.config.Host + ":" + m.config.Port
+	to := headerSanitizer.Replace(msg.To)
+	if err := m.send(addr, auth, m.config.From, []string{to}, m.build(msg)); err != nil {
+		return fmt.Errorf("erro ao enviar email: %w", err)
+	}
+	return nil
+}
+
+var headerSanitizer = strings.NewReplacer("\r", "", "\n", "")
+
+func (m *smtpMailer) build(msg Message) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + headerSanitizer.Replace(m.config.From) + "\r\n")
+	b.WriteString("To: " + headerSanitizer.Replace(msg.To) + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject) + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
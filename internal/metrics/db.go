@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// DB é o conjunto de métodos usado pelos repositórios (repository.DBInterface).
+type DB interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// InstrumentDB mede a latência de cada consulta em DBQueryDuration.
+func InstrumentDB(db DB) DB {
+	return &instrumentedDB{db: db}
+}
+
+type instrumentedDB struct {
+	db DB
+}
+
+func (d *instrumentedDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	defer observeQuery("query_row", time.Now())
+	return d.db.QueryRowContext(ctx, query, args...)
+}
+
+func (d *instrumentedDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	defer observeQuery("exec", time.Now())
+	return d.db.ExecContext(ctx, query, args...)
+}
+
+func (d *instrumentedDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	defer observeQuery("query", time.Now())
+	return d.db.QueryContext(ctx, query, args...)
+}
+
+func observeQuery(operation string, start time.Time) {
+	DBQueryDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,47 @@
+package metrics
+
+import "sync"
+
+const (
+	// LabelOther agrupa os valores que chegaram depois de atingido o limite.
+	LabelOther = "other"
+	// LabelNone representa valores vazios.
+	LabelNone = "none"
+)
+
+// LabelLimiter aceita no máximo max valores distintos para um rótulo, na ordem
+// em que aparecem; os seguintes são contados como LabelOther. Assim um cliente
+// enviando sites ou tipos arbitrários não cria séries sem limite.
+type LabelLimiter struct {
+	max  int
+	mu   sync.RWMutex
+	seen map[string]struct{}
+}
+
+func NewLabelLimiter(max int) *LabelLimiter {
+	return &LabelLimiter{max: max, seen: make(map[string]struct{})}
+}
+
+func (l *LabelLimiter) Label(value string) string {
+	if value == "" {
+		return LabelNone
+	}
+
+	l.mu.RLock()
+	_, ok := l.seen[value]
+	l.mu.RUnlock()
+	if ok {
+		return value
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.seen[value]; ok {
+		return value
+	}
+	if len(l.seen) >= l.max {
+		return LabelOther
+	}
+	l.seen[value] = struct{}{}
+	return value
+}
@@ -0,0 +1,120 @@
+// Package metrics define as métricas Prometheus da aplicação. Os rótulos que
+// vêm dos clientes (site e tipo de evento) passam por um LabelLimiter para que
+// a cardinalidade das séries fique limitada.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/nathaliaoliveira/goapp/internal/env"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "goapp"
+
+// Resultados de cada evento recebido em ProcessEvents.
+const (
+	EventProcessed = "processed"
+	EventDuplicate = "duplicate"
+	EventInvalid   = "invalid"
+	EventError     = "error"
+	EventRejected  = "rejected"
+)
+
+// Métodos e resultados de login.
+const (
+	LoginPassword  = "password"
+	LoginTwoFactor = "2fa"
+	LoginSSO       = "sso"
+
+	LoginSuccess     = "success"
+	LoginMFARequired = "mfa_required"
+	LoginFailure     = "failure"
+	LoginLocked      = "locked"
+	LoginError       = "error"
+)
+
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Requisições HTTP por rota, método e status.",
+	}, []string{"route", "method", "status"})
+
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latência das requisições HTTP por rota e método.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method"})
+
+	EventsIngested = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "events_ingested_total",
+		Help:      "Eventos recebidos em /api/events por tipo, site e resultado.",
+	}, []string{"type", "site", "result"})
+
+	LoginAttempts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "login_attempts_total",
+		Help:      "Tentativas de login por método e resultado.",
+	}, []string{"method", "result"})
+
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Latência das consultas ao banco por operação.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"operation"})
+)
+
+// Limites padrão de valores distintos para os rótulos site e type.
+var (
+	Sites      = NewLabelLimiter(100)
+	EventTypes = NewLabelLimiter(20)
+)
+
+// Register registra as métricas da aplicação e as estatísticas do pool de
+// conexões do banco. Os limites de rótulo vêm de METRICS_MAX_SITES e
+// METRICS_MAX_EVENT_TYPES.
+func Register(registerer prometheus.Registerer, db *sql.DB) error {
+	Sites = NewLabelLimiter(env.Int("METRICS_MAX_SITES", Sites.max))
+	EventTypes = NewLabelLimiter(env.Int("METRICS_MAX_EVENT_TYPES", EventTypes.max))
+
+	cs := []prometheus.Collector{HTTPRequests, HTTPDuration, EventsIngested, LoginAttempts, DBQueryDuration}
+	if db != nil {
+		cs = append(cs, collectors.NewDBStatsCollector(db, "goapp"))
+	}
+	for _, c := range cs {
+		if err := registerer.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Handler serve GET /metrics. As métricas cobrem todas as organizações, então
+// o handler fica em uma porta própria (METRICS_PORT), fora da API pública.
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	return mux
+}
+
+// ObserveEvent conta um evento recebido, limitando os rótulos type e site.
+// Eventos recusados ou inválidos contam como LabelOther e não ocupam vagas dos
+// limitadores: o site pode não pertencer à organização e nada foi gravado.
+func ObserveEvent(eventType, site, result string) {
+	if result == EventRejected || result == EventInvalid {
+		EventsIngested.WithLabelValues(LabelOther, LabelOther, result).Inc()
+		return
+	}
+	EventsIngested.WithLabelValues(EventTypes.Label(eventType), Sites.Label(site), result).Inc()
+}
+
+func ObserveLogin(method, result string) {
+	LoginAttempts.WithLabelValues(method, result).Inc()
+}
@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabelLimiter(t *testing.T) {
+	l := NewLabelLimiter(2)
+
+	assert.Equal(t, "a.com", l.Label("a.com"))
+	assert.Equal(t, "b.com", l.Label("b.com"))
+	assert.Equal(t, LabelOther, l.Label("c.com"))
+	assert.Equal(t, "a.com", l.Label("a.com"))
+	assert.Equal(t, LabelNone, l.Label(""))
+}
+
+func TestObserveEvent_BoundsSiteCardinality(t *testing.T) {
+	Sites, EventTypes = NewLabelLimiter(1), NewLabelLimiter(5)
+	EventsIngested.Reset()
+
+	ObserveEvent("open", "a.com", EventProcessed)
+	ObserveEvent("open", "b.com", EventProcessed)
+	ObserveEvent("open", "c.com", EventDuplicate)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(EventsIngested.WithLabelValues("open", "a.com", EventProcessed)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(EventsIngested.WithLabelValues("open", LabelOther, EventProcessed)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(EventsIngested.WithLabelValues("open", LabelOther, EventDuplicate)))
+	assert.Equal(t, 3, testutil.CollectAndCount(EventsIngested))
+}
+
+func TestObserveEvent_RejectedEventsDoNotTakeLabels(t *testing.T) {
+	Sites, EventTypes = NewLabelLimiter(1), NewLabelLimiter(1)
+	EventsIngested.Reset()
+
+	ObserveEvent("spam", "alheio.com", EventRejected)
+	ObserveEvent("", "", EventInvalid)
+	ObserveEvent("open", "a.com", EventProcessed)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(EventsIngested.WithLabelValues(LabelOther, LabelOther, EventRejected)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(EventsIngested.WithLabelValues(LabelOther, LabelOther, EventInvalid)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(EventsIngested.WithLabelValues("open", "a.com", EventProcessed)))
+}
+
+func TestRegister(t *testing.T) {
+	t.Setenv("METRICS_MAX_SITES", "7")
+
+	registry := prometheus.NewRegistry()
+	require.NoError(t, Register(registry, nil))
+	assert.Equal(t, 7, Sites.max)
+
+	ObserveLogin(LoginPassword, LoginSuccess)
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(families))
+	for _, f := range families {
+		names = append(names, f.GetName())
+	}
+	assert.Contains(t, names, "goapp_login_attempts_total")
+}
@@ -0,0 +1,101 @@
+package oidc_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/nathaliaoliveira/goapp/internal/oidc"
+	"github.com/nathaliaoliveira/goapp/internal/oidc/oidctest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var ctx = context.Background()
+
+func newTestProvider(t *testing.T) (*oidctest.Provider, *oidc.Provider) {
+	idp, err := oidctest.NewProvider()
+	require.NoError(t, err)
+	t.Cleanup(idp.Close)
+
+	client := oidc.NewProvider(oidc.Config{
+		IssuerURL:    idp.URL,
+		ClientID:     idp.ClientID,
+		ClientSecret: idp.ClientSecret,
+		RedirectURL:  "http://app.test/auth/oidc/callback",
+	}, nil)
+	return idp, client
+}
+
+func TestAuthCodeFlow(t *testing.T) {
+	idp, client := newTestProvider(t)
+
+	verifier, err := oidc.NewCodeVerifier()
+	require.NoError(t, err)
+
+	authURL, err := client.AuthCodeURL(ctx, "state-1", "nonce-1", verifier)
+	require.NoError(t, err)
+
+	parsed, _ := url.Parse(authURL)
+	assert.Equal(t, "S256", parsed.Query().Get("code_challenge_method"))
+	assert.Equal(t, oidc.CodeChallenge(verifier), parsed.Query().Get("code_challenge"))
+
+	code, state, err := idp.Authorize(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, "state-1", state)
+
+	claims, err := client.Exchange(ctx, code, verifier, "nonce-1")
+	require.NoError(t, err)
+	assert.Equal(t, idp.URL, claims.Issuer)
+	assert.Equal(t, "user-1", claims.Subject)
+	assert.Equal(t, "sso@example.com", claims.Email)
+	assert.True(t, claims.EmailVerified)
+}
+
+func TestExchange_WrongVerifier(t *testing.T) {
+	idp, client := newTestProvider(t)
+
+	authURL, err := client.AuthCodeURL(ctx, "state-1", "nonce-1", "verifier-original")
+	require.NoError(t, err)
+	code, _, err := idp.Authorize(authURL)
+	require.NoError(t, err)
+
+	_, err = client.Exchange(ctx, code, "verifier-diferente", "nonce-1")
+	assert.Error(t, err)
+}
+
+func TestExchange_NonceMismatch(t *testing.T) {
+	idp, client := newTestProvider(t)
+	idp.OverrideNonce("nonce-de-outra-sessao")
+
+	authURL, err := client.AuthCodeURL(ctx, "state-1", "nonce-1", "verifier")
+	require.NoError(t, err)
+	code, _, err := idp.Authorize(authURL)
+	require.NoError(t, err)
+
+	_, err = client.Exchange(ctx, code, "verifier", "nonce-1")
+	assert.ErrorContains(t, err, "nonce")
+}
+
+func TestExchange_CodeIsSingleUse(t *testing.T) {
+	idp, client := newTestProvider(t)
+
+	authURL, err := client.AuthCodeURL(ctx, "state-1", "nonce-1", "verifier")
+	require.NoError(t, err)
+	code, _, err := idp.Authorize(authURL)
+	require.NoError(t, err)
+
+	_, err = client.Exchange(ctx, code, "verifier", "nonce-1")
+	require.NoError(t, err)
+
+	_, err = client.Exchange(ctx, code, "verifier", "nonce-1")
+	assert.Error(t, err)
+}
+
+func TestVerifyIDToken_RejectsUnsignedToken(t *testing.T) {
+	_, client := newTestProvider(t)
+
+	// {"alg":"none"} . {"sub":"user-1","nonce":"n"}
+	_, err := client.VerifyIDToken(ctx, "eyJhbGciOiJub25lIn0.eyJzdWIiOiJ1c2VyLTEiLCJub25jZSI6Im4ifQ.", "n")
+	assert.Error(t, err)
+}
@@ -0,0 +1,154 @@
+// Package openapi monta a especificação OpenAPI 3 da API HTTP. As rotas são
+// descritas em routes.go e os schemas são gerados por reflexão a partir dos
+// tipos do pacote domain, para que a documentação acompanhe o código.
+package openapi
+
+import (
+	"embed"
+	"encoding/json"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+)
+
+// Version é a versão da especificação OpenAPI usada no documento.
+const Version = "3.0.3"
+
+// Document é o subconjunto do OpenAPI 3 usado pela API.
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Tags       []Tag               `json:"tags,omitempty"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem associa o método HTTP em minúsculas (get, post...) à operação.
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary"`
+	Description string                `json:"description,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Security    []map[string][]string `json:"security,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Headers     map[string]Header    `json:"headers,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type Header struct {
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Description  string `json:"description,omitempty"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	Name         string `json:"name,omitempty"`
+	In           string `json:"in,omitempty"`
+}
+
+// Operation devolve a operação documentada para o método e o template de
+// caminho do roteador (ex.: "DELETE", "/users/{id}"), ou nil.
+func (d *Document) Operation(method, path string) *Operation {
+	return d.Paths[path][strings.ToLower(method)]
+}
+
+var (
+	specOnce sync.Once
+	spec     *Document
+	specJSON []byte
+)
+
+// Spec devolve a especificação da API. O documento é montado uma única vez e
+// não deve ser alterado por quem o recebe.
+func Spec() *Document {
+	specOnce.Do(func() {
+		spec = build()
+		var err error
+		if specJSON, err = json.Marshal(spec); err != nil {
+			panic(err)
+		}
+	})
+	return spec
+}
+
+// JSON devolve a especificação já serializada, pronta para ser servida.
+func JSON() []byte {
+	Spec()
+	return specJSON
+}
+
+//go:embed docs.html
+var docsPage []byte
+
+//go:embed swagger-ui
+var swaggerUI embed.FS
+
+// DocsPage devolve a página HTML da documentação interativa, que carrega a
+// especificação de /openapi.json.
+func DocsPage() []byte {
+	return docsPage
+}
+
+// DocsAsset devolve um arquivo do Swagger UI embutido no binário, usado pela
+// página de DocsPage; ver swagger-ui/README.md.
+func DocsAsset(name string) ([]byte, error) {
+	return fs.ReadFile(swaggerUI, path.Join("swagger-ui", path.Clean("/"+name)))
+}
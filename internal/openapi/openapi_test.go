@@ -0,0 +1,100 @@
+package openapi
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemas_FollowJSONTags(t *testing.T) {
+	s := newSchemas()
+
+	assert.Equal(t, "#/components/schemas/User", s.of(domain.User{}).Ref)
+	user := s.components["User"]
+	require.NotNil(t, user)
+	assert.Contains(t, user.Properties, "email_verified")
+	assert.NotContains(t, user.Properties, "PasswordHash")
+	assert.NotContains(t, user.Properties, "-")
+	assert.Equal(t, "date-time", user.Properties["created_at"].Format)
+	assert.Contains(t, user.Required, "email")
+
+	s.of(domain.AuthResponse{})
+	auth := s.components["AuthResponse"]
+	assert.Equal(t, "#/components/schemas/User", auth.Properties["user"].Ref)
+	assert.NotContains(t, auth.Required, "token")
+
+	s.of(domain.EmailEvent{})
+	metadata := s.components["EmailEvent"].Properties["metadata"]
+	assert.Equal(t, "object", metadata.Type)
+	assert.NotNil(t, metadata.AdditionalProperties)
+}
+
+func TestSchemas_InlineAndScalars(t *testing.T) {
+	type embedded struct {
+		ID int64 `json:"id"`
+	}
+	s := newSchemas()
+
+	schema := s.of(struct {
+		embedded
+		Tags    []string  `json:"tags,omitempty"`
+		Raw     []byte    `json:"raw"`
+		Ratio   float64   `json:"ratio"`
+		At      time.Time `json:"at"`
+		private string
+	}{})
+
+	assert.Equal(t, "object", schema.Type)
+	assert.Equal(t, "int64", schema.Properties["id"].Format)
+	assert.Equal(t, "array", schema.Properties["tags"].Type)
+	assert.Equal(t, "byte", schema.Properties["raw"].Format)
+	assert.Equal(t, "number", schema.Properties["ratio"].Type)
+	assert.NotContains(t, schema.Properties, "private")
+	assert.ElementsMatch(t, []string{"id", "raw", "ratio", "at"}, schema.Required)
+}
+
+func TestSpec_RefsResolve(t *testing.T) {
+	raw := string(JSON())
+
+	for _, part := range strings.Split(raw, `"$ref":"#/components/schemas/`)[1:] {
+		name := part[:strings.Index(part, `"`)]
+		assert.Contains(t, Spec().Components.Schemas, name)
+	}
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(JSON(), &doc))
+}
+
+func TestSpec_Operation(t *testing.T) {
+	op := Spec().Operation("DELETE", "/users/{id}")
+	require.NotNil(t, op)
+	assert.Equal(t, "deleteUser", op.OperationID)
+	require.Len(t, op.Parameters, 1)
+	assert.Equal(t, "path", op.Parameters[0].In)
+	assert.Equal(t, []map[string][]string{{SecurityBearer: {}}}, op.Security)
+	assert.Contains(t, op.Responses, "default")
+
+	events := Spec().Operation("POST", "/api/events")
+	require.NotNil(t, events)
+	assert.Len(t, events.Security, 3)
+	assert.Contains(t, events.Responses, "201")
+	assert.Contains(t, events.Responses["429"].Headers, "Retry-After")
+	assert.NotContains(t, Spec().Operation("GET", "/health").Responses, "429")
+
+	assert.Nil(t, Spec().Operation("GET", "/inexistente"))
+}
+
+func TestDocsAsset_OnlyServesSwaggerUIFiles(t *testing.T) {
+	_, err := DocsAsset("README.md")
+	assert.NoError(t, err)
+
+	for _, name := range []string{"../docs.html", "../openapi.go", "inexistente.js"} {
+		_, err = DocsAsset(name)
+		assert.Error(t, err, name)
+	}
+}
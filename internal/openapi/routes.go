@@ -0,0 +1,332 @@
+package openapi
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+)
+
+// Esquemas de autenticação aceitos pela API.
+const (
+	SecurityBearer    = "bearerAuth"
+	SecurityAPIKey    = "apiKeyAuth"
+	SecuritySignature = "signatureAuth"
+)
+
+// route descreve uma rota registrada em handler.NewRouter. Sem data, raw nem
+// success, a resposta é o envelope domain.Response só com a mensagem.
+type route struct {
+	method      string
+	path        string
+	id          string
+	tag         string
+	summary     string
+	description string
+	// Esquemas alternativos: basta um deles para autenticar.
+	security []string
+	query    []Parameter
+	body     interface{}
+	// Status da resposta de sucesso; 200 quando zero.
+	status int
+	// Conteúdo do campo data do envelope domain.Response.
+	data interface{}
+	// Corpo JSON devolvido sem envelope.
+	raw interface{}
+	// Substitui a resposta JSON de sucesso (redirecionamentos, downloads...).
+	success *Response
+	// A rota responde 503 com o mesmo corpo quando alguma dependência falha.
+	unavailable bool
+}
+
+var tags = []Tag{
+	{Name: "auth", Description: "Cadastro, login e recuperação de conta"},
+	{Name: "users", Description: "Usuários da organização"},
+	{Name: "profile", Description: "Conta do usuário autenticado"},
+	{Name: "two-factor", Description: "Autenticação em dois fatores (TOTP)"},
+	{Name: "organization", Description: "Organização e sites"},
+	{Name: "api-keys", Description: "Chaves de API"},
+	{Name: "senders", Description: "Remetentes de requisições assinadas (HMAC)"},
+	{Name: "audit", Description: "Log de auditoria"},
+	{Name: "events", Description: "Ingestão de eventos e estatísticas"},
+	{Name: "system", Description: "Health checks, métricas e documentação"},
+}
+
+var pagination = []Parameter{
+	query("page", "integer", "Página, a partir de 1"),
+	query("page_size", "integer", "Itens por página"),
+}
+
+var auditFilters = append([]Parameter{
+	query("action", "string", "Ação registrada (ex.: auth.login)"),
+	query("actor_id", "integer", "ID do usuário que executou a ação"),
+	query("outcome", "string", "success, denied ou failure"),
+	query("target_id", "string", "ID do alvo da ação"),
+	query("from", "string", "Início do período, RFC 3339 ou AAAA-MM-DD"),
+	query("to", "string", "Fim do período, RFC 3339 ou AAAA-MM-DD"),
+}, pagination...)
+
+var routes = []route{
+	{method: http.MethodGet, path: "/", id: "home", tag: "system", summary: "Informações do serviço",
+		data: map[string]string{}},
+	{method: http.MethodGet, path: "/health", id: "getHealth", tag: "system", summary: "Estado detalhado do serviço e do banco",
+		data: domain.HealthResponse{}, unavailable: true},
+	{method: http.MethodGet, path: "/livez", id: "livez", tag: "system", summary: "Liveness probe",
+		raw: domain.LivenessResponse{}},
+	{method: http.MethodGet, path: "/readyz", id: "readyz", tag: "system", summary: "Readiness probe",
+		raw: domain.ReadinessResponse{}, unavailable: true},
+	{method: http.MethodGet, path: "/openapi.json", id: "openapi", tag: "system", summary: "Esta especificação",
+		success: &Response{Description: "Documento OpenAPI 3", Content: map[string]MediaType{"application/json": {Schema: &Schema{Type: "object"}}}}},
+	{method: http.MethodGet, path: "/docs", id: "docs", tag: "system", summary: "Documentação interativa",
+		success: &Response{Description: "Página HTML", Content: map[string]MediaType{"text/html": {Schema: &Schema{Type: "string"}}}}},
+	{method: http.MethodGet, path: "/docs/assets/{file}", id: "docsAsset", tag: "system", summary: "Arquivos do Swagger UI usados por /docs",
+		success: &Response{Description: "CSS ou JavaScript", Content: map[string]MediaType{"text/css": {Schema: &Schema{Type: "string"}}, "text/javascript": {Schema: &Schema{Type: "string"}}}}},
+
+	{method: http.MethodPost, path: "/register", id: "register", tag: "auth", summary: "Cria uma organização e seu primeiro usuário (admin)",
+		body: domain.RegisterRequest{}, status: http.StatusCreated, data: domain.User{}},
+	{method: http.MethodPost, path: "/login", id: "login", tag: "auth", summary: "Login com email e senha",
+		description: "Com 2FA ativo, devolve apenas mfa_token, que deve ser trocado em POST /login/2fa.",
+		body:        domain.LoginRequest{}, raw: domain.AuthResponse{}},
+	{method: http.MethodPost, path: "/login/2fa", id: "loginMFA", tag: "auth", summary: "Conclui o login com o código TOTP ou de recuperação",
+		body: domain.MFALoginRequest{}, raw: domain.AuthResponse{}},
+	{method: http.MethodPost, path: "/login/refresh", id: "refreshToken", tag: "auth", summary: "Troca um token de sessão válido por um novo", security: []string{SecurityBearer},
+		description: "Não pede a senha. A sessão pode ser renovada por até 30 dias desde o login; depois disso é preciso fazer login de novo.",
+		raw:         domain.AuthResponse{}},
+	{method: http.MethodPost, path: "/password/forgot", id: "forgotPassword", tag: "auth", summary: "Envia o link de redefinição de senha",
+		body: domain.ForgotPasswordRequest{}},
+	{method: http.MethodPost, path: "/password/reset", id: "resetPassword", tag: "auth", summary: "Redefine a senha com o token recebido por email",
+		body: domain.ResetPasswordRequest{}},
+	{method: http.MethodPost, path: "/email/verify", id: "verifyEmail", tag: "auth", summary: "Confirma o email com o token recebido",
+		body: domain.VerifyEmailRequest{}},
+	{method: http.MethodGet, path: "/auth/oidc/login", id: "ssoLogin", tag: "auth", summary: "Inicia o login SSO no provedor OpenID Connect",
+		description: "Disponível apenas com OIDC_ISSUER_URL configurado.",
+		success:     &Response{Description: "Redireciona para o provedor", Headers: map[string]Header{"Location": {Schema: &Schema{Type: "string"}}}}, status: http.StatusFound},
+	{method: http.MethodGet, path: "/auth/oidc/callback", id: "ssoCallback", tag: "auth", summary: "Retorno do provedor OpenID Connect",
+		description: "Disponível apenas com OIDC_ISSUER_URL configurado.",
+		query:       []Parameter{query("state", "string", "State gerado no início do login"), query("code", "string", "Código de autorização")},
+		raw:         domain.AuthResponse{}},
+
+	{method: http.MethodGet, path: "/users", id: "listUsers", tag: "users", summary: "Lista os usuários da organização", security: []string{SecurityBearer},
+		query: append([]Parameter{
+			query("search", "string", "Filtra por nome ou email"),
+			query("sort", "string", "Campo de ordenação"),
+			query("order", "string", "asc ou desc"),
+		}, pagination...),
+		data: domain.UserListResponse{}},
+	{method: http.MethodPost, path: "/users", id: "createUser", tag: "users", summary: "Cria um usuário na organização", security: []string{SecurityBearer},
+		body: domain.CreateUserRequest{}, status: http.StatusCreated, data: domain.User{}},
+	{method: http.MethodPatch, path: "/users/{id}", id: "updateUser", tag: "users", summary: "Atualiza nome, email ou papel", security: []string{SecurityBearer},
+		body: domain.UpdateUserRequest{}, data: domain.User{}},
+	{method: http.MethodDelete, path: "/users/{id}", id: "deleteUser", tag: "users", summary: "Remove um usuário (admin)", security: []string{SecurityBearer}},
+	{method: http.MethodPost, path: "/users/{id}/unlock", id: "unlockUser", tag: "users", summary: "Libera o login bloqueado por tentativas (admin)", security: []string{SecurityBearer}},
+
+	{method: http.MethodGet, path: "/profile", id: "getProfile", tag: "profile", summary: "Dados do usuário autenticado", security: []string{SecurityBearer},
+		data: domain.User{}},
+	{method: http.MethodPut, path: "/profile/password", id: "changePassword", tag: "profile", summary: "Troca a senha", security: []string{SecurityBearer},
+		body: domain.ChangePasswordRequest{}},
+	{method: http.MethodPost, path: "/email/verify/resend", id: "resendVerification", tag: "profile", summary: "Reenvia o email de verificação", security: []string{SecurityBearer}},
+
+	{method: http.MethodPost, path: "/profile/2fa/setup", id: "setupTwoFactor", tag: "two-factor", summary: "Gera o segredo TOTP e o QR code", security: []string{SecurityBearer},
+		description: "Aceita também o token de cadastro emitido no login quando o 2FA é obrigatório.",
+		data:        domain.TwoFactorSetupResponse{}},
+	{method: http.MethodPost, path: "/profile/2fa/enable", id: "enableTwoFactor", tag: "two-factor", summary: "Ativa o 2FA e devolve os códigos de recuperação", security: []string{SecurityBearer},
+		description: "Aceita também o token de cadastro emitido no login quando o 2FA é obrigatório.",
+		body:        domain.TwoFactorCodeRequest{}, data: domain.RecoveryCodesResponse{}},
+	{method: http.MethodPost, path: "/profile/2fa/disable", id: "disableTwoFactor", tag: "two-factor", summary: "Desativa o 2FA", security: []string{SecurityBearer},
+		body: domain.DisableTwoFactorRequest{}},
+	{method: http.MethodPost, path: "/profile/2fa/recovery-codes", id: "regenerateRecoveryCodes", tag: "two-factor", summary: "Gera novos códigos de recuperação", security: []string{SecurityBearer},
+		body: domain.TwoFactorCodeRequest{}, data: domain.RecoveryCodesResponse{}},
+	{method: http.MethodGet, path: "/admin/settings/2fa", id: "getTwoFactorSettings", tag: "two-factor", summary: "Consulta se o 2FA é obrigatório na organização (admin)", security: []string{SecurityBearer},
+		data: domain.TwoFactorSettings{}},
+	{method: http.MethodPut, path: "/admin/settings/2fa", id: "updateTwoFactorSettings", tag: "two-factor", summary: "Torna o 2FA obrigatório ou opcional na organização (admin)", security: []string{SecurityBearer},
+		body: domain.TwoFactorSettings{}, data: domain.TwoFactorSettings{}},
+
+	{method: http.MethodGet, path: "/organization", id: "getOrganization", tag: "organization", summary: "Organização do usuário autenticado", security: []string{SecurityBearer},
+		data: domain.Organization{}},
+	{method: http.MethodGet, path: "/api/sites", id: "listSites", tag: "organization", summary: "Lista os sites da organização", security: []string{SecurityBearer},
+		data: []domain.Site{}},
+	{method: http.MethodPost, path: "/api/sites", id: "createSite", tag: "organization", summary: "Registra um site (admin)", security: []string{SecurityBearer},
+		body: domain.CreateSiteRequest{}, status: http.StatusCreated, data: domain.Site{}},
+	{method: http.MethodDelete, path: "/api/sites/{id}", id: "deleteSite", tag: "organization", summary: "Remove um site (admin)", security: []string{SecurityBearer}},
+
+	{method: http.MethodGet, path: "/api/keys", id: "listAPIKeys", tag: "api-keys", summary: "Lista as chaves de API do usuário", security: []string{SecurityBearer},
+		data: []domain.APIKey{}},
+	{method: http.MethodPost, path: "/api/keys", id: "createAPIKey", tag: "api-keys", summary: "Cria uma chave de API", security: []string{SecurityBearer},
+		description: "A chave em texto puro só é devolvida nesta resposta.",
+		body:        domain.CreateAPIKeyRequest{}, status: http.StatusCreated, data: domain.APIKeyCreatedResponse{}},
+	{method: http.MethodDelete, path: "/api/keys/{id}", id: "revokeAPIKey", tag: "api-keys", summary: "Revoga uma chave de API", security: []string{SecurityBearer}},
+
+	{method: http.MethodGet, path: "/api/senders", id: "listSenders", tag: "senders", summary: "Lista os remetentes do usuário", security: []string{SecurityBearer},
+		data: []domain.WebhookSender{}},
+	{method: http.MethodPost, path: "/api/senders", id: "createSender", tag: "senders", summary: "Cria um remetente de requisições assinadas", security: []string{SecurityBearer},
+		description: "O segredo só é devolvido nesta resposta.",
+		body:        domain.CreateWebhookSenderRequest{}, status: http.StatusCreated, data: domain.WebhookSenderCreatedResponse{}},
+	{method: http.MethodDelete, path: "/api/senders/{id}", id: "revokeSender", tag: "senders", summary: "Revoga um remetente", security: []string{SecurityBearer}},
+
+	{method: http.MethodGet, path: "/api/audit", id: "listAudit", tag: "audit", summary: "Consulta o log de auditoria (admin)", security: []string{SecurityBearer},
+		query: auditFilters, data: domain.AuditListResponse{}},
+	{method: http.MethodGet, path: "/api/audit/export", id: "exportAudit", tag: "audit", summary: "Exporta o log de auditoria (admin)", security: []string{SecurityBearer},
+		query: append([]Parameter{query("format", "string", "csv (padrão) ou json")}, auditFilters...),
+		success: &Response{Description: "Arquivo para download", Content: map[string]MediaType{
+			"text/csv":         {Schema: &Schema{Type: "string"}},
+			"application/json": {Schema: &Schema{Type: "array", Items: ref("AuditEntry")}},
+		}}},
+
+	{method: http.MethodPost, path: "/api/events", id: "createEvents", tag: "events", summary: "Recebe um lote de eventos de email",
+		description: "Chaves de API precisam do escopo events:write. Aceita até 1000 eventos e 10 MB por requisição.",
+		security:    []string{SecurityBearer, SecurityAPIKey, SecuritySignature},
+		body:        domain.EventsRequest{}, status: http.StatusCreated, raw: domain.EventsResponse{}},
+	{method: http.MethodGet, path: "/api/stats/daily", id: "getDailyStats", tag: "events", summary: "Estatísticas diárias de eventos",
+		description: "Chaves de API precisam do escopo stats:read.",
+		security:    []string{SecurityBearer, SecurityAPIKey},
+		query: []Parameter{
+			query("start_date", "string", "Data inicial, AAAA-MM-DD"),
+			query("end_date", "string", "Data final, AAAA-MM-DD"),
+			query("site", "string", "Filtra por site"),
+		},
+		data: domain.StatsResponse{}},
+	{method: http.MethodPost, path: "/graphql", id: "graphql", tag: "events", summary: "Consultas analíticas em GraphQL",
+		description: "Sites, campanhas, contatos, eventos e estatísticas agregadas; o schema pode ser obtido por introspecção. " +
+			"Chaves de API precisam do escopo stats:read, e de events:read para contacts e events. Consultas acima dos limites de profundidade ou de custo são recusadas " +
+			"antes de executar. Erros da consulta vêm com status 200, em errors[].extensions.code.",
+		security: []string{SecurityBearer, SecurityAPIKey},
+		body:     domain.GraphQLRequest{},
+		success: &Response{Description: "Resposta GraphQL (data, errors e extensions.cost)",
+			Content: map[string]MediaType{"application/json": {Schema: &Schema{Type: "object"}}}}},
+}
+
+func query(name, typ, description string) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: &Schema{Type: typ}}
+}
+
+func build() *Document {
+	s := newSchemas()
+	s.of(domain.AuditEntry{})
+	problem := s.of(domain.Problem{})
+	envelope := s.of(domain.Response{})
+
+	doc := &Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title:       "Event Go API",
+			Description: "Ingestão de eventos de email, estatísticas e gestão de contas. Erros seguem a RFC 7807 (application/problem+json).",
+			Version:     "1.0.0",
+		},
+		Tags:  tags,
+		Paths: map[string]PathItem{},
+		Components: Components{
+			Schemas: s.components,
+			SecuritySchemes: map[string]SecurityScheme{
+				SecurityBearer: {Type: "http", Scheme: "bearer", BearerFormat: "JWT",
+					Description: "Token de sessão devolvido por POST /login"},
+				SecurityAPIKey: {Type: "apiKey", In: "header", Name: "X-API-Key",
+					Description: "Chave de API; também aceita em Authorization: ApiKey <chave>"},
+				SecuritySignature: {Type: "apiKey", In: "header", Name: "X-Signature",
+					Description: "Assinatura HMAC-SHA256, acompanhada de X-Signature-Key-Id, X-Signature-Timestamp e X-Signature-Nonce"},
+			},
+		},
+	}
+
+	integer := &Schema{Type: "integer"}
+	rateLimited := Response{
+		Description: "Limite de requisições excedido",
+		Headers: map[string]Header{
+			"Retry-After":         {Description: "Segundos até a próxima requisição ser aceita", Schema: integer},
+			"RateLimit-Limit":     {Description: "Capacidade do balde do principal", Schema: integer},
+			"RateLimit-Remaining": {Description: "Requisições restantes no balde", Schema: integer},
+			"RateLimit-Reset":     {Description: "Segundos até o balde encher de novo", Schema: integer},
+			"RateLimit-Policy":    {Description: "Limite e janela em segundos (ex.: 600;w=60)", Schema: &Schema{Type: "string"}},
+		},
+		Content: map[string]MediaType{"application/problem+json": {Schema: problem}},
+	}
+
+	for _, rt := range routes {
+		op := &Operation{
+			OperationID: rt.id,
+			Summary:     rt.summary,
+			Description: rt.description,
+			Tags:        []string{rt.tag},
+			Parameters:  append(pathParams(rt.path), rt.query...),
+			Responses: map[string]Response{
+				"default": {Description: "Erro", Content: map[string]MediaType{"application/problem+json": {Schema: problem}}},
+			},
+		}
+		for _, scheme := range rt.security {
+			op.Security = append(op.Security, map[string][]string{scheme: {}})
+		}
+		if rt.body != nil {
+			op.RequestBody = &RequestBody{Required: true, Content: jsonContent(s.of(rt.body))}
+		}
+
+		status := rt.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		success := successResponse(s, rt, envelope)
+		op.Responses[strconv.Itoa(status)] = success
+		if rt.unavailable {
+			op.Responses[strconv.Itoa(http.StatusServiceUnavailable)] = Response{Description: "Dependência indisponível", Content: success.Content}
+		}
+		// Só as rotas de sistema ficam fora do limitador de requisições.
+		if rt.tag != "system" {
+			op.Responses[strconv.Itoa(http.StatusTooManyRequests)] = rateLimited
+		}
+
+		if doc.Paths[rt.path] == nil {
+			doc.Paths[rt.path] = PathItem{}
+		}
+		doc.Paths[rt.path][strings.ToLower(rt.method)] = op
+	}
+	return doc
+}
+
+func successResponse(s *schemas, rt route, envelope *Schema) Response {
+	switch {
+	case rt.success != nil:
+		return *rt.success
+	case rt.raw != nil:
+		return Response{Description: "Sucesso", Content: jsonContent(s.of(rt.raw))}
+	case rt.data != nil:
+		return Response{Description: "Sucesso", Content: jsonContent(&Schema{AllOf: []*Schema{
+			envelope,
+			{Type: "object", Properties: map[string]*Schema{"data": s.of(rt.data)}, Required: []string{"data"}},
+		}})}
+	default:
+		return Response{Description: "Sucesso", Content: jsonContent(envelope)}
+	}
+}
+
+// pathParams documenta as variáveis do template do gorilla/mux (ex.: {id}).
+// {id} é sempre um ID numérico; as demais variáveis são texto.
+func pathParams(path string) []Parameter {
+	var params []Parameter
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			name := strings.Trim(segment, "{}")
+			schema := &Schema{Type: "string"}
+			if name == "id" {
+				schema.Type = "integer"
+			}
+			params = append(params, Parameter{Name: name, In: "path", Required: true, Schema: schema})
+		}
+	}
+	return params
+}
+
+func jsonContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: schema}}
+}
+
+// Routes lista "MÉTODO caminho" de todas as operações documentadas, em ordem.
+func Routes() []string {
+	var list []string
+	for path, item := range Spec().Paths {
+		for method := range item {
+			list = append(list, strings.ToUpper(method)+" "+path)
+		}
+	}
+	sort.Strings(list)
+	return list
+}
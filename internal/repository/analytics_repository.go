@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+)
+
+// statsDimensions mapeia as dimensões de agrupamento para colunas; só valores
+// desta tabela entram no SQL.
+var statsDimensions = map[string]string{
+	domain.DimensionSite:      "site",
+	domain.DimensionEventType: "event_type",
+	domain.DimensionCampaign:  "COALESCE(campaign_id, '')",
+}
+
+type analyticsRepository struct {
+	db DBInterface
+}
+
+func NewAnalyticsRepository(db DBInterface) AnalyticsRepository {
+	return &analyticsRepository{db: db}
+}
+
+// analyticsWhere monta o WHERE comum às consultas. O período usa timestamp
+// diretamente, sem DATE(), para aproveitar o índice (org_id, timestamp).
+func analyticsWhere(orgID int, filter domain.AnalyticsFilter) (string, []interface{}) {
+	where := "WHERE org_id = $1"
+	args := []interface{}{orgID}
+	add := func(cond string, value interface{}) {
+		args = append(args, value)
+		where += fmt.Sprintf(" AND "+cond, len(args))
+	}
+	if filter.StartDate != "" {
+		add("timestamp >= $%d::date", filter.StartDate)
+	}
+	if filter.EndDate != "" {
+		add("timestamp < $%d::date + 1", filter.EndDate)
+	}
+	if len(filter.Sites) > 0 {
+		add("site = ANY($%d)", pq.Array(filter.Sites))
+	}
+	if len(filter.EventTypes) > 0 {
+		add("event_type = ANY($%d)", pq.Array(filter.EventTypes))
+	}
+	if filter.CampaignID != "" {
+		add("campaign_id = $%d", filter.CampaignID)
+	}
+	if filter.Email != "" {
+		add("email = $%d", filter.Email)
+	}
+	return where, args
+}
+
+func (r *analyticsRepository) ListEvents(ctx context.Context, orgID int, filter domain.AnalyticsFilter, page domain.AnalyticsPage) ([]domain.StoredEvent, error) {
+	where, args := analyticsWhere(orgID, filter)
+	query := fmt.Sprintf(`
+		SELECT event_id, event_type, email, site, timestamp, COALESCE(campaign_id, ''), COALESCE(subject, '')
+		FROM email_events %s
+		ORDER BY timestamp DESC, id DESC
+		LIMIT $%d OFFSET $%d`, where, len(args)+1, len(args)+2)
+	args = append(args, page.Limit, page.Offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar eventos: %w", err)
+	}
+	defer rows.Close()
+
+	events := []domain.StoredEvent{}
+	for rows.Next() {
+		var e domain.StoredEvent
+		if err := rows.Scan(&e.ID, &e.Type, &e.Email, &e.Site, &e.Timestamp, &e.CampaignID, &e.Subject); err != nil {
+			return nil, fmt.Errorf("erro ao ler evento: %w", err)
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+func (r *analyticsRepository) ListCampaigns(ctx context.Context, orgID int, filter domain.AnalyticsFilter, page domain.AnalyticsPage) ([]domain.CampaignSummary, error) {
+	where, args := analyticsWhere(orgID, filter)
+	query := fmt.Sprintf(`
+		SELECT campaign_id, COUNT(*), COUNT(DISTINCT email), MIN(timestamp), MAX(timestamp)
+		FROM email_events %s AND campaign_id <> ''
+		GROUP BY campaign_id
+		ORDER BY MAX(timestamp) DESC, campaign_id
+		LIMIT $%d OFFSET $%d`, where, len(args)+1, len(args)+2)
+	args = append(args, page.Limit, page.Offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar campanhas: %w", err)
+	}
+	defer rows.Close()
+
+	campaigns := []domain.CampaignSummary{}
+	for rows.Next() {
+		var c domain.CampaignSummary
+		if err := rows.Scan(&c.ID, &c.TotalEvents, &c.UniqueEmails, &c.FirstSeen, &c.LastSeen); err != nil {
+			return nil, fmt.Errorf("erro ao ler campanha: %w", err)
+		}
+		campaigns = append(campaigns, c)
+	}
+	return campaigns, rows.Err()
+}
+
+func (r *analyticsRepository) ListContacts(ctx context.Context, orgID int, filter domain.AnalyticsFilter, page domain.AnalyticsPage) ([]domain.ContactSummary, error) {
+	where, args := analyticsWhere(orgID, filter)
+	query := fmt.Sprintf(`
+		SELECT email, COUNT(*), MIN(timestamp), MAX(timestamp)
+		FROM email_events %s
+		GROUP BY email
+		ORDER BY MAX(timestamp) DESC, email
+		LIMIT $%d OFFSET $%d`, where, len(args)+1, len(args)+2)
+	args = append(args, page.Limit, page.Offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar contatos: %w", err)
+	}
+	defer rows.Close()
+
+	contacts := []domain.ContactSummary{}
+	for rows.Next() {
+		var c domain.ContactSummary
+		if err := rows.Scan(&c.Email, &c.TotalEvents, &c.FirstSeen, &c.LastSeen); err != nil {
+			return nil, fmt.Errorf("erro ao ler contato: %w", err)
+		}
+		contacts = append(contacts, c)
+	}
+	return contacts, rows.Err()
+}
+
+// AggregateStats agrupa por período (date_trunc na granularidade) e pelas
+// dimensões pedidas, na ordem de query.GroupBy.
+func (r *analyticsRepository) AggregateStats(ctx context.Context, orgID int, query domain.StatsQuery) ([]domain.StatsBucket, error) {
+	where, args := analyticsWhere(orgID, query.Filter)
+
+	columns := []string{"to_char(date_trunc($" + fmt.Sprint(len(args)+1) + ", timestamp), 'YYYY-MM-DD')"}
+	args = append(args, query.Granularity)
+	for _, dimension := range query.GroupBy {
+		column, ok := statsDimensions[dimension]
+		if !ok {
+			return nil, fmt.Errorf("dimensão desconhecida: %s", dimension)
+		}
+		columns = append(columns, column)
+	}
+	groupBy := make([]string, len(columns))
+	for i := range columns {
+		groupBy[i] = fmt.Sprint(i + 1)
+	}
+
+	sql := fmt.Sprintf(`
+		SELECT %s, COUNT(*), COUNT(DISTINCT email)
+		FROM email_events %s
+		GROUP BY %s
+		ORDER BY %s
+		LIMIT $%d`, strings.Join(columns, ", "), where, strings.Join(groupBy, ", "), strings.Join(groupBy, ", "), len(args)+1)
+	args = append(args, query.Limit)
+
+	rows, err := r.db.QueryContext(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao agregar estatísticas: %w", err)
+	}
+	defer rows.Close()
+
+	buckets := []domain.StatsBucket{}
+	for rows.Next() {
+		var b domain.StatsBucket
+		dest := []interface{}{&b.Period}
+		for _, dimension := range query.GroupBy {
+			switch dimension {
+			case domain.DimensionSite:
+				dest = append(dest, &b.Site)
+			case domain.DimensionEventType:
+				dest = append(dest, &b.EventType)
+			case domain.DimensionCampaign:
+				dest = append(dest, &b.CampaignID)
+			}
+		}
+		dest = append(dest, &b.Count, &b.UniqueEmails)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("erro ao ler estatísticas: %w", err)
+		}
+		buckets = append(buckets, b)
+	}
+	return buckets, rows.Err()
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyticsWhere(t *testing.T) {
+	where, args := analyticsWhere(3, domain.AnalyticsFilter{
+		StartDate: "2026-01-01",
+		EndDate:   "2026-01-31",
+		Sites:     []string{"exemplo.com"},
+		Email:     "a@exemplo.com",
+	})
+
+	assert.Equal(t, "WHERE org_id = $1 AND timestamp >= $2::date AND timestamp < $3::date + 1 AND site = ANY($4) AND email = $5", where)
+	assert.Equal(t, []interface{}{3, "2026-01-01", "2026-01-31", pq.Array([]string{"exemplo.com"}), "a@exemplo.com"}, args)
+}
+
+func TestAnalyticsWhere_OnlyOrganization(t *testing.T) {
+	where, args := analyticsWhere(3, domain.AnalyticsFilter{})
+
+	assert.Equal(t, "WHERE org_id = $1", where)
+	assert.Equal(t, []interface{}{3}, args)
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+)
+
+type apiKeyRepository struct {
+	db DBInterface
+}
+
+func NewAPIKeyRepository(db DBInterface) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+const apiKeyColumns = "id, user_id, org_id, name, prefix, scopes, sites, expires_at, last_used_at, revoked_at, created_at"
+
+func (r *apiKeyRepository) Create(ctx context.Context, key *domain.APIKey, keyHash string) (*domain.APIKey, error) {
+	query := `
+		INSERT INTO api_keys (user_id, org_id, name, prefix, key_hash, scopes, sites, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING ` + apiKeyColumns
+
+	row := r.db.QueryRowContext(ctx, query, key.UserID, key.OrgID, key.Name, key.Prefix, keyHash,
+		pq.Array(key.Scopes), pq.Array(key.Sites), key.ExpiresAt)
+
+	created, err := scanAPIKey(row)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar chave de API: %w", err)
+	}
+
+	return created, nil
+}
+
+func (r *apiKeyRepository) GetByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	// Chaves de usuários removidos não autenticam, mesmo as que não foram
+	// revogadas na remoção (usuários removidos antes dela revogar credenciais).
+	row := r.db.QueryRowContext(ctx, "SELECT "+apiKeyColumns+" FROM api_keys WHERE key_hash = $1 AND user_id IN (SELECT id FROM users WHERE deleted_at IS NULL)", keyHash)
+
+	key, err := scanAPIKey(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &APIKeyNotFoundError{}
+		}
+		return nil, fmt.Errorf("erro ao buscar chave de API: %w", err)
+	}
+
+	return key, nil
+}
+
+func (r *apiKeyRepository) ListByUser(ctx context.Context, userID int) ([]domain.APIKey, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+apiKeyColumns+" FROM api_keys WHERE user_id = $1 ORDER BY id", userID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar chaves de API: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []domain.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler chave de API: %w", err)
+		}
+		keys = append(keys, *key)
+	}
+
+	return keys, rows.Err()
+}
+
+func (r *apiKeyRepository) Revoke(ctx context.Context, id, userID int) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE api_keys SET revoked_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+	`, id, userID)
+	if err != nil {
+		return fmt.Errorf("erro ao revogar chave de API: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao revogar chave de API: %w", err)
+	}
+	if affected == 0 {
+		return &APIKeyNotFoundError{ID: id}
+	}
+
+	return nil
+}
+
+func (r *apiKeyRepository) TouchLastUsed(ctx context.Context, id int) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE api_keys SET last_used_at = CURRENT_TIMESTAMP WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar uso da chave de API: %w", err)
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAPIKey(row rowScanner) (*domain.APIKey, error) {
+	var key domain.APIKey
+	var expiresAt, lastUsedAt, revokedAt sql.NullTime
+
+	err := row.Scan(&key.ID, &key.UserID, &key.OrgID, &key.Name, &key.Prefix,
+		pq.Array(&key.Scopes), pq.Array(&key.Sites),
+		&expiresAt, &lastUsedAt, &revokedAt, &key.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	key.ExpiresAt = nullTimePtr(expiresAt)
+	key.LastUsedAt = nullTimePtr(lastUsedAt)
+	key.RevokedAt = nullTimePtr(revokedAt)
+
+	return &key, nil
+}
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+type APIKeyNotFoundError struct {
+	ID int
+}
+
+func (e *APIKeyNotFoundError) Error() string {
+	if e.ID != 0 {
+		return "chave de API não encontrada com ID: " + strconv.Itoa(e.ID)
+	}
+	return "chave de API não encontrada"
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+)
+
+type auditRepository struct {
+	db DBInterface
+}
+
+func NewAuditRepository(db DBInterface) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+// Append nunca atribui a ação a um usuário que não estava autenticado. Em
+// requisições anônimas o email informado é o alvo: quando pertence a uma conta,
+// a entrada vai para a organização dela com o id da conta em target_id.
+func (r *auditRepository) Append(ctx context.Context, entry *domain.AuditEntry) error {
+	err := r.db.QueryRowContext(ctx, `
+		WITH target AS (
+			SELECT id, org_id FROM users
+			WHERE $2 = 0 AND $5 = 'user' AND email = $6 AND deleted_at IS NULL
+		)
+		INSERT INTO audit_log (org_id, actor_id, actor_email, action, target_type, target_id, ip_address, user_agent, outcome, status_code)
+		VALUES (
+			COALESCE(NULLIF($1, 0), (SELECT org_id FROM target)),
+			NULLIF($2, 0),
+			$3, $4, $5,
+			COALESCE((SELECT id::text FROM target), $6),
+			$7, $8, $9, $10
+		)
+		RETURNING id, COALESCE(org_id, 0), target_id, created_at
+	`, entry.OrgID, entry.ActorID, entry.ActorEmail, entry.Action, entry.TargetType, entry.TargetID,
+		entry.IP, entry.UserAgent, entry.Outcome, entry.StatusCode).
+		Scan(&entry.ID, &entry.OrgID, &entry.TargetID, &entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("erro ao gravar log de auditoria: %w", err)
+	}
+	return nil
+}
+
+// auditWhere monta o WHERE comum à listagem paginada e à exportação.
+func auditWhere(orgID int, filter domain.AuditFilter) (string, []interface{}) {
+	where := "WHERE org_id = $1"
+	args := []interface{}{orgID}
+	add := func(cond string, value interface{}) {
+		args = append(args, value)
+		where += fmt.Sprintf(" AND "+cond, len(args))
+	}
+	if filter.Action != "" {
+		add("action = $%d", filter.Action)
+	}
+	if filter.ActorID != 0 {
+		add("actor_id = $%d", filter.ActorID)
+	}
+	if filter.Outcome != "" {
+		add("outcome = $%d", filter.Outcome)
+	}
+	if filter.TargetID != "" {
+		add("target_id = $%d", filter.TargetID)
+	}
+	if filter.From != nil {
+		add("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		add("created_at < $%d", *filter.To)
+	}
+	return where, args
+}
+
+const auditColumns = `id, COALESCE(org_id, 0), COALESCE(actor_id, 0), actor_email, action, target_type, target_id,
+		       ip_address, user_agent, outcome, status_code, created_at`
+
+func (r *auditRepository) List(ctx context.Context, orgID int, filter domain.AuditFilter) ([]domain.AuditEntry, int, error) {
+	where, args := auditWhere(orgID, filter)
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM audit_log "+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("erro ao contar log de auditoria: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM audit_log %s
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d OFFSET $%d`, auditColumns, where, len(args)+1, len(args)+2)
+	args = append(args, filter.PageSize, (filter.Page-1)*filter.PageSize)
+
+	entries, err := r.query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// ListBefore pagina por id em vez de OFFSET: entradas gravadas durante a
+// exportação não deslocam as páginas seguintes. beforeID 0 começa do topo.
+func (r *auditRepository) ListBefore(ctx context.Context, orgID int, filter domain.AuditFilter, beforeID int64, limit int) ([]domain.AuditEntry, error) {
+	where, args := auditWhere(orgID, filter)
+	if beforeID > 0 {
+		args = append(args, beforeID)
+		where += fmt.Sprintf(" AND id < $%d", len(args))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM audit_log %s
+		ORDER BY id DESC
+		LIMIT $%d`, auditColumns, where, len(args)+1)
+	args = append(args, limit)
+
+	return r.query(ctx, query, args...)
+}
+
+func (r *auditRepository) query(ctx context.Context, query string, args ...interface{}) ([]domain.AuditEntry, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar log de auditoria: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []domain.AuditEntry{}
+	for rows.Next() {
+		var e domain.AuditEntry
+		if err := rows.Scan(&e.ID, &e.OrgID, &e.ActorID, &e.ActorEmail, &e.Action, &e.TargetType, &e.TargetID,
+			&e.IP, &e.UserAgent, &e.Outcome, &e.StatusCode, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("erro ao ler log de auditoria: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
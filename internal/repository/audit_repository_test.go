@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditWhere(t *testing.T) {
+	where, args := auditWhere(3, domain.AuditFilter{Action: domain.AuditLogin, Outcome: domain.AuditOutcomeDenied, TargetID: "4"})
+
+	assert.Equal(t, "WHERE org_id = $1 AND action = $2 AND outcome = $3 AND target_id = $4", where)
+	assert.Equal(t, []interface{}{3, domain.AuditLogin, domain.AuditOutcomeDenied, "4"}, args)
+}
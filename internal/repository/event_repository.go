@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"crypto/sha256"
 	"database/sql"
 	"fmt"
@@ -8,12 +9,13 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/tracing"
 )
 
 type DBInterface interface {
-	QueryRow(query string, args ...interface{}) *sql.Row
-	Exec(query string, args ...interface{}) (sql.Result, error)
-	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (tracing.Rows, error)
 }
 
 type eventRepository struct {
@@ -24,30 +26,41 @@ func NewEventRepository(db DBInterface) EventRepository {
 	return &eventRepository{db: db}
 }
 
-func (r *eventRepository) Create(event *domain.EmailEvent) (string, error) {
+func (r *eventRepository) Create(ctx context.Context, orgID int, event *domain.EmailEvent) (string, error) {
 	contentHash := r.generateContentHash(event)
 	
+	// A duplicação é verificada só dentro da organização, para que um tenant não
+	// descubra eventos de outro.
 	var exists bool
-	err := r.db.QueryRow("SELECT EXISTS(SELECT 1 FROM email_events WHERE content_hash = $1)", contentHash).Scan(&exists)
+	err := r.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM email_events WHERE org_id = $1 AND content_hash = $2)", orgID, contentHash).Scan(&exists)
 	if err != nil {
 		return "", fmt.Errorf("erro ao verificar duplicação: %w", err)
 	}
 	
 	if exists {
-		return "", fmt.Errorf("evento duplicado")
+		return "", &DuplicateEventError{}
 	}
 	
 	eventID := uuid.New().String()
 	
-	_, err = r.db.Exec(`
-		INSERT INTO email_events (event_id, event_type, email, site, timestamp, content_hash)
-		VALUES ($1, $2, $3, $4, $5, $6)
-	`, eventID, event.Type, event.Email, event.Site, event.Timestamp, contentHash)
+	// O índice único (org_id, content_hash) cobre duas requisições simultâneas com o mesmo evento.
+	// Os campos opcionais vazios ficam NULL; o tamanho deles é validado no EventService.
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO email_events (org_id, event_id, event_type, email, site, timestamp, content_hash,
+		                          campaign_id, subject, ip_address, user_agent)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), NULLIF($9, ''), NULLIF($10, ''), NULLIF($11, ''))
+		ON CONFLICT (org_id, content_hash) DO NOTHING
+	`, orgID, eventID, event.Type, event.Email, event.Site, event.Timestamp, contentHash,
+		event.CampaignID, event.Subject, event.IPAddress, event.UserAgent)
 	
 	if err != nil {
 		return "", fmt.Errorf("erro ao inserir evento: %w", err)
 	}
 	
+	if inserted, err := result.RowsAffected(); err == nil && inserted == 0 {
+		return "", &DuplicateEventError{}
+	}
+	
 	return eventID, nil
 }
 
@@ -57,7 +70,7 @@ func (r *eventRepository) generateContentHash(event *domain.EmailEvent) string {
     return fmt.Sprintf("%x", hash)
 }
 
-func (r *eventRepository) GetDailyStats(startDate, endDate, site string) ([]domain.DailyStats, error) {
+func (r *eventRepository) GetDailyStats(ctx context.Context, orgID int, startDate, endDate, site string) ([]domain.DailyStats, error) {
 	baseQuery := `
 		SELECT 
 			DATE(timestamp) as date,
@@ -66,12 +79,12 @@ func (r *eventRepository) GetDailyStats(startDate, endDate, site string) ([]doma
 			COUNT(*) as count,
 			COUNT(DISTINCT email) as unique_emails
 		FROM email_events
-		WHERE 1=1
+		WHERE org_id = $1
 	`
 	
-	var args []interface{}
+	args := []interface{}{orgID}
 	var conditions []string
-	argIndex := 1
+	argIndex := 2
 	
 	if startDate != "" {
 		conditions = append(conditions, fmt.Sprintf("DATE(timestamp) >= $%d", argIndex))
@@ -100,9 +113,9 @@ func (r *eventRepository) GetDailyStats(startDate, endDate, site string) ([]doma
 		ORDER BY date DESC, site, event_type
 	`
 	
-	rows, err := r.db.Query(baseQuery, args...)
+	rows, err := r.db.QueryContext(ctx, baseQuery, args...)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao consultar estatísticas: %v", err)
+		return nil, fmt.Errorf("erro ao consultar estatísticas: %w", err)
 	}
 	defer rows.Close()
 	
@@ -114,7 +127,7 @@ func (r *eventRepository) GetDailyStats(startDate, endDate, site string) ([]doma
 		
 		err := rows.Scan(&date, &siteName, &eventType, &count, &uniqueEmails)
 		if err != nil {
-			return nil, fmt.Errorf("erro ao ler dados: %v", err)
+			return nil, fmt.Errorf("erro ao ler dados: %w", err)
 		}
         
         if statsByDate[date] == nil {
@@ -157,18 +170,27 @@ func (r *eventRepository) GetDailyStats(startDate, endDate, site string) ([]doma
     	return result, nil
 }
 
-func (r *eventRepository) GetTotalCounts() (int, int, error) {
+// EstimateTotalCounts usa a estimativa do planner (pg_class.reltuples), mantida
+// pelo autovacuum/ANALYZE, em vez de COUNT(*) que varre as tabelas inteiras.
+// Tabelas nunca analisadas têm reltuples -1 e contam como 0.
+func (r *eventRepository) EstimateTotalCounts(ctx context.Context) (int, int, error) {
 	var totalUsers, totalEvents int
-	
-	err := r.db.QueryRow("SELECT COUNT(*) FROM users").Scan(&totalUsers)
-	if err != nil {
-		return 0, 0, err
-	}
-	
-	err = r.db.QueryRow("SELECT COUNT(*) FROM email_events").Scan(&totalEvents)
+
+	err := r.db.QueryRowContext(ctx, `
+		SELECT GREATEST((SELECT reltuples FROM pg_class WHERE oid = 'users'::regclass), 0)::bigint,
+		       GREATEST((SELECT reltuples FROM pg_class WHERE oid = 'email_events'::regclass), 0)::bigint
+	`).Scan(&totalUsers, &totalEvents)
 	if err != nil {
 		return 0, 0, err
 	}
-	
+
 	return totalUsers, totalEvents, nil
-} 
\ No newline at end of file
+}
+
+// DuplicateEventError indica que a organização já registrou um evento com o
+// mesmo conteúdo.
+type DuplicateEventError struct{}
+
+func (e *DuplicateEventError) Error() string {
+	return "evento duplicado"
+}
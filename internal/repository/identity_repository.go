@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type identityRepository struct {
+	db DBInterface
+}
+
+func NewIdentityRepository(db DBInterface) IdentityRepository {
+	return &identityRepository{db: db}
+}
+
+func (r *identityRepository) FindUserID(ctx context.Context, issuer, subject string) (int, error) {
+	var userID int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT i.user_id FROM user_identities i
+		JOIN users u ON u.id = i.user_id
+		WHERE i.issuer = $1 AND i.subject = $2 AND u.deleted_at IS NULL
+	`, issuer, subject).Scan(&userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, &IdentityNotFoundError{Issuer: issuer, Subject: subject}
+		}
+		return 0, fmt.Errorf("erro ao buscar identidade: %w", err)
+	}
+	return userID, nil
+}
+
+// Link vincula a identidade ao usuário e registra o login; chamadas repetidas
+// apenas atualizam o email e o horário do último acesso.
+func (r *identityRepository) Link(ctx context.Context, userID int, issuer, subject, email string) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO user_identities (user_id, issuer, subject, email, last_login_at)
+		VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP)
+		ON CONFLICT (issuer, subject) DO UPDATE
+		SET email = EXCLUDED.email, last_login_at = EXCLUDED.last_login_at
+	`, userID, issuer, subject, email)
+	if err != nil {
+		return fmt.Errorf("erro ao vincular identidade: %w", err)
+	}
+	return nil
+}
+
+type IdentityNotFoundError struct {
+	Issuer  string
+	Subject string
+}
+
+func (e *IdentityNotFoundError) Error() string {
+	return "identidade não encontrada: " + e.Issuer + " / " + e.Subject
+}
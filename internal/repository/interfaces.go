@@ -1,16 +1,120 @@
 package repository
 
-import "github.com/nathaliaoliveira/goapp/internal/domain"
+import (
+    "context"
+    "time"
+
+    "github.com/nathaliaoliveira/goapp/internal/domain"
+)
 
 type UserRepository interface {
-    Create(name, email, passwordHash string) (*domain.User, error)
-    GetByEmail(email string) (*domain.User, error)
-    GetByID(id int) (*domain.User, error)
-    GetAll() ([]domain.User, error)
+    Create(ctx context.Context, orgID int, name, email, passwordHash string) (*domain.User, error)
+    CreateWithOrganization(ctx context.Context, orgName, name, email, passwordHash string) (*domain.User, error)
+    GetByEmail(ctx context.Context, email string) (*domain.User, error)
+    GetByID(ctx context.Context, id int) (*domain.User, error)
+    List(ctx context.Context, orgID int, params domain.UserListParams) ([]domain.User, int, error)
+    Update(ctx context.Context, orgID, id int, name, email, role string) (*domain.User, error)
+    SoftDelete(ctx context.Context, orgID, id int) error
+    UpdatePassword(ctx context.Context, id int, passwordHash string) error
+    MarkEmailVerified(ctx context.Context, id int) error
 }
 
 type EventRepository interface {
-    Create(event *domain.EmailEvent) (string, error)
-    GetDailyStats(startDate, endDate, site string) ([]domain.DailyStats, error)
-    GetTotalCounts() (int, int, error)
-} 
\ No newline at end of file
+    Create(ctx context.Context, orgID int, event *domain.EmailEvent) (string, error)
+    GetDailyStats(ctx context.Context, orgID int, startDate, endDate, site string) ([]domain.DailyStats, error)
+    EstimateTotalCounts(ctx context.Context) (int, int, error)
+}
+
+type APIKeyRepository interface {
+    Create(ctx context.Context, key *domain.APIKey, keyHash string) (*domain.APIKey, error)
+    GetByHash(ctx context.Context, keyHash string) (*domain.APIKey, error)
+    ListByUser(ctx context.Context, userID int) ([]domain.APIKey, error)
+    Revoke(ctx context.Context, id, userID int) error
+    TouchLastUsed(ctx context.Context, id int) error
+}
+
+type WebhookSenderRepository interface {
+    Create(ctx context.Context, sender *domain.WebhookSender) (*domain.WebhookSender, error)
+    GetByKeyID(ctx context.Context, keyID string) (*domain.WebhookSender, error)
+    ListByUser(ctx context.Context, userID int) ([]domain.WebhookSender, error)
+    Revoke(ctx context.Context, id, userID int) error
+}
+
+type NonceStore interface {
+    Remember(nonce string, expiresAt time.Time) (bool, error)
+}
+
+type TokenRepository interface {
+    Create(ctx context.Context, userID int, purpose, tokenHash string, expiresAt time.Time) error
+    Lookup(ctx context.Context, purpose, tokenHash string) (int, error)
+    Consume(ctx context.Context, purpose, tokenHash string) (int, error)
+    InvalidateForUser(ctx context.Context, userID int, purpose string) error
+}
+
+// LoginAttemptStore guarda as falhas de login por chave. Update aplica update
+// ao registro da chave de forma atômica e devolve o resultado; implementações
+// compartilhadas precisam de transação ou compare-and-set (WATCH/MULTI no Redis).
+type LoginAttemptStore interface {
+    Update(key string, update func(domain.LoginAttempts) domain.LoginAttempts) (domain.LoginAttempts, error)
+    Reset(key string) error
+}
+
+// RateLimitStore guarda os baldes do limitador de requisições. Take consome
+// uma ficha do balde de key e precisa ser atômico, para que réplicas que
+// compartilham o store (ex.: Redis) não ultrapassem o limite juntas.
+type RateLimitStore interface {
+    Take(ctx context.Context, key string, limit domain.RateLimit) (domain.RateLimitResult, error)
+}
+
+type TwoFactorRepository interface {
+    Get(ctx context.Context, userID int) (*domain.TwoFactorState, error)
+    SetPendingSecret(ctx context.Context, userID int, secret string) error
+    Enable(ctx context.Context, userID int, step int64) error
+    Disable(ctx context.Context, userID int) error
+    UseStep(ctx context.Context, userID int, step int64) (bool, error)
+    ReplaceRecoveryCodes(ctx context.Context, userID int, codeHashes []string) error
+    ConsumeRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error)
+}
+
+// SettingsRepository guarda configurações por organização.
+type SettingsRepository interface {
+    Get(ctx context.Context, orgID int, key string) (string, bool, error)
+    Set(ctx context.Context, orgID int, key, value string) error
+}
+
+type IdentityRepository interface {
+    FindUserID(ctx context.Context, issuer, subject string) (int, error)
+    Link(ctx context.Context, userID int, issuer, subject, email string) error
+}
+
+type SSOStateStore interface {
+    Save(state string, value domain.SSOState) error
+    Take(state string) (*domain.SSOState, error)
+}
+
+type OrganizationRepository interface {
+    GetByID(ctx context.Context, id int) (*domain.Organization, error)
+}
+
+type SiteRepository interface {
+    Create(ctx context.Context, orgID int, domainName string) (*domain.Site, error)
+    List(ctx context.Context, orgID int) ([]domain.Site, error)
+    Delete(ctx context.Context, orgID, id int) error
+    Domains(ctx context.Context, orgID int) ([]string, error)
+}
+
+// AuditRepository só grava e consulta: o log de auditoria não tem atualização nem remoção.
+type AuditRepository interface {
+    Append(ctx context.Context, entry *domain.AuditEntry) error
+    List(ctx context.Context, orgID int, filter domain.AuditFilter) ([]domain.AuditEntry, int, error)
+    ListBefore(ctx context.Context, orgID int, filter domain.AuditFilter, beforeID int64, limit int) ([]domain.AuditEntry, error)
+}
+
+// AnalyticsRepository consulta os eventos gravados; todas as consultas são
+// restritas à organização e esperam o filtro já validado pelo serviço.
+type AnalyticsRepository interface {
+    ListEvents(ctx context.Context, orgID int, filter domain.AnalyticsFilter, page domain.AnalyticsPage) ([]domain.StoredEvent, error)
+    ListCampaigns(ctx context.Context, orgID int, filter domain.AnalyticsFilter, page domain.AnalyticsPage) ([]domain.CampaignSummary, error)
+    ListContacts(ctx context.Context, orgID int, filter domain.AnalyticsFilter, page domain.AnalyticsPage) ([]domain.ContactSummary, error)
+    AggregateStats(ctx context.Context, orgID int, query domain.StatsQuery) ([]domain.StatsBucket, error)
+}
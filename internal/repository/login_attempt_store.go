@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"sync"
+	"time"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+)
+
+// memoryLoginAttemptStore mantém as tentativas em memória e descarta registros
+// sem falhas recentes. Com várias réplicas use uma implementação compartilhada.
+type memoryLoginAttemptStore struct {
+	mu        sync.Mutex
+	attempts  map[string]domain.LoginAttempts
+	retention time.Duration
+	lastPrune time.Time
+	now       func() time.Time
+}
+
+func NewMemoryLoginAttemptStore(retention time.Duration) LoginAttemptStore {
+	return &memoryLoginAttemptStore{
+		attempts:  make(map[string]domain.LoginAttempts),
+		retention: retention,
+		now:       time.Now,
+	}
+}
+
+func (s *memoryLoginAttemptStore) Update(key string, update func(domain.LoginAttempts) domain.LoginAttempts) (domain.LoginAttempts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prune()
+	attempts := update(s.attempts[key])
+	if attempts == (domain.LoginAttempts{}) {
+		delete(s.attempts, key)
+	} else {
+		s.attempts[key] = attempts
+	}
+	return attempts, nil
+}
+
+func (s *memoryLoginAttemptStore) Reset(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.attempts, key)
+	return nil
+}
+
+func (s *memoryLoginAttemptStore) prune() {
+	now := s.now()
+	if now.Sub(s.lastPrune) < time.Minute {
+		return
+	}
+	for key, attempts := range s.attempts {
+		if now.Sub(attempts.LastFailure) > s.retention {
+			delete(s.attempts, key)
+		}
+	}
+	s.lastPrune = now
+}
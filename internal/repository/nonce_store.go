@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"sync"
+	"time"
+)
+
+// memoryNonceStore guarda os nonces já vistos até expirarem. Serve para uma única
+// instância; com várias réplicas use uma implementação compartilhada de NonceStore.
+type memoryNonceStore struct {
+	mu        sync.Mutex
+	nonces    map[string]time.Time
+	lastPrune time.Time
+	now       func() time.Time
+}
+
+const noncePruneInterval = time.Minute
+
+func NewMemoryNonceStore() NonceStore {
+	return &memoryNonceStore{
+		nonces: make(map[string]time.Time),
+		now:    time.Now,
+	}
+}
+
+// Remember registra o nonce e devolve false se ele já tinha sido usado.
+func (s *memoryNonceStore) Remember(nonce string, expiresAt time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.lastPrune) >= noncePruneInterval {
+		for key, exp := range s.nonces {
+			if !exp.After(now) {
+				delete(s.nonces, key)
+			}
+		}
+		s.lastPrune = now
+	}
+
+	if exp, seen := s.nonces[nonce]; seen && exp.After(now) {
+		return false, nil
+	}
+
+	s.nonces[nonce] = expiresAt
+	return true, nil
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+)
+
+type organizationRepository struct {
+	db DBInterface
+}
+
+func NewOrganizationRepository(db DBInterface) OrganizationRepository {
+	return &organizationRepository{db: db}
+}
+
+func (r *organizationRepository) GetByID(ctx context.Context, id int) (*domain.Organization, error) {
+	var org domain.Organization
+	err := r.db.QueryRowContext(ctx, "SELECT id, name, created_at FROM organizations WHERE id = $1", id).
+		Scan(&org.ID, &org.Name, &org.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &OrganizationNotFoundError{ID: id}
+		}
+		return nil, fmt.Errorf("erro ao buscar organização: %w", err)
+	}
+	return &org, nil
+}
+
+type OrganizationNotFoundError struct {
+	ID int
+}
+
+func (e *OrganizationNotFoundError) Error() string {
+	return "organização não encontrada com ID: " + strconv.Itoa(e.ID)
+}
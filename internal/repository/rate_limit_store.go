@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+)
+
+type tokenBucket struct {
+	tokens    float64
+	updatedAt time.Time
+	// fullAt é quando o balde volta a ficar cheio; a partir daí ele equivale
+	// a um balde novo e pode ser descartado.
+	fullAt time.Time
+}
+
+// memoryRateLimitStore mantém os baldes em memória e descarta os que já se
+// reabasteceram. Com várias réplicas use uma implementação compartilhada.
+type memoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]tokenBucket
+	lastPrune time.Time
+	now       func() time.Time
+}
+
+func NewMemoryRateLimitStore() RateLimitStore {
+	return &memoryRateLimitStore{
+		buckets: make(map[string]tokenBucket),
+		now:     time.Now,
+	}
+}
+
+func (s *memoryRateLimitStore) Take(ctx context.Context, key string, limit domain.RateLimit) (domain.RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.prune(now)
+
+	capacity := float64(limit.Requests)
+	perSecond := capacity / limit.Period.Seconds()
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = tokenBucket{tokens: capacity, updatedAt: now}
+	}
+	if elapsed := now.Sub(bucket.updatedAt); elapsed > 0 {
+		bucket.tokens = math.Min(capacity, bucket.tokens+elapsed.Seconds()*perSecond)
+		bucket.updatedAt = now
+	}
+
+	result := domain.RateLimitResult{Limit: limit.Requests}
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - bucket.tokens) / perSecond)
+	}
+	result.Remaining = int(bucket.tokens)
+	result.Reset = secondsToDuration((capacity - bucket.tokens) / perSecond)
+
+	bucket.fullAt = now.Add(result.Reset)
+	s.buckets[key] = bucket
+	return result, nil
+}
+
+func (s *memoryRateLimitStore) prune(now time.Time) {
+	if now.Sub(s.lastPrune) < time.Minute {
+		return
+	}
+	for key, bucket := range s.buckets {
+		if !now.Before(bucket.fullAt) {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastPrune = now
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(math.Ceil(seconds * float64(time.Second)))
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRateLimitStore_TokenBucket(t *testing.T) {
+	now := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	store := NewMemoryRateLimitStore().(*memoryRateLimitStore)
+	store.now = func() time.Time { return now }
+	limit := domain.RateLimit{Requests: 3, Period: 3 * time.Second}
+	ctx := context.Background()
+
+	for i := 2; i >= 0; i-- {
+		result, err := store.Take(ctx, "ip:1.2.3.4", limit)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, i, result.Remaining)
+	}
+
+	result, err := store.Take(ctx, "ip:1.2.3.4", limit)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Second, result.RetryAfter)
+	assert.Equal(t, 3*time.Second, result.Reset)
+
+	other, err := store.Take(ctx, "ip:5.6.7.8", limit)
+	require.NoError(t, err)
+	assert.True(t, other.Allowed, "cada chave tem o seu balde")
+
+	now = now.Add(1500 * time.Millisecond)
+	result, err = store.Take(ctx, "ip:1.2.3.4", limit)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+}
+
+func TestMemoryRateLimitStore_PrunesRefilledBuckets(t *testing.T) {
+	now := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	store := NewMemoryRateLimitStore().(*memoryRateLimitStore)
+	store.now = func() time.Time { return now }
+	limit := domain.RateLimit{Requests: 10, Period: time.Minute}
+
+	_, err := store.Take(context.Background(), "user:1", limit)
+	require.NoError(t, err)
+
+	now = now.Add(2 * time.Minute)
+	_, err = store.Take(context.Background(), "user:2", limit)
+	require.NoError(t, err)
+
+	assert.NotContains(t, store.buckets, "user:1")
+	assert.Contains(t, store.buckets, "user:2")
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type settingsRepository struct {
+	db DBInterface
+}
+
+func NewSettingsRepository(db DBInterface) SettingsRepository {
+	return &settingsRepository{db: db}
+}
+
+// Get devolve ok = false quando a organização nunca gravou a configuração.
+func (r *settingsRepository) Get(ctx context.Context, orgID int, key string) (string, bool, error) {
+	var value string
+	err := r.db.QueryRowContext(ctx, "SELECT value FROM app_settings WHERE org_id = $1 AND key = $2", orgID, key).Scan(&value)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("erro ao buscar configuração %s: %w", key, err)
+	}
+	return value, true, nil
+}
+
+func (r *settingsRepository) Set(ctx context.Context, orgID int, key, value string) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO app_settings (org_id, key, value, updated_at) VALUES ($1, $2, $3, CURRENT_TIMESTAMP)
+		ON CONFLICT (org_id, key) DO UPDATE SET value = EXCLUDED.value, updated_at = EXCLUDED.updated_at
+	`, orgID, key, value)
+	if err != nil {
+		return fmt.Errorf("erro ao salvar configuração %s: %w", key, err)
+	}
+	return nil
+}
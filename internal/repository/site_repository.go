@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+)
+
+type siteRepository struct {
+	db DBInterface
+}
+
+func NewSiteRepository(db DBInterface) SiteRepository {
+	return &siteRepository{db: db}
+}
+
+// Create registra o domínio para a organização. O domínio é único dentro da
+// organização; outras organizações podem cadastrar o mesmo domínio.
+func (r *siteRepository) Create(ctx context.Context, orgID int, domainName string) (*domain.Site, error) {
+	var site domain.Site
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO sites (org_id, domain) VALUES ($1, $2)
+		RETURNING id, org_id, domain, created_at
+	`, orgID, domainName).Scan(&site.ID, &site.OrgID, &site.Domain, &site.CreatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, &DuplicateSiteError{Domain: domainName}
+		}
+		return nil, fmt.Errorf("erro ao criar site: %w", err)
+	}
+	return &site, nil
+}
+
+func (r *siteRepository) List(ctx context.Context, orgID int) ([]domain.Site, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT id, org_id, domain, created_at FROM sites WHERE org_id = $1 ORDER BY domain", orgID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar sites: %w", err)
+	}
+	defer rows.Close()
+
+	sites := []domain.Site{}
+	for rows.Next() {
+		var site domain.Site
+		if err := rows.Scan(&site.ID, &site.OrgID, &site.Domain, &site.CreatedAt); err != nil {
+			return nil, fmt.Errorf("erro ao ler site: %w", err)
+		}
+		sites = append(sites, site)
+	}
+	return sites, rows.Err()
+}
+
+func (r *siteRepository) Delete(ctx context.Context, orgID, id int) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM sites WHERE id = $1 AND org_id = $2", id, orgID)
+	if err != nil {
+		return fmt.Errorf("erro ao remover site: %w", err)
+	}
+	return requireAffected(result, &SiteNotFoundError{ID: id})
+}
+
+func (r *siteRepository) Domains(ctx context.Context, orgID int) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT domain FROM sites WHERE org_id = $1", orgID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar sites: %w", err)
+	}
+	defer rows.Close()
+
+	domains := []string{}
+	for rows.Next() {
+		var d string
+		if err := rows.Scan(&d); err != nil {
+			return nil, fmt.Errorf("erro ao ler site: %w", err)
+		}
+		domains = append(domains, d)
+	}
+	return domains, rows.Err()
+}
+
+type DuplicateSiteError struct {
+	Domain string
+}
+
+func (e *DuplicateSiteError) Error() string {
+	return "site já cadastrado: " + e.Domain
+}
+
+type SiteNotFoundError struct {
+	ID int
+}
+
+func (e *SiteNotFoundError) Error() string {
+	return "site não encontrado com ID: " + strconv.Itoa(e.ID)
+}
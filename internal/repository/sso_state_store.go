@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+)
+
+const (
+	// maxSSOStates limita os logins OIDC em andamento guardados em memória, para
+	// que uma enxurrada de /auth/oidc/login não esgote a memória da instância.
+	maxSSOStates = 10000
+	// maxSSOStatesPerIP impede que um único cliente ocupe sozinho o limite
+	// global e bloqueie o SSO para todos.
+	maxSSOStatesPerIP = 50
+)
+
+var (
+	// ErrSSOStateStoreFull indica que há logins OIDC em andamento demais.
+	ErrSSOStateStoreFull = errors.New("logins SSO em andamento demais")
+	// ErrTooManySSOStates indica que o IP já tem logins OIDC em andamento demais.
+	ErrTooManySSOStates = errors.New("logins SSO em andamento demais para este IP")
+)
+
+// memorySSOStateStore guarda os logins OIDC em andamento. Assim como o
+// memoryNonceStore, serve para uma única instância.
+type memorySSOStateStore struct {
+	mu        sync.Mutex
+	states    map[string]domain.SSOState
+	perIP     map[string]int
+	maxStates int
+	maxPerIP  int
+	lastPrune time.Time
+	now       func() time.Time
+}
+
+func NewMemorySSOStateStore() SSOStateStore {
+	return &memorySSOStateStore{
+		states:    make(map[string]domain.SSOState),
+		perIP:     make(map[string]int),
+		maxStates: maxSSOStates,
+		maxPerIP:  maxSSOStatesPerIP,
+		now:       time.Now,
+	}
+}
+
+// Save descarta os states vencidos no máximo uma vez por minuto, ou antes
+// disso quando o store ou o IP atingiram o limite, e recusa novos states
+// enquanto o limite continuar atingido.
+func (s *memorySSOStateStore) Save(state string, value domain.SSOState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	full := len(s.states) >= s.maxStates || s.perIP[value.ClientIP] >= s.maxPerIP
+	s.prune(full)
+	if s.perIP[value.ClientIP] >= s.maxPerIP {
+		return ErrTooManySSOStates
+	}
+	if len(s.states) >= s.maxStates {
+		return ErrSSOStateStoreFull
+	}
+
+	s.states[state] = value
+	s.perIP[value.ClientIP]++
+	return nil
+}
+
+// Take remove o state ao devolvê-lo, para que cada callback só valha uma vez.
+func (s *memorySSOStateStore) Take(state string) (*domain.SSOState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	value, ok := s.states[state]
+	if ok {
+		s.remove(state, value)
+	}
+	if !ok || !value.ExpiresAt.After(s.now()) {
+		return nil, &InvalidTokenError{}
+	}
+	return &value, nil
+}
+
+func (s *memorySSOStateStore) prune(force bool) {
+	now := s.now()
+	if !force && now.Sub(s.lastPrune) < time.Minute {
+		return
+	}
+	for key, existing := range s.states {
+		if !existing.ExpiresAt.After(now) {
+			s.remove(key, existing)
+		}
+	}
+	s.lastPrune = now
+}
+
+func (s *memorySSOStateStore) remove(state string, value domain.SSOState) {
+	delete(s.states, state)
+	if s.perIP[value.ClientIP]--; s.perIP[value.ClientIP] <= 0 {
+		delete(s.perIP, value.ClientIP)
+	}
+}
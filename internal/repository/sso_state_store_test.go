@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemorySSOStateStore_CapsPendingStates(t *testing.T) {
+	now := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	store := NewMemorySSOStateStore().(*memorySSOStateStore)
+	store.now = func() time.Time { return now }
+	store.maxStates = 3
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, store.Save(fmt.Sprint("state-", i), domain.SSOState{ExpiresAt: now.Add(time.Duration(i+1) * time.Minute)}))
+	}
+	assert.ErrorIs(t, store.Save("state-3", domain.SSOState{ExpiresAt: now.Add(time.Hour)}), ErrSSOStateStoreFull)
+
+	now = now.Add(90 * time.Second)
+	require.NoError(t, store.Save("state-3", domain.SSOState{ExpiresAt: now.Add(time.Hour)}), "cheio, o store descarta os vencidos antes de recusar")
+	assert.Len(t, store.states, 3)
+
+	_, err := store.Take("state-0")
+	assert.Error(t, err)
+	value, err := store.Take("state-2")
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-90*time.Second).Add(3*time.Minute), value.ExpiresAt)
+}
+
+func TestMemorySSOStateStore_CapsPendingStatesPerIP(t *testing.T) {
+	now := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	store := NewMemorySSOStateStore().(*memorySSOStateStore)
+	store.now = func() time.Time { return now }
+	store.maxPerIP = 2
+
+	valid := domain.SSOState{ClientIP: "203.0.113.7", ExpiresAt: now.Add(time.Minute)}
+	require.NoError(t, store.Save("a-1", valid))
+	require.NoError(t, store.Save("a-2", valid))
+	assert.ErrorIs(t, store.Save("a-3", valid), ErrTooManySSOStates)
+	assert.NoError(t, store.Save("b-1", domain.SSOState{ClientIP: "198.51.100.9", ExpiresAt: now.Add(time.Minute)}), "outro IP não é afetado")
+
+	_, err := store.Take("a-1")
+	require.NoError(t, err)
+	assert.NoError(t, store.Save("a-3", valid), "o callback libera a vaga do IP")
+
+	now = now.Add(2 * time.Minute)
+	assert.NoError(t, store.Save("a-4", domain.SSOState{ClientIP: "203.0.113.7", ExpiresAt: now.Add(time.Minute)}), "states vencidos não contam")
+	assert.Equal(t, 1, store.perIP["203.0.113.7"])
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type tokenRepository struct {
+	db DBInterface
+}
+
+func NewTokenRepository(db DBInterface) TokenRepository {
+	return &tokenRepository{db: db}
+}
+
+// Create grava expires_at em UTC. A coluna é TIMESTAMP, sem fuso, então as
+// consultas comparam com NOW() AT TIME ZONE 'UTC' em vez de CURRENT_TIMESTAMP,
+// que seguiria o fuso da sessão.
+func (r *tokenRepository) Create(ctx context.Context, userID int, purpose, tokenHash string, expiresAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO user_tokens (user_id, purpose, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4)
+	`, userID, purpose, tokenHash, expiresAt.UTC())
+	if err != nil {
+		return fmt.Errorf("erro ao criar token: %w", err)
+	}
+	return nil
+}
+
+// Lookup devolve o dono de um token ainda válido sem consumi-lo.
+func (r *tokenRepository) Lookup(ctx context.Context, purpose, tokenHash string) (int, error) {
+	var userID int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT user_id FROM user_tokens
+		WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > (NOW() AT TIME ZONE 'UTC')
+	`, tokenHash, purpose).Scan(&userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, &InvalidTokenError{}
+		}
+		return 0, fmt.Errorf("erro ao buscar token: %w", err)
+	}
+	return userID, nil
+}
+
+// Consume marca o token como usado numa única instrução, garantindo que ele
+// só possa ser trocado uma vez, e devolve o ID do usuário dono do token.
+func (r *tokenRepository) Consume(ctx context.Context, purpose, tokenHash string) (int, error) {
+	var userID int
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE user_tokens SET used_at = CURRENT_TIMESTAMP
+		WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > (NOW() AT TIME ZONE 'UTC')
+		RETURNING user_id
+	`, tokenHash, purpose).Scan(&userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, &InvalidTokenError{}
+		}
+		return 0, fmt.Errorf("erro ao consumir token: %w", err)
+	}
+	return userID, nil
+}
+
+func (r *tokenRepository) InvalidateForUser(ctx context.Context, userID int, purpose string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE user_tokens SET used_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL
+	`, userID, purpose)
+	if err != nil {
+		return fmt.Errorf("erro ao invalidar tokens: %w", err)
+	}
+	return nil
+}
+
+type InvalidTokenError struct{}
+
+func (e *InvalidTokenError) Error() string {
+	return "token inválido ou expirado"
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+)
+
+type twoFactorRepository struct {
+	db DBInterface
+}
+
+func NewTwoFactorRepository(db DBInterface) TwoFactorRepository {
+	return &twoFactorRepository{db: db}
+}
+
+func (r *twoFactorRepository) Get(ctx context.Context, userID int) (*domain.TwoFactorState, error) {
+	var secret sql.NullString
+	state := &domain.TwoFactorState{}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT totp_secret, totp_enabled_at IS NOT NULL, totp_last_step
+		FROM users WHERE id = $1 AND deleted_at IS NULL
+	`, userID).Scan(&secret, &state.Enabled, &state.LastStep)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &UserNotFoundError{ID: userID}
+		}
+		return nil, fmt.Errorf("erro ao buscar 2FA: %w", err)
+	}
+	state.Secret = secret.String
+	return state, nil
+}
+
+// SetPendingSecret grava um novo segredo ainda não confirmado. Contas com 2FA
+// ativo não são alteradas; é preciso desativar antes.
+func (r *twoFactorRepository) SetPendingSecret(ctx context.Context, userID int, secret string) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE users SET totp_secret = $1, totp_last_step = 0
+		WHERE id = $2 AND deleted_at IS NULL AND totp_enabled_at IS NULL
+	`, secret, userID)
+	if err != nil {
+		return fmt.Errorf("erro ao salvar segredo 2FA: %w", err)
+	}
+	return requireAffected(result, &UserNotFoundError{ID: userID})
+}
+
+func (r *twoFactorRepository) Enable(ctx context.Context, userID int, step int64) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE users SET totp_enabled_at = CURRENT_TIMESTAMP, totp_last_step = $1
+		WHERE id = $2 AND deleted_at IS NULL AND totp_secret IS NOT NULL AND totp_enabled_at IS NULL
+	`, step, userID)
+	if err != nil {
+		return fmt.Errorf("erro ao ativar 2FA: %w", err)
+	}
+	return requireAffected(result, &UserNotFoundError{ID: userID})
+}
+
+func (r *twoFactorRepository) Disable(ctx context.Context, userID int) error {
+	_, err := r.db.ExecContext(ctx, `
+		WITH codes AS (
+			DELETE FROM user_recovery_codes WHERE user_id = $1
+		)
+		UPDATE users SET totp_secret = NULL, totp_enabled_at = NULL, totp_last_step = 0
+		WHERE id = $1
+	`, userID)
+	if err != nil {
+		return fmt.Errorf("erro ao desativar 2FA: %w", err)
+	}
+	return nil
+}
+
+// UseStep registra o contador aceito e devolve false se ele (ou um posterior)
+// já tiver sido usado, impedindo que o mesmo código valha duas vezes.
+func (r *twoFactorRepository) UseStep(ctx context.Context, userID int, step int64) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE users SET totp_last_step = $1
+		WHERE id = $2 AND totp_last_step < $1
+	`, step, userID)
+	if err != nil {
+		return false, fmt.Errorf("erro ao registrar código 2FA: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *twoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID int, codeHashes []string) error {
+	_, err := r.db.ExecContext(ctx, `
+		WITH old AS (
+			DELETE FROM user_recovery_codes WHERE user_id = $1
+		)
+		INSERT INTO user_recovery_codes (user_id, code_hash)
+		SELECT $1, unnest($2::text[])
+	`, userID, pq.Array(codeHashes))
+	if err != nil {
+		return fmt.Errorf("erro ao salvar códigos de recuperação: %w", err)
+	}
+	return nil
+}
+
+func (r *twoFactorRepository) ConsumeRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE user_recovery_codes SET used_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`, userID, codeHash)
+	if err != nil {
+		return false, fmt.Errorf("erro ao usar código de recuperação: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
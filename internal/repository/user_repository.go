@@ -1,107 +1,280 @@
 package repository
 
 import (
+    "context"
     "database/sql"
-    "log"
+    "fmt"
+    "log/slog"
+    "strconv"
     "strings"
 
     "github.com/nathaliaoliveira/goapp/internal/domain"
 )
 
 type userRepository struct {
-    db *sql.DB
+    db DBInterface
 }
 
-func NewUserRepository(db *sql.DB) UserRepository {
+func NewUserRepository(db DBInterface) UserRepository {
     return &userRepository{db: db}
 }
 
-func (r *userRepository) Create(name, email, passwordHash string) (*domain.User, error) {
-    log.Printf("📝 Criando usuário: %s (%s)", name, email)
+func (r *userRepository) Create(ctx context.Context, orgID int, name, email, passwordHash string) (*domain.User, error) {
+    slog.DebugContext(ctx, "Criando usuário na organização", "name", name, "email", email, "org_id", orgID)
     
-    query := "INSERT INTO users (name, email, password_hash) VALUES ($1, $2, $3) RETURNING id, name, email, created_at"
+    query := "INSERT INTO users (org_id, name, email, password_hash) VALUES ($1, $2, $3, $4) RETURNING id, org_id, name, email, role, email_verified_at IS NOT NULL, totp_enabled_at IS NOT NULL, created_at"
     
     var user domain.User
-    err := r.db.QueryRow(query, name, email, passwordHash).Scan(
-        &user.ID, &user.Name, &user.Email, &user.CreatedAt)
+    err := r.db.QueryRowContext(ctx, query, orgID, name, email, passwordHash).Scan(
+        &user.ID, &user.OrgID, &user.Name, &user.Email, &user.Role, &user.EmailVerified, &user.TwoFactorEnabled, &user.CreatedAt)
     
     if err != nil {
-        if strings.Contains(err.Error(), "unique constraint") {
-            log.Printf("❌ Email já cadastrado: %s", email)
+        if isUniqueViolation(err) {
+            slog.DebugContext(ctx, "Email já cadastrado", "email", email)
             return nil, &DuplicateEmailError{Email: email}
         }
-        log.Printf("❌ Erro ao criar usuário: %v", err)
+        slog.ErrorContext(ctx, "Erro ao criar usuário", "error", err)
         return nil, err
     }
     
-    log.Printf("✅ Usuário criado com sucesso: %s (ID: %d)", user.Name, user.ID)
+    slog.DebugContext(ctx, "Usuário criado com sucesso", "name", user.Name, "user_id", user.ID)
     return &user, nil
 }
 
-func (r *userRepository) GetByID(id int) (*domain.User, error) {
-    log.Printf("🔍 Buscando usuário por ID: %d", id)
+// CreateWithOrganization cria a organização e o seu primeiro usuário, como
+// administrador, em um único comando: se o email já existir nada é gravado.
+func (r *userRepository) CreateWithOrganization(ctx context.Context, orgName, name, email, passwordHash string) (*domain.User, error) {
+    slog.DebugContext(ctx, "Criando organização com o usuário", "org_name", orgName, "email", email)
+    
+    query := `
+        WITH org AS (
+            INSERT INTO organizations (name) VALUES ($1) RETURNING id
+        )
+        INSERT INTO users (org_id, name, email, password_hash, role)
+        SELECT id, $2, $3, $4, $5 FROM org
+        RETURNING id, org_id, name, email, role, email_verified_at IS NOT NULL, totp_enabled_at IS NOT NULL, created_at
+    `
+    
+    var user domain.User
+    err := r.db.QueryRowContext(ctx, query, orgName, name, email, passwordHash, domain.RoleAdmin).Scan(
+        &user.ID, &user.OrgID, &user.Name, &user.Email, &user.Role, &user.EmailVerified, &user.TwoFactorEnabled, &user.CreatedAt)
+    if err != nil {
+        if isUniqueViolation(err) {
+            slog.DebugContext(ctx, "Email já cadastrado", "email", email)
+            return nil, &DuplicateEmailError{Email: email}
+        }
+        slog.ErrorContext(ctx, "Erro ao criar organização", "error", err)
+        return nil, err
+    }
+    
+    slog.DebugContext(ctx, "Organização criada com o usuário", "org_id", user.OrgID, "email", user.Email, "user_id", user.ID)
+    return &user, nil
+}
+
+// GetByID e GetByEmail não filtram por organização: servem ao login e às
+// operações do próprio usuário autenticado. Operações sobre outros usuários
+// usam os métodos que recebem orgID.
+func (r *userRepository) GetByID(ctx context.Context, id int) (*domain.User, error) {
+    slog.DebugContext(ctx, "Buscando usuário por ID", "user_id", id)
     
     var user domain.User
-    query := "SELECT id, name, email, created_at FROM users WHERE id = $1"
+    query := "SELECT id, org_id, name, email, password_hash, role, email_verified_at IS NOT NULL, totp_enabled_at IS NOT NULL, created_at FROM users WHERE id = $1 AND deleted_at IS NULL"
     
-    err := r.db.QueryRow(query, id).Scan(&user.ID, &user.Name, &user.Email, &user.CreatedAt)
+    err := r.db.QueryRowContext(ctx, query, id).Scan(
+        &user.ID, &user.OrgID, &user.Name, &user.Email, &user.PasswordHash, &user.Role, &user.EmailVerified, &user.TwoFactorEnabled, &user.CreatedAt)
     if err != nil {
         if err == sql.ErrNoRows {
-            log.Printf("❌ Usuário não encontrado: ID %d", id)
+            slog.DebugContext(ctx, "Usuário não encontrado", "user_id", id)
             return nil, &UserNotFoundError{ID: id}
         }
-        log.Printf("❌ Erro ao buscar usuário: %v", err)
+        slog.ErrorContext(ctx, "Erro ao buscar usuário", "error", err)
         return nil, err
     }
     
-    log.Printf("✅ Usuário encontrado: %s (ID: %d)", user.Name, user.ID)
+    slog.DebugContext(ctx, "Usuário encontrado", "name", user.Name, "user_id", user.ID)
     return &user, nil
 }
 
-func (r *userRepository) GetByEmail(email string) (*domain.User, error) {
-    log.Printf("🔍 Buscando usuário por email: %s", email)
+func (r *userRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+    slog.DebugContext(ctx, "Buscando usuário por email", "email", email)
     
     var user domain.User
-    query := "SELECT id, name, email, password_hash, created_at FROM users WHERE email = $1"
+    query := "SELECT id, org_id, name, email, password_hash, role, email_verified_at IS NOT NULL, totp_enabled_at IS NOT NULL, created_at FROM users WHERE email = $1 AND deleted_at IS NULL"
     
-    err := r.db.QueryRow(query, email).Scan(
-        &user.ID, &user.Name, &user.Email, &user.PasswordHash, &user.CreatedAt)
+    err := r.db.QueryRowContext(ctx, query, email).Scan(
+        &user.ID, &user.OrgID, &user.Name, &user.Email, &user.PasswordHash, &user.Role, &user.EmailVerified, &user.TwoFactorEnabled, &user.CreatedAt)
     if err != nil {
         if err == sql.ErrNoRows {
-            log.Printf("❌ Usuário não encontrado: %s", email)
+            slog.DebugContext(ctx, "Usuário não encontrado", "email", email)
             return nil, &UserNotFoundError{Email: email}
         }
-        log.Printf("❌ Erro ao buscar usuário: %v", err)
+        slog.ErrorContext(ctx, "Erro ao buscar usuário", "error", err)
         return nil, err
     }
     
-    log.Printf("✅ Usuário encontrado: %s (%s)", user.Name, user.Email)
+    slog.DebugContext(ctx, "Usuário encontrado", "name", user.Name, "email", user.Email)
     return &user, nil
 }
 
-func (r *userRepository) GetAll() ([]domain.User, error) {
-    log.Printf("👥 Listando todos os usuários")
+var userSortColumns = map[string]string{
+    "id":         "id",
+    "name":       "name",
+    "email":      "email",
+    "created_at": "created_at",
+}
+
+func (r *userRepository) List(ctx context.Context, orgID int, params domain.UserListParams) ([]domain.User, int, error) {
+    slog.DebugContext(ctx, "Listando usuários da organização", "org_id", orgID, "page", params.Page, "page_size", params.PageSize)
+    
+    where := "WHERE org_id = $1 AND deleted_at IS NULL"
+    args := []interface{}{orgID}
+    if params.Search != "" {
+        args = append(args, "%"+escapeLike(params.Search)+"%")
+        where += " AND (name ILIKE $2 OR email ILIKE $2)"
+    }
     
-    query := "SELECT id, name, email, created_at FROM users ORDER BY id"
-    rows, err := r.db.Query(query)
+    var total int
+    if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM users "+where, args...).Scan(&total); err != nil {
+        slog.ErrorContext(ctx, "Erro ao contar usuários", "error", err)
+        return nil, 0, err
+    }
+    
+    sortColumn, ok := userSortColumns[params.SortBy]
+    if !ok {
+        sortColumn = "id"
+    }
+    sortOrder := "ASC"
+    if strings.EqualFold(params.SortOrder, "desc") {
+        sortOrder = "DESC"
+    }
+    
+    query := fmt.Sprintf(
+        "SELECT id, org_id, name, email, role, email_verified_at IS NOT NULL, totp_enabled_at IS NOT NULL, created_at FROM users %s ORDER BY %s %s, id LIMIT $%d OFFSET $%d",
+        where, sortColumn, sortOrder, len(args)+1, len(args)+2)
+    args = append(args, params.PageSize, (params.Page-1)*params.PageSize)
+    
+    rows, err := r.db.QueryContext(ctx, query, args...)
     if err != nil {
-        log.Printf("❌ Erro ao listar usuários: %v", err)
-        return nil, err
+        slog.ErrorContext(ctx, "Erro ao listar usuários", "error", err)
+        return nil, 0, err
     }
     defer rows.Close()
     
-    var users []domain.User
+    users := []domain.User{}
     for rows.Next() {
         var user domain.User
-        if err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.CreatedAt); err != nil {
-            log.Printf("❌ Erro ao ler usuário: %v", err)
-            return nil, err
+        if err := rows.Scan(&user.ID, &user.OrgID, &user.Name, &user.Email, &user.Role, &user.EmailVerified, &user.TwoFactorEnabled, &user.CreatedAt); err != nil {
+            slog.ErrorContext(ctx, "Erro ao ler usuário", "error", err)
+            return nil, 0, err
         }
         users = append(users, user)
     }
     
-    log.Printf("✅ %d usuários encontrados", len(users))
-    return users, nil
+    slog.DebugContext(ctx, "Usuários encontrados", "count", len(users), "total", total)
+    return users, total, rows.Err()
+}
+
+func (r *userRepository) Update(ctx context.Context, orgID, id int, name, email, role string) (*domain.User, error) {
+    slog.DebugContext(ctx, "Atualizando usuário", "user_id", id)
+    
+    // Trocar o email exige uma nova confirmação.
+    query := `
+        UPDATE users SET
+            name = $1,
+            email_verified_at = CASE WHEN email = $2 THEN email_verified_at ELSE NULL END,
+            email = $2,
+            role = $3
+        WHERE id = $4 AND org_id = $5 AND deleted_at IS NULL
+        RETURNING id, org_id, name, email, role, email_verified_at IS NOT NULL, totp_enabled_at IS NOT NULL, created_at
+    `
+    
+    var user domain.User
+    err := r.db.QueryRowContext(ctx, query, name, email, role, id, orgID).Scan(
+        &user.ID, &user.OrgID, &user.Name, &user.Email, &user.Role, &user.EmailVerified, &user.TwoFactorEnabled, &user.CreatedAt)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return nil, &UserNotFoundError{ID: id}
+        }
+        if isUniqueViolation(err) {
+            return nil, &DuplicateEmailError{Email: email}
+        }
+        slog.ErrorContext(ctx, "Erro ao atualizar usuário", "error", err)
+        return nil, err
+    }
+    
+    slog.DebugContext(ctx, "Usuário atualizado", "user_id", user.ID)
+    return &user, nil
+}
+
+// SoftDelete remove o usuário e revoga, no mesmo comando, as chaves de API e
+// os remetentes dele, para que nenhuma credencial sobreviva à remoção.
+func (r *userRepository) SoftDelete(ctx context.Context, orgID, id int) error {
+    slog.DebugContext(ctx, "Removendo usuário", "user_id", id)
+    
+    query := `
+        WITH deleted AS (
+            UPDATE users SET deleted_at = CURRENT_TIMESTAMP
+            WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL
+            RETURNING id
+        ), revoked_keys AS (
+            UPDATE api_keys SET revoked_at = CURRENT_TIMESTAMP
+            WHERE user_id IN (SELECT id FROM deleted) AND revoked_at IS NULL
+        ), revoked_senders AS (
+            UPDATE webhook_senders SET revoked_at = CURRENT_TIMESTAMP
+            WHERE user_id IN (SELECT id FROM deleted) AND revoked_at IS NULL
+        )
+        SELECT COUNT(*) FROM deleted
+    `
+    
+    var deleted int
+    if err := r.db.QueryRowContext(ctx, query, id, orgID).Scan(&deleted); err != nil {
+        slog.ErrorContext(ctx, "Erro ao remover usuário", "error", err)
+        return err
+    }
+    if deleted == 0 {
+        return &UserNotFoundError{ID: id}
+    }
+    
+    return nil
+}
+
+func escapeLike(value string) string {
+    return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+func (r *userRepository) UpdatePassword(ctx context.Context, id int, passwordHash string) error {
+    slog.DebugContext(ctx, "Atualizando senha do usuário", "user_id", id)
+    
+    result, err := r.db.ExecContext(ctx, "UPDATE users SET password_hash = $1 WHERE id = $2 AND deleted_at IS NULL", passwordHash, id)
+    if err != nil {
+        slog.ErrorContext(ctx, "Erro ao atualizar senha", "error", err)
+        return err
+    }
+    
+    return requireAffected(result, &UserNotFoundError{ID: id})
+}
+
+func (r *userRepository) MarkEmailVerified(ctx context.Context, id int) error {
+    slog.DebugContext(ctx, "Confirmando email do usuário", "user_id", id)
+    
+    result, err := r.db.ExecContext(ctx, "UPDATE users SET email_verified_at = COALESCE(email_verified_at, CURRENT_TIMESTAMP) WHERE id = $1 AND deleted_at IS NULL", id)
+    if err != nil {
+        slog.ErrorContext(ctx, "Erro ao confirmar email", "error", err)
+        return err
+    }
+    
+    return requireAffected(result, &UserNotFoundError{ID: id})
+}
+
+func requireAffected(result sql.Result, notFound error) error {
+    affected, err := result.RowsAffected()
+    if err != nil {
+        return err
+    }
+    if affected == 0 {
+        return notFound
+    }
+    return nil
 }
 
 type DuplicateEmailError struct {
@@ -119,7 +292,7 @@ type UserNotFoundError struct {
 
 func (e *UserNotFoundError) Error() string {
     if e.ID != 0 {
-        return "usuário não encontrado com ID: " + string(rune(e.ID))
+        return "usuário não encontrado com ID: " + strconv.Itoa(e.ID)
     }
     return "usuário não encontrado com email: " + e.Email
 } 
\ No newline at end of file
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserNotFoundError_FormatsID(t *testing.T) {
+	assert.Equal(t, "usuário não encontrado com ID: 42", (&UserNotFoundError{ID: 42}).Error())
+	assert.Equal(t, "usuário não encontrado com email: a@b.com", (&UserNotFoundError{Email: "a@b.com"}).Error())
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"github.com/lib/pq"
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+)
+
+type webhookSenderRepository struct {
+	db DBInterface
+}
+
+func NewWebhookSenderRepository(db DBInterface) WebhookSenderRepository {
+	return &webhookSenderRepository{db: db}
+}
+
+const webhookSenderColumns = "id, user_id, org_id, name, key_id, secret, sites, revoked_at, created_at"
+
+func (r *webhookSenderRepository) Create(ctx context.Context, sender *domain.WebhookSender) (*domain.WebhookSender, error) {
+	query := `
+		INSERT INTO webhook_senders (user_id, org_id, name, key_id, secret, sites)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + webhookSenderColumns
+
+	row := r.db.QueryRowContext(ctx, query, sender.UserID, sender.OrgID, sender.Name, sender.KeyID, sender.Secret, pq.Array(sender.Sites))
+
+	created, err := scanWebhookSender(row)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar remetente: %w", err)
+	}
+
+	return created, nil
+}
+
+func (r *webhookSenderRepository) GetByKeyID(ctx context.Context, keyID string) (*domain.WebhookSender, error) {
+	// Como nas chaves de API, remetentes de usuários removidos não autenticam.
+	row := r.db.QueryRowContext(ctx, "SELECT "+webhookSenderColumns+" FROM webhook_senders WHERE key_id = $1 AND user_id IN (SELECT id FROM users WHERE deleted_at IS NULL)", keyID)
+
+	sender, err := scanWebhookSender(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &WebhookSenderNotFoundError{}
+		}
+		return nil, fmt.Errorf("erro ao buscar remetente: %w", err)
+	}
+
+	return sender, nil
+}
+
+func (r *webhookSenderRepository) ListByUser(ctx context.Context, userID int) ([]domain.WebhookSender, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+webhookSenderColumns+" FROM webhook_senders WHERE user_id = $1 ORDER BY id", userID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar remetentes: %w", err)
+	}
+	defer rows.Close()
+
+	senders := []domain.WebhookSender{}
+	for rows.Next() {
+		sender, err := scanWebhookSender(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler remetente: %w", err)
+		}
+		senders = append(senders, *sender)
+	}
+
+	return senders, rows.Err()
+}
+
+func (r *webhookSenderRepository) Revoke(ctx context.Context, id, userID int) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE webhook_senders SET revoked_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+	`, id, userID)
+	if err != nil {
+		return fmt.Errorf("erro ao revogar remetente: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao revogar remetente: %w", err)
+	}
+	if affected == 0 {
+		return &WebhookSenderNotFoundError{ID: id}
+	}
+
+	return nil
+}
+
+func scanWebhookSender(row rowScanner) (*domain.WebhookSender, error) {
+	var sender domain.WebhookSender
+	var revokedAt sql.NullTime
+
+	err := row.Scan(&sender.ID, &sender.UserID, &sender.OrgID, &sender.Name, &sender.KeyID, &sender.Secret,
+		pq.Array(&sender.Sites), &revokedAt, &sender.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	sender.RevokedAt = nullTimePtr(revokedAt)
+
+	return &sender, nil
+}
+
+type WebhookSenderNotFoundError struct {
+	ID int
+}
+
+func (e *WebhookSenderNotFoundError) Error() string {
+	if e.ID != 0 {
+		return "remetente não encontrado com ID: " + strconv.Itoa(e.ID)
+	}
+	return "remetente não encontrado"
+}
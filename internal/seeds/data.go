@@ -1,13 +1,29 @@
 package seeds
 
 import (
+	"crypto/rand"
 	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
 
+	"github.com/nathaliaoliveira/goapp/internal/env"
+	"github.com/nathaliaoliveira/goapp/internal/service"
 	"golang.org/x/crypto/bcrypt"
 )
 
-func RunSeeds(db *sql.DB) error {
-	if err := createInitialUsers(db); err != nil {
+const (
+	adminName  = "Admin User"
+	adminEmail = "admin@test.com"
+)
+
+// RunSeeds cria o administrador inicial e os eventos de exemplo. A senha do
+// administrador precisa atender a policy.
+func RunSeeds(db *sql.DB, policy *service.PasswordPolicy) error {
+	if err := createInitialUsers(db, policy); err != nil {
 		return err
 	}
 	
@@ -18,7 +34,7 @@ func RunSeeds(db *sql.DB) error {
 	return nil
 }
 
-func createInitialUsers(db *sql.DB) error {
+func createInitialUsers(db *sql.DB, policy *service.PasswordPolicy) error {
 	var count int
 	err := db.QueryRow("SELECT COUNT(*) FROM users").Scan(&count)
 	if err != nil {
@@ -29,17 +45,32 @@ func createInitialUsers(db *sql.DB) error {
 		return nil
 	}
 	
-	adminPassword := "admin123"
+	adminPassword := env.String("SEED_ADMIN_PASSWORD", "")
+	if adminPassword != "" {
+		if details := policy.Validate(adminPassword, adminEmail, adminName); len(details) > 0 {
+			return fmt.Errorf("SEED_ADMIN_PASSWORD não atende à política de senhas: %s", policyMessages(details))
+		}
+	} else {
+		generated, err := generatePassword(policy)
+		if err != nil {
+			return err
+		}
+		if err := writePasswordFile(env.String("SEED_ADMIN_PASSWORD_FILE", "admin-password.txt"), generated); err != nil {
+			return err
+		}
+		adminPassword = generated
+	}
+	
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(adminPassword), bcrypt.DefaultCost)
 	if err != nil {
 		return err
 	}
 	
 	_, err = db.Exec(`
-		INSERT INTO users (name, email, password_hash) 
-		VALUES ($1, $2, $3)
-		ON CONFLICT (email) DO NOTHING
-	`, "Admin User", "admin@test.com", string(hashedPassword))
+		INSERT INTO users (org_id, name, email, password_hash, role, email_verified_at) 
+		VALUES ((SELECT MIN(id) FROM organizations), $1, $2, $3, 'admin', CURRENT_TIMESTAMP)
+		ON CONFLICT (email) WHERE deleted_at IS NULL DO NOTHING
+	`, adminName, adminEmail, string(hashedPassword))
 	
 	if err != nil {
 		return err
@@ -48,6 +79,57 @@ func createInitialUsers(db *sql.DB) error {
 	return nil
 }
 
+// generatePassword sorteia senhas até uma atender à política; com 24
+// caracteres aleatórios isso costuma acontecer na primeira.
+func generatePassword(policy *service.PasswordPolicy) (string, error) {
+	for i := 0; i < 10; i++ {
+		password, err := randomPassword()
+		if err != nil {
+			return "", err
+		}
+		if len(policy.Validate(password, adminEmail, adminName)) == 0 {
+			return password, nil
+		}
+	}
+	return "", errors.New("não foi possível gerar uma senha que atenda à política; defina SEED_ADMIN_PASSWORD")
+}
+
+// writePasswordFile grava a senha gerada num arquivo legível só pelo dono, e
+// nunca no stdout ou stderr, que também levam o log da aplicação. Um arquivo
+// já existente não é sobrescrito.
+func writePasswordFile(path, password string) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return fmt.Errorf("erro ao gravar a senha gerada do administrador: %w", err)
+	}
+	if _, err := file.WriteString(password + "\n"); err != nil {
+		file.Close()
+		return fmt.Errorf("erro ao gravar a senha gerada do administrador: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	slog.Info("Senha do administrador inicial gravada em arquivo", "email", adminEmail, "path", path)
+	return nil
+}
+
+func policyMessages(details []service.ValidationDetail) string {
+	messages := make([]string, len(details))
+	for i, detail := range details {
+		messages[i] = detail.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+func randomPassword() (string, error) {
+	buf := make([]byte, 18)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
 func createSampleEvents(db *sql.DB) error {
 	var count int
 	err := db.QueryRow("SELECT COUNT(*) FROM email_events").Scan(&count)
@@ -76,8 +158,8 @@ func createSampleEvents(db *sql.DB) error {
 	
 	for _, event := range sampleEvents {
 		_, err = db.Exec(`
-			INSERT INTO email_events (event_id, event_type, email, site, timestamp, content_hash, campaign_id, subject, ip_address, user_agent)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			INSERT INTO email_events (org_id, event_id, event_type, email, site, timestamp, content_hash, campaign_id, subject, ip_address, user_agent)
+			VALUES ((SELECT MIN(id) FROM organizations), $1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 			ON CONFLICT (event_id) DO NOTHING
 		`, event.eventID, event.eventType, event.email, event.site, event.timestamp, event.contentHash, event.campaignID, event.subject, event.ipAddress, event.userAgent)
 		
@@ -86,5 +168,10 @@ func createSampleEvents(db *sql.DB) error {
 		}
 	}
 	
-	return nil
-} 
\ No newline at end of file
+	_, err = db.Exec(`
+		INSERT INTO sites (org_id, domain)
+		SELECT MIN(org_id), site FROM email_events GROUP BY site
+		ON CONFLICT (domain) DO NOTHING
+	`)
+	return err
+}
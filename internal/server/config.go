@@ -0,0 +1,35 @@
+package server
+
+import (
+	"time"
+
+	"github.com/nathaliaoliveira/goapp/internal/env"
+)
+
+type Config struct {
+	Addr string
+
+	// ReadHeaderTimeout e ReadTimeout limitam clientes lentos (slowloris).
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	// WriteTimeout precisa ser maior que o prazo da rota mais longa
+	// (AUDIT_EXPORT_TIMEOUT), senão a resposta é cortada no meio.
+	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
+
+	// ShutdownTimeout é o prazo para drenar requisições e workers ao desligar.
+	ShutdownTimeout time.Duration
+}
+
+func NewConfig() *Config {
+	return &Config{
+		Addr: ":" + env.String("PORT", "8080"),
+
+		ReadHeaderTimeout: env.Duration("HTTP_READ_HEADER_TIMEOUT", 5*time.Second),
+		ReadTimeout:       env.Duration("HTTP_READ_TIMEOUT", 30*time.Second),
+		WriteTimeout:      env.Duration("HTTP_WRITE_TIMEOUT", 3*time.Minute),
+		IdleTimeout:       env.Duration("HTTP_IDLE_TIMEOUT", 2*time.Minute),
+
+		ShutdownTimeout: env.Duration("SHUTDOWN_TIMEOUT", 30*time.Second),
+	}
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Server envolve o http.Server com desligamento gracioso: ao fim do contexto
+// de Run, para de aceitar conexões, espera as requisições em andamento e os
+// workers registrados em Go, tudo dentro de Config.ShutdownTimeout.
+type Server struct {
+	http            *http.Server
+	shutdownTimeout time.Duration
+
+	workers     sync.WaitGroup
+	workerCtx   context.Context
+	stopWorkers context.CancelFunc
+}
+
+func New(config *Config, handler http.Handler) *Server {
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	return &Server{
+		http: &http.Server{
+			Addr:              config.Addr,
+			Handler:           handler,
+			ReadHeaderTimeout: config.ReadHeaderTimeout,
+			ReadTimeout:       config.ReadTimeout,
+			WriteTimeout:      config.WriteTimeout,
+			IdleTimeout:       config.IdleTimeout,
+			ErrorLog:          slog.NewLogLogger(slog.Default().Handler(), slog.LevelWarn),
+		},
+		shutdownTimeout: config.ShutdownTimeout,
+		workerCtx:       workerCtx,
+		stopWorkers:     stopWorkers,
+	}
+}
+
+// Go executa um worker em segundo plano. O contexto recebido é cancelado
+// quando o servidor começa a desligar, depois que as requisições foram drenadas.
+func (s *Server) Go(worker func(ctx context.Context)) {
+	s.workers.Add(1)
+	go func() {
+		defer s.workers.Done()
+		worker(s.workerCtx)
+	}()
+}
+
+// Run escuta em Config.Addr até ctx terminar e então desliga o servidor.
+func (s *Server) Run(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.http.Addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, ln)
+}
+
+// Serve atende conexões de ln até ctx terminar e então desliga o servidor.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- s.http.Serve(ln)
+	}()
+
+	select {
+	case err := <-serveErr:
+		s.stopWorkers()
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.InfoContext(ctx, "Desligando servidor, aguardando requisições em andamento", "timeout", s.shutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+
+	err := s.http.Shutdown(shutdownCtx)
+	if err != nil {
+		err = fmt.Errorf("requisições não finalizadas a tempo: %w", err)
+	}
+	if serr := <-serveErr; serr != nil && !errors.Is(serr, http.ErrServerClosed) {
+		slog.ErrorContext(ctx, "Erro no servidor HTTP", "error", serr)
+	}
+
+	s.stopWorkers()
+	done := make(chan struct{})
+	go func() {
+		s.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-shutdownCtx.Done():
+		return errors.Join(err, errors.New("workers não finalizados a tempo"))
+	}
+
+	if err == nil {
+		slog.InfoContext(ctx, "Servidor desligado")
+	}
+	return err
+}
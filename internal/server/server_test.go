@@ -0,0 +1,106 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testConfig(shutdown time.Duration) *Config {
+	return &Config{
+		ReadHeaderTimeout: time.Second,
+		ReadTimeout:       time.Second,
+		WriteTimeout:      5 * time.Second,
+		IdleTimeout:       time.Second,
+		ShutdownTimeout:   shutdown,
+	}
+}
+
+func TestServe_DrainsInFlightRequests(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	srv := New(testConfig(5*time.Second), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		io.WriteString(w, "ok")
+	}))
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve(ctx, ln) }()
+
+	body := make(chan string, 1)
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String())
+		if err != nil {
+			body <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		body <- string(b)
+	}()
+
+	<-started
+	cancel()
+
+	// Novas conexões são recusadas enquanto a requisição anterior termina.
+	require.Eventually(t, func() bool {
+		_, err := net.DialTimeout("tcp", ln.Addr().String(), 100*time.Millisecond)
+		return err != nil
+	}, time.Second, 10*time.Millisecond)
+
+	close(release)
+	assert.Equal(t, "ok", <-body)
+	assert.NoError(t, <-served)
+}
+
+func TestServe_StopsWorkers(t *testing.T) {
+	srv := New(testConfig(time.Second), http.NotFoundHandler())
+
+	stopped := make(chan struct{})
+	srv.Go(func(ctx context.Context) {
+		<-ctx.Done()
+		close(stopped)
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.NoError(t, srv.Serve(ctx, ln))
+	select {
+	case <-stopped:
+	default:
+		t.Fatal("worker não foi finalizado")
+	}
+}
+
+func TestServe_ShutdownDeadline(t *testing.T) {
+	srv := New(testConfig(50*time.Millisecond), http.NotFoundHandler())
+
+	release := make(chan struct{})
+	defer close(release)
+	srv.Go(func(ctx context.Context) {
+		<-release
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.Error(t, srv.Serve(ctx, ln))
+}
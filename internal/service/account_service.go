@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/i18n"
+	"github.com/nathaliaoliveira/goapp/internal/mailer"
+	"github.com/nathaliaoliveira/goapp/internal/repository"
+	"github.com/nathaliaoliveira/goapp/internal/tracing"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	passwordResetTTL     = time.Hour
+	emailVerificationTTL = 48 * time.Hour
+
+	// backgroundJobTimeout limita o trabalho feito fora da requisição, como
+	// gravar o token e enviar o email de redefinição.
+	backgroundJobTimeout = 30 * time.Second
+)
+
+type accountService struct {
+	userRepo  repository.UserRepository
+	tokenRepo repository.TokenRepository
+	mailer    mailer.Mailer
+	policy    *PasswordPolicy
+	baseURL    string
+	background func(job func())
+	now        func() time.Time
+}
+
+type AccountServiceOption func(*accountService)
+
+// WithBackground define onde rodam os trabalhos feitos fora da requisição. O
+// padrão é uma goroutine solta; o servidor registra os seus para drená-los
+// ao desligar.
+func WithBackground(run func(job func())) AccountServiceOption {
+	return func(s *accountService) {
+		s.background = run
+	}
+}
+
+func NewAccountService(userRepo repository.UserRepository, tokenRepo repository.TokenRepository, m mailer.Mailer, policy *PasswordPolicy, baseURL string, opts ...AccountServiceOption) AccountService {
+	if policy == nil {
+		policy = DefaultPasswordPolicy()
+	}
+	s := &accountService{
+		userRepo:   userRepo,
+		tokenRepo:  tokenRepo,
+		mailer:     m,
+		policy:     policy,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		background: func(job func()) { go job() },
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// RequestPasswordReset nunca informa se o email existe: emails desconhecidos
+// são ignorados silenciosamente e, para emails cadastrados, o token e o envio
+// ficam em segundo plano, para que o tempo de resposta seja o mesmo nos dois
+// casos.
+func (s *accountService) RequestPasswordReset(ctx context.Context, email string) error {
+	ctx, span := tracing.Start(ctx, "AccountService.RequestPasswordReset")
+	defer span.End()
+
+	if email == "" {
+		return &ValidationError{Key: "account.email_required"}
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if _, ok := err.(*repository.UserNotFoundError); ok {
+			return nil
+		}
+		return &InternalError{Key: "account.user_lookup_failed", Cause: err}
+	}
+
+	jobCtx := context.WithoutCancel(ctx)
+	s.background(func() {
+		ctx, cancel := context.WithTimeout(jobCtx, backgroundJobTimeout)
+		defer cancel()
+		if err := s.sendPasswordReset(ctx, user); err != nil {
+			slog.ErrorContext(ctx, "Erro ao enviar redefinição de senha", "user_id", user.ID, "error", err)
+		}
+	})
+
+	return nil
+}
+
+func (s *accountService) sendPasswordReset(ctx context.Context, user *domain.User) error {
+	if err := s.tokenRepo.InvalidateForUser(ctx, user.ID, domain.TokenPurposePasswordReset); err != nil {
+		return &InternalError{Key: "account.token_invalidation_failed", Cause: err}
+	}
+
+	token, err := s.issueToken(ctx, user.ID, domain.TokenPurposePasswordReset, passwordResetTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.send(mailer.Message{
+		To:      user.Email,
+		Subject: i18n.Translate(ctx, "mail.password_reset.subject"),
+		Body:    i18n.Translate(ctx, "mail.password_reset.body", user.Name, s.baseURL+"/password/reset?token="+token),
+	})
+}
+
+func (s *accountService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	ctx, span := tracing.Start(ctx, "AccountService.ResetPassword")
+	defer span.End()
+
+	if token == "" || newPassword == "" {
+		return &ValidationError{Key: "account.token_and_password_required"}
+	}
+
+	tokenHash := hashToken(token)
+
+	// A política é checada antes de consumir o token, para que uma senha fraca não invalide o link.
+	userID, err := s.tokenRepo.Lookup(ctx, domain.TokenPurposePasswordReset, tokenHash)
+	if err != nil {
+		return tokenError(err)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.policy.check(newPassword, user.Email, user.Name); err != nil {
+		return err
+	}
+
+	if _, err := s.tokenRepo.Consume(ctx, domain.TokenPurposePasswordReset, tokenHash); err != nil {
+		return tokenError(err)
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return &InternalError{Key: "password.hash_failed", Cause: err}
+	}
+
+	return s.userRepo.UpdatePassword(ctx, userID, string(hashedPassword))
+}
+
+func (s *accountService) SendVerification(ctx context.Context, user *domain.User) error {
+	ctx, span := tracing.Start(ctx, "AccountService.SendVerification")
+	defer span.End()
+
+	if user.EmailVerified {
+		return nil
+	}
+
+	if err := s.tokenRepo.InvalidateForUser(ctx, user.ID, domain.TokenPurposeEmailVerification); err != nil {
+		return &InternalError{Key: "account.token_invalidation_failed", Cause: err}
+	}
+
+	token, err := s.issueToken(ctx, user.ID, domain.TokenPurposeEmailVerification, emailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.send(mailer.Message{
+		To:      user.Email,
+		Subject: i18n.Translate(ctx, "mail.email_verification.subject"),
+		Body:    i18n.Translate(ctx, "mail.email_verification.body", user.Name, s.baseURL+"/email/verify?token="+token),
+	})
+}
+
+func (s *accountService) ResendVerification(ctx context.Context, userID int) error {
+	ctx, span := tracing.Start(ctx, "AccountService.ResendVerification")
+	defer span.End()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if user.EmailVerified {
+		return &ValidationError{Key: "account.email_already_verified"}
+	}
+
+	return s.SendVerification(ctx, user)
+}
+
+func (s *accountService) VerifyEmail(ctx context.Context, token string) error {
+	ctx, span := tracing.Start(ctx, "AccountService.VerifyEmail")
+	defer span.End()
+
+	if token == "" {
+		return &ValidationError{Key: "account.token_required"}
+	}
+
+	userID, err := s.tokenRepo.Consume(ctx, domain.TokenPurposeEmailVerification, hashToken(token))
+	if err != nil {
+		return tokenError(err)
+	}
+
+	return s.userRepo.MarkEmailVerified(ctx, userID)
+}
+
+func (s *accountService) issueToken(ctx context.Context, userID int, purpose string, ttl time.Duration) (string, error) {
+	token, err := randomHex(32)
+	if err != nil {
+		return "", &InternalError{Key: "token.generate_failed", Cause: err}
+	}
+
+	if err := s.tokenRepo.Create(ctx, userID, purpose, hashToken(token), s.now().Add(ttl).UTC()); err != nil {
+		return "", &InternalError{Key: "token.save_failed", Cause: err}
+	}
+
+	return token, nil
+}
+
+func (s *accountService) send(msg mailer.Message) error {
+	if err := s.mailer.Send(msg); err != nil {
+		slog.Error("Erro ao enviar email", "to", msg.To, "error", err)
+		return &InternalError{Key: "account.email_send_failed", Cause: err}
+	}
+	return nil
+}
+
+func tokenError(err error) error {
+	if _, ok := err.(*repository.InvalidTokenError); ok {
+		return &ValidationError{Key: "token.invalid_or_expired"}
+	}
+	return &InternalError{Key: "token.validate_failed", Cause: err}
+}
+
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/i18n"
+	"github.com/nathaliaoliveira/goapp/internal/mailer"
+	"github.com/nathaliaoliveira/goapp/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type MockTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockTokenRepository) Create(ctx context.Context, userID int, purpose, tokenHash string, expiresAt time.Time) error {
+	args := m.Called(userID, purpose, tokenHash, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) Lookup(ctx context.Context, purpose, tokenHash string) (int, error) {
+	args := m.Called(purpose, tokenHash)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTokenRepository) Consume(ctx context.Context, purpose, tokenHash string) (int, error) {
+	args := m.Called(purpose, tokenHash)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTokenRepository) InvalidateForUser(ctx context.Context, userID int, purpose string) error {
+	args := m.Called(userID, purpose)
+	return args.Error(0)
+}
+
+var tokenInLink = regexp.MustCompile(`token=([0-9a-f]{64})`)
+
+func TestRequestPasswordReset_SendsSingleUseToken(t *testing.T) {
+	mockUsers := new(MockUserRepository)
+	mockTokens := new(MockTokenRepository)
+	outbox := mailer.NewMemoryMailer()
+	var jobs []func()
+	service := NewAccountService(mockUsers, mockTokens, outbox, nil, "http://app.test", WithBackground(func(job func()) { jobs = append(jobs, job) }))
+
+	user := &domain.User{ID: 1, Name: "Test User", Email: "test@example.com"}
+	mockUsers.On("GetByEmail", "test@example.com").Return(user, nil)
+	mockTokens.On("InvalidateForUser", 1, domain.TokenPurposePasswordReset).Return(nil)
+	mockTokens.On("Create", 1, domain.TokenPurposePasswordReset, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(nil)
+
+	err := service.RequestPasswordReset(ctx, "test@example.com")
+
+	assert.NoError(t, err)
+	assert.Empty(t, outbox.Messages(), "o token e o envio não atrasam a resposta")
+	mockTokens.AssertNotCalled(t, "Create")
+
+	require.Len(t, jobs, 1)
+	jobs[0]()
+	messages := outbox.Messages()
+	assert.Len(t, messages, 1)
+	assert.Equal(t, "test@example.com", messages[0].To)
+
+	match := tokenInLink.FindStringSubmatch(messages[0].Body)
+	assert.Len(t, match, 2)
+	storedHash := mockTokens.Calls[1].Arguments.String(2)
+	assert.Equal(t, hashToken(match[1]), storedHash)
+
+	mockUsers.AssertExpectations(t)
+	mockTokens.AssertExpectations(t)
+}
+
+func TestRequestPasswordReset_UnknownEmailIsSilent(t *testing.T) {
+	mockUsers := new(MockUserRepository)
+	mockTokens := new(MockTokenRepository)
+	outbox := mailer.NewMemoryMailer()
+	service := NewAccountService(mockUsers, mockTokens, outbox, nil, "http://app.test", WithBackground(func(job func()) {
+		t.Fatal("email desconhecido não agenda trabalho")
+	}))
+
+	mockUsers.On("GetByEmail", "ghost@example.com").Return(nil, &repository.UserNotFoundError{Email: "ghost@example.com"})
+
+	err := service.RequestPasswordReset(ctx, "ghost@example.com")
+
+	assert.NoError(t, err)
+	assert.Empty(t, outbox.Messages())
+	mockTokens.AssertNotCalled(t, "Create")
+}
+
+func TestResetPassword_ValidToken(t *testing.T) {
+	mockUsers := new(MockUserRepository)
+	mockTokens := new(MockTokenRepository)
+	service := NewAccountService(mockUsers, mockTokens, mailer.NewMemoryMailer(), nil, "http://app.test")
+
+	mockTokens.On("Lookup", domain.TokenPurposePasswordReset, hashToken("raw-token")).Return(1, nil)
+	mockTokens.On("Consume", domain.TokenPurposePasswordReset, hashToken("raw-token")).Return(1, nil)
+	mockUsers.On("GetByID", 1).Return(&domain.User{ID: 1, Name: "Test User", Email: "test@example.com"}, nil)
+	mockUsers.On("UpdatePassword", 1, mock.AnythingOfType("string")).Return(nil)
+
+	err := service.ResetPassword(ctx, "raw-token", "N0va-Senha!")
+
+	assert.NoError(t, err)
+	newHash := mockUsers.Calls[1].Arguments.String(1)
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(newHash), []byte("N0va-Senha!")))
+
+	mockTokens.AssertExpectations(t)
+	mockUsers.AssertExpectations(t)
+}
+
+func TestResetPassword_UsedOrExpiredToken(t *testing.T) {
+	mockUsers := new(MockUserRepository)
+	mockTokens := new(MockTokenRepository)
+	service := NewAccountService(mockUsers, mockTokens, mailer.NewMemoryMailer(), nil, "http://app.test")
+
+	mockTokens.On("Lookup", domain.TokenPurposePasswordReset, hashToken("raw-token")).Return(0, &repository.InvalidTokenError{})
+
+	err := service.ResetPassword(ctx, "raw-token", "N0va-Senha!")
+
+	assert.Error(t, err)
+	assert.IsType(t, &ValidationError{}, err)
+	mockUsers.AssertNotCalled(t, "UpdatePassword")
+}
+
+func TestResetPassword_WeakPasswordKeepsToken(t *testing.T) {
+	mockUsers := new(MockUserRepository)
+	mockTokens := new(MockTokenRepository)
+	service := NewAccountService(mockUsers, mockTokens, mailer.NewMemoryMailer(), nil, "http://app.test")
+
+	mockTokens.On("Lookup", domain.TokenPurposePasswordReset, hashToken("raw-token")).Return(1, nil)
+	mockUsers.On("GetByID", 1).Return(&domain.User{ID: 1, Name: "Test User", Email: "test@example.com"}, nil)
+
+	err := service.ResetPassword(ctx, "raw-token", "password123")
+
+	assert.Error(t, err)
+	validationErr, ok := err.(*ValidationError)
+	assert.True(t, ok)
+	assert.NotEmpty(t, validationErr.Details)
+	mockTokens.AssertNotCalled(t, "Consume", mock.Anything, mock.Anything)
+	mockUsers.AssertNotCalled(t, "UpdatePassword")
+}
+
+func TestSendVerification_SkipsVerifiedUser(t *testing.T) {
+	mockUsers := new(MockUserRepository)
+	mockTokens := new(MockTokenRepository)
+	outbox := mailer.NewMemoryMailer()
+	service := NewAccountService(mockUsers, mockTokens, outbox, nil, "http://app.test")
+
+	err := service.SendVerification(ctx, &domain.User{ID: 1, Email: "test@example.com", EmailVerified: true})
+
+	assert.NoError(t, err)
+	assert.Empty(t, outbox.Messages())
+}
+
+func TestSendVerification_UsesRequestLanguage(t *testing.T) {
+	mockTokens := new(MockTokenRepository)
+	outbox := mailer.NewMemoryMailer()
+	service := NewAccountService(new(MockUserRepository), mockTokens, outbox, nil, "http://app.test")
+
+	mockTokens.On("InvalidateForUser", 1, domain.TokenPurposeEmailVerification).Return(nil)
+	mockTokens.On("Create", 1, domain.TokenPurposeEmailVerification, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(nil)
+
+	err := service.SendVerification(i18n.WithLanguage(ctx, "en"), &domain.User{ID: 1, Name: "Test User", Email: "test@example.com"})
+
+	require.NoError(t, err)
+	messages := outbox.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "Confirm your email", messages[0].Subject)
+	assert.Contains(t, messages[0].Body, "Hello, Test User.")
+	assert.Contains(t, messages[0].Body, "http://app.test/email/verify?token=")
+}
+
+func TestVerifyEmail_ValidToken(t *testing.T) {
+	mockUsers := new(MockUserRepository)
+	mockTokens := new(MockTokenRepository)
+	service := NewAccountService(mockUsers, mockTokens, mailer.NewMemoryMailer(), nil, "http://app.test")
+
+	mockTokens.On("Consume", domain.TokenPurposeEmailVerification, hashToken("raw-token")).Return(1, nil)
+	mockUsers.On("MarkEmailVerified", 1).Return(nil)
+
+	err := service.VerifyEmail(ctx, "raw-token")
+
+	assert.NoError(t, err)
+	mockUsers.AssertExpectations(t)
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/repository"
+	"github.com/nathaliaoliveira/goapp/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	DefaultAnalyticsLimit = 50
+	MaxAnalyticsLimit     = 500
+	DefaultStatsLimit     = 500
+	MaxStatsLimit         = 2000
+	// defaultAnalyticsDays é o período usado quando o filtro não informa datas.
+	defaultAnalyticsDays = 30
+	// maxAnalyticsDays limita o período de cada consulta, para que nenhuma
+	// varra o histórico inteiro da organização.
+	maxAnalyticsDays = 366
+)
+
+const analyticsDateLayout = "2006-01-02"
+
+type analyticsService struct {
+	analyticsRepo repository.AnalyticsRepository
+	now           func() time.Time
+}
+
+func NewAnalyticsService(analyticsRepo repository.AnalyticsRepository) AnalyticsService {
+	return &analyticsService{analyticsRepo: analyticsRepo, now: time.Now}
+}
+
+func (s *analyticsService) ListEvents(ctx context.Context, orgID int, filter domain.AnalyticsFilter, page domain.AnalyticsPage) ([]domain.StoredEvent, error) {
+	ctx, span := tracing.Start(ctx, "AnalyticsService.ListEvents")
+	defer span.End()
+
+	filter, page, err := s.prepare(filter, page)
+	if err != nil {
+		return nil, err
+	}
+	return s.analyticsRepo.ListEvents(ctx, orgID, filter, page)
+}
+
+func (s *analyticsService) ListCampaigns(ctx context.Context, orgID int, filter domain.AnalyticsFilter, page domain.AnalyticsPage) ([]domain.CampaignSummary, error) {
+	ctx, span := tracing.Start(ctx, "AnalyticsService.ListCampaigns")
+	defer span.End()
+
+	filter, page, err := s.prepare(filter, page)
+	if err != nil {
+		return nil, err
+	}
+	return s.analyticsRepo.ListCampaigns(ctx, orgID, filter, page)
+}
+
+func (s *analyticsService) ListContacts(ctx context.Context, orgID int, filter domain.AnalyticsFilter, page domain.AnalyticsPage) ([]domain.ContactSummary, error) {
+	ctx, span := tracing.Start(ctx, "AnalyticsService.ListContacts")
+	defer span.End()
+
+	filter, page, err := s.prepare(filter, page)
+	if err != nil {
+		return nil, err
+	}
+	return s.analyticsRepo.ListContacts(ctx, orgID, filter, page)
+}
+
+func (s *analyticsService) AggregateStats(ctx context.Context, orgID int, query domain.StatsQuery) ([]domain.StatsBucket, error) {
+	ctx, span := tracing.Start(ctx, "AnalyticsService.AggregateStats",
+		attribute.String("stats.granularity", query.Granularity))
+	defer span.End()
+
+	filter, err := s.normalizeFilter(query.Filter)
+	if err != nil {
+		return nil, err
+	}
+	query.Filter = filter
+
+	switch query.Granularity {
+	case "":
+		query.Granularity = domain.GranularityDay
+	case domain.GranularityDay, domain.GranularityWeek, domain.GranularityMonth:
+	default:
+		return nil, &ValidationError{Key: "analytics.invalid_granularity", Args: []interface{}{query.Granularity}}
+	}
+
+	seen := make(map[string]bool, len(query.GroupBy))
+	for _, dimension := range query.GroupBy {
+		switch dimension {
+		case domain.DimensionSite, domain.DimensionEventType, domain.DimensionCampaign:
+		default:
+			return nil, &ValidationError{Key: "analytics.invalid_dimension", Args: []interface{}{dimension}}
+		}
+		if seen[dimension] {
+			return nil, &ValidationError{Key: "analytics.duplicate_dimension", Args: []interface{}{dimension}}
+		}
+		seen[dimension] = true
+	}
+
+	if query.Limit, err = StatsLimit(query.Limit); err != nil {
+		return nil, err
+	}
+
+	return s.analyticsRepo.AggregateStats(ctx, orgID, query)
+}
+
+func (s *analyticsService) prepare(filter domain.AnalyticsFilter, page domain.AnalyticsPage) (domain.AnalyticsFilter, domain.AnalyticsPage, error) {
+	filter, err := s.normalizeFilter(filter)
+	if err != nil {
+		return filter, page, err
+	}
+	if page.Limit, err = AnalyticsPageLimit(page.Limit); err != nil {
+		return filter, page, err
+	}
+	if page.Offset < 0 {
+		return filter, page, &ValidationError{Key: "analytics.invalid_offset"}
+	}
+	return filter, page, nil
+}
+
+// normalizeFilter completa o período ausente (até hoje, 30 dias para trás) e
+// recusa períodos invertidos ou maiores que maxAnalyticsDays.
+func (s *analyticsService) normalizeFilter(filter domain.AnalyticsFilter) (domain.AnalyticsFilter, error) {
+	end := s.now().UTC().Truncate(24 * time.Hour)
+	if filter.EndDate != "" {
+		parsed, err := time.Parse(analyticsDateLayout, filter.EndDate)
+		if err != nil {
+			return filter, &ValidationError{Key: "analytics.invalid_date", Args: []interface{}{"endDate"}}
+		}
+		end = parsed
+	}
+
+	start := end.AddDate(0, 0, -(defaultAnalyticsDays - 1))
+	if filter.StartDate != "" {
+		parsed, err := time.Parse(analyticsDateLayout, filter.StartDate)
+		if err != nil {
+			return filter, &ValidationError{Key: "analytics.invalid_date", Args: []interface{}{"startDate"}}
+		}
+		start = parsed
+	}
+
+	if start.After(end) {
+		return filter, &ValidationError{Key: "analytics.invalid_period"}
+	}
+	if end.Sub(start) >= maxAnalyticsDays*24*time.Hour {
+		return filter, &ValidationError{Key: "analytics.period_too_long", Args: []interface{}{maxAnalyticsDays}}
+	}
+
+	filter.StartDate = start.Format(analyticsDateLayout)
+	filter.EndDate = end.Format(analyticsDateLayout)
+	return filter, nil
+}
+
+// checkLimit usa defaultLimit quando limit é zero e recusa valores fora de 1..maxLimit.
+// AnalyticsPageLimit e StatsLimit devolvem o limit efetivo das listagens e
+// das estatísticas: 0 vale o padrão e fora do intervalo é erro de validação.
+// A API GraphQL usa as mesmas regras para estimar o custo das consultas.
+func AnalyticsPageLimit(limit int) (int, error) {
+	return checkLimit(limit, DefaultAnalyticsLimit, MaxAnalyticsLimit)
+}
+
+func StatsLimit(limit int) (int, error) {
+	return checkLimit(limit, DefaultStatsLimit, MaxStatsLimit)
+}
+
+func checkLimit(limit, defaultLimit, maxLimit int) (int, error) {
+	if limit == 0 {
+		return defaultLimit, nil
+	}
+	if limit < 0 || limit > maxLimit {
+		return 0, &ValidationError{Key: "analytics.invalid_limit", Args: []interface{}{maxLimit}}
+	}
+	return limit, nil
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockAnalyticsRepository struct {
+	mock.Mock
+}
+
+func (m *MockAnalyticsRepository) ListEvents(ctx context.Context, orgID int, filter domain.AnalyticsFilter, page domain.AnalyticsPage) ([]domain.StoredEvent, error) {
+	args := m.Called(orgID, filter, page)
+	return args.Get(0).([]domain.StoredEvent), args.Error(1)
+}
+
+func (m *MockAnalyticsRepository) ListCampaigns(ctx context.Context, orgID int, filter domain.AnalyticsFilter, page domain.AnalyticsPage) ([]domain.CampaignSummary, error) {
+	args := m.Called(orgID, filter, page)
+	return args.Get(0).([]domain.CampaignSummary), args.Error(1)
+}
+
+func (m *MockAnalyticsRepository) ListContacts(ctx context.Context, orgID int, filter domain.AnalyticsFilter, page domain.AnalyticsPage) ([]domain.ContactSummary, error) {
+	args := m.Called(orgID, filter, page)
+	return args.Get(0).([]domain.ContactSummary), args.Error(1)
+}
+
+func (m *MockAnalyticsRepository) AggregateStats(ctx context.Context, orgID int, query domain.StatsQuery) ([]domain.StatsBucket, error) {
+	args := m.Called(orgID, query)
+	return args.Get(0).([]domain.StatsBucket), args.Error(1)
+}
+
+func newTestAnalyticsService(repo *MockAnalyticsRepository) *analyticsService {
+	return &analyticsService{
+		analyticsRepo: repo,
+		now:           func() time.Time { return time.Date(2026, 3, 10, 15, 4, 5, 0, time.UTC) },
+	}
+}
+
+func TestAnalyticsListEvents_DefaultsPeriodAndPage(t *testing.T) {
+	repo := new(MockAnalyticsRepository)
+	service := newTestAnalyticsService(repo)
+
+	want := domain.AnalyticsFilter{StartDate: "2026-02-09", EndDate: "2026-03-10", Email: "a@b.com"}
+	repo.On("ListEvents", 3, want, domain.AnalyticsPage{Limit: DefaultAnalyticsLimit}).Return([]domain.StoredEvent{}, nil)
+
+	_, err := service.ListEvents(ctx, 3, domain.AnalyticsFilter{Email: "a@b.com"}, domain.AnalyticsPage{})
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestAnalyticsListCampaigns_RejectsInvalidFilters(t *testing.T) {
+	service := newTestAnalyticsService(new(MockAnalyticsRepository))
+
+	cases := map[string]struct {
+		filter domain.AnalyticsFilter
+		page   domain.AnalyticsPage
+		key    string
+	}{
+		"data malformada":   {domain.AnalyticsFilter{StartDate: "10/03/2026"}, domain.AnalyticsPage{}, "analytics.invalid_date"},
+		"período invertido": {domain.AnalyticsFilter{StartDate: "2026-03-02", EndDate: "2026-03-01"}, domain.AnalyticsPage{}, "analytics.invalid_period"},
+		"período longo":     {domain.AnalyticsFilter{StartDate: "2025-01-01", EndDate: "2026-01-02"}, domain.AnalyticsPage{}, "analytics.period_too_long"},
+		"limit alto":        {domain.AnalyticsFilter{}, domain.AnalyticsPage{Limit: MaxAnalyticsLimit + 1}, "analytics.invalid_limit"},
+		"offset negativo":   {domain.AnalyticsFilter{}, domain.AnalyticsPage{Offset: -1}, "analytics.invalid_offset"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := service.ListCampaigns(ctx, 3, tc.filter, tc.page)
+			var validation *ValidationError
+			require.ErrorAs(t, err, &validation)
+			assert.Equal(t, tc.key, validation.Key)
+		})
+	}
+}
+
+func TestAnalyticsAggregateStats_ValidatesGrouping(t *testing.T) {
+	repo := new(MockAnalyticsRepository)
+	service := newTestAnalyticsService(repo)
+	filter := domain.AnalyticsFilter{StartDate: "2026-01-01", EndDate: "2026-01-31"}
+
+	_, err := service.AggregateStats(ctx, 3, domain.StatsQuery{Filter: filter, Granularity: "hour"})
+	assert.IsType(t, &ValidationError{}, err)
+
+	_, err = service.AggregateStats(ctx, 3, domain.StatsQuery{Filter: filter, GroupBy: []string{domain.DimensionSite, domain.DimensionSite}})
+	assert.IsType(t, &ValidationError{}, err)
+
+	_, err = service.AggregateStats(ctx, 3, domain.StatsQuery{Filter: filter, GroupBy: []string{"email"}})
+	assert.IsType(t, &ValidationError{}, err)
+
+	repo.On("AggregateStats", 3, domain.StatsQuery{
+		Filter:      filter,
+		Granularity: domain.GranularityDay,
+		GroupBy:     []string{domain.DimensionEventType},
+		Limit:       DefaultStatsLimit,
+	}).Return([]domain.StatsBucket{{Period: "2026-01-01", EventType: "open", Count: 2}}, nil)
+
+	buckets, err := service.AggregateStats(ctx, 3, domain.StatsQuery{Filter: filter, GroupBy: []string{domain.DimensionEventType}})
+	require.NoError(t, err)
+	assert.Len(t, buckets, 1)
+	repo.AssertExpectations(t)
+}
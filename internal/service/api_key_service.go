@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/repository"
+	"github.com/nathaliaoliveira/goapp/internal/tracing"
+)
+
+const (
+	apiKeyPrefix    = "evk_"
+	apiKeyPrefixLen = 8
+)
+
+type apiKeyService struct {
+	apiKeyRepo repository.APIKeyRepository
+	now        func() time.Time
+}
+
+func NewAPIKeyService(apiKeyRepo repository.APIKeyRepository) APIKeyService {
+	return &apiKeyService{
+		apiKeyRepo: apiKeyRepo,
+		now:        time.Now,
+	}
+}
+
+func (s *apiKeyService) Create(ctx context.Context, actor domain.Actor, req domain.CreateAPIKeyRequest) (*domain.APIKeyCreatedResponse, error) {
+	ctx, span := tracing.Start(ctx, "APIKeyService.Create")
+	defer span.End()
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, &ValidationError{Key: "api_key.name_required"}
+	}
+
+	scopes := req.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{domain.ScopeEventsWrite}
+	}
+	for _, scope := range scopes {
+		if !isKnownScope(scope) {
+			return nil, &ValidationError{Key: "api_key.invalid_scope", Args: []interface{}{scope}}
+		}
+	}
+
+	sites := []string{}
+	for _, site := range req.Sites {
+		if site = strings.TrimSpace(site); site != "" {
+			sites = append(sites, site)
+		}
+	}
+
+	// expires_at é TIMESTAMP, sem fuso: o Postgres descartaria o deslocamento
+	// enviado pelo cliente (ex.: -03:00), então a expiração é gravada em UTC.
+	var expiresAt *time.Time
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(s.now()) {
+			return nil, &ValidationError{Key: "api_key.expiration_in_past"}
+		}
+		utc := req.ExpiresAt.UTC()
+		expiresAt = &utc
+	}
+
+	rawKey, err := generateAPIKey()
+	if err != nil {
+		return nil, &InternalError{Key: "api_key.generate_failed", Cause: err}
+	}
+
+	key, err := s.apiKeyRepo.Create(ctx, &domain.APIKey{
+		UserID:    actor.UserID,
+		OrgID:     actor.OrgID,
+		Name:      name,
+		Prefix:    rawKey[:len(apiKeyPrefix)+apiKeyPrefixLen],
+		Scopes:    scopes,
+		Sites:     sites,
+		ExpiresAt: expiresAt,
+	}, hashAPIKey(rawKey))
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.APIKeyCreatedResponse{
+		Key:    rawKey,
+		APIKey: *key,
+	}, nil
+}
+
+func (s *apiKeyService) List(ctx context.Context, userID int) ([]domain.APIKey, error) {
+	ctx, span := tracing.Start(ctx, "APIKeyService.List")
+	defer span.End()
+
+	return s.apiKeyRepo.ListByUser(ctx, userID)
+}
+
+func (s *apiKeyService) Revoke(ctx context.Context, userID, id int) error {
+	ctx, span := tracing.Start(ctx, "APIKeyService.Revoke")
+	defer span.End()
+
+	return s.apiKeyRepo.Revoke(ctx, id, userID)
+}
+
+func (s *apiKeyService) Authenticate(ctx context.Context, rawKey string) (*domain.APIKey, error) {
+	ctx, span := tracing.Start(ctx, "APIKeyService.Authenticate")
+	defer span.End()
+
+	if !strings.HasPrefix(rawKey, apiKeyPrefix) {
+		return nil, &AuthenticationError{Key: "api_key.invalid"}
+	}
+
+	key, err := s.apiKeyRepo.GetByHash(ctx, hashAPIKey(rawKey))
+	if err != nil {
+		return nil, &AuthenticationError{Key: "api_key.invalid"}
+	}
+
+	if key.RevokedAt != nil {
+		return nil, &AuthenticationError{Key: "api_key.revoked"}
+	}
+
+	if key.ExpiresAt != nil && !key.ExpiresAt.After(s.now()) {
+		return nil, &AuthenticationError{Key: "api_key.expired"}
+	}
+
+	if err := s.apiKeyRepo.TouchLastUsed(ctx, key.ID); err != nil {
+		slog.WarnContext(ctx, "Erro ao registrar uso da chave de API", "key_id", key.ID, "error", err)
+	}
+
+	return key, nil
+}
+
+func isKnownScope(scope string) bool {
+	for _, known := range domain.APIKeyScopes {
+		if scope == known {
+			return true
+		}
+	}
+	return false
+}
+
+func generateAPIKey() (string, error) {
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return apiKeyPrefix + hex.EncodeToString(secret), nil
+}
+
+// As chaves têm 192 bits de entropia, então um SHA-256 simples basta para armazená-las.
+func hashAPIKey(rawKey string) string {
+	hash := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(hash[:])
+}
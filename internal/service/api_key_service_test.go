@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) Create(ctx context.Context, key *domain.APIKey, keyHash string) (*domain.APIKey, error) {
+	args := m.Called(key, keyHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	args := m.Called(keyHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) ListByUser(ctx context.Context, userID int) ([]domain.APIKey, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]domain.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) Revoke(ctx context.Context, id, userID int) error {
+	args := m.Called(id, userID)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) TouchLastUsed(ctx context.Context, id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func TestCreateAPIKey_StoresHashOnly(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	service := NewAPIKeyService(mockRepo)
+
+	var storedHash string
+	mockRepo.On("Create", mock.AnythingOfType("*domain.APIKey"), mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) {
+			storedHash = args.String(1)
+		}).
+		Return(&domain.APIKey{ID: 1, UserID: 7, Name: "worker", Scopes: []string{domain.ScopeEventsWrite}}, nil)
+
+	result, err := service.Create(ctx, domain.Actor{UserID: 7, OrgID: 2, Role: domain.RoleUser}, domain.CreateAPIKeyRequest{Name: "worker", Sites: []string{"site-a.com"}})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.True(t, strings.HasPrefix(result.Key, "evk_"))
+	assert.Equal(t, hashAPIKey(result.Key), storedHash)
+	assert.NotContains(t, storedHash, result.Key)
+
+	created := mockRepo.Calls[0].Arguments.Get(0).(*domain.APIKey)
+	assert.Equal(t, []string{domain.ScopeEventsWrite}, created.Scopes)
+	assert.Equal(t, []string{"site-a.com"}, created.Sites)
+	assert.Equal(t, result.Key[:12], created.Prefix)
+	assert.Equal(t, 2, created.OrgID)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateAPIKey_StoresExpiryInUTC(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	service := NewAPIKeyService(mockRepo)
+	mockRepo.On("Create", mock.AnythingOfType("*domain.APIKey"), mock.AnythingOfType("string")).Return(&domain.APIKey{ID: 1}, nil)
+
+	saoPaulo := time.FixedZone("-03", -3*60*60)
+	expiresAt := time.Now().Add(24 * time.Hour).In(saoPaulo)
+	_, err := service.Create(ctx, domain.Actor{UserID: 7, OrgID: 2, Role: domain.RoleUser}, domain.CreateAPIKeyRequest{Name: "worker", ExpiresAt: &expiresAt})
+	require.NoError(t, err)
+
+	created := mockRepo.Calls[0].Arguments.Get(0).(*domain.APIKey)
+	require.NotNil(t, created.ExpiresAt)
+	assert.Equal(t, time.UTC, created.ExpiresAt.Location())
+	assert.True(t, expiresAt.Equal(*created.ExpiresAt))
+	assert.Equal(t, expiresAt.UTC().Hour(), created.ExpiresAt.Hour(), "o relógio gravado é o de UTC, não o do cliente")
+}
+
+func TestCreateAPIKey_InvalidScope(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	service := NewAPIKeyService(mockRepo)
+
+	result, err := service.Create(ctx, domain.Actor{UserID: 1, OrgID: 1, Role: domain.RoleUser}, domain.CreateAPIKeyRequest{Name: "worker", Scopes: []string{"admin"}})
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.IsType(t, &ValidationError{}, err)
+
+	mockRepo.AssertNotCalled(t, "Create")
+}
+
+func TestCreateAPIKey_ExpiryInPast(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	service := NewAPIKeyService(mockRepo)
+
+	past := time.Now().Add(-time.Hour)
+	result, err := service.Create(ctx, domain.Actor{UserID: 1, OrgID: 1, Role: domain.RoleUser}, domain.CreateAPIKeyRequest{Name: "worker", ExpiresAt: &past})
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "expiração")
+
+	mockRepo.AssertNotCalled(t, "Create")
+}
+
+func TestAuthenticateAPIKey_Valid(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	service := NewAPIKeyService(mockRepo)
+
+	rawKey := "evk_0123456789abcdef"
+	key := &domain.APIKey{ID: 3, UserID: 1, Scopes: []string{domain.ScopeEventsWrite}}
+
+	mockRepo.On("GetByHash", hashAPIKey(rawKey)).Return(key, nil)
+	mockRepo.On("TouchLastUsed", 3).Return(nil)
+
+	result, err := service.Authenticate(ctx, rawKey)
+
+	assert.NoError(t, err)
+	assert.Equal(t, key, result)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuthenticateAPIKey_Revoked(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	service := NewAPIKeyService(mockRepo)
+
+	rawKey := "evk_0123456789abcdef"
+	revokedAt := time.Now().Add(-time.Minute)
+	mockRepo.On("GetByHash", hashAPIKey(rawKey)).Return(&domain.APIKey{ID: 3, RevokedAt: &revokedAt}, nil)
+
+	result, err := service.Authenticate(ctx, rawKey)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.IsType(t, &AuthenticationError{}, err)
+
+	mockRepo.AssertNotCalled(t, "TouchLastUsed", 3)
+}
+
+func TestAuthenticateAPIKey_Expired(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	service := NewAPIKeyService(mockRepo)
+
+	rawKey := "evk_0123456789abcdef"
+	expiresAt := time.Now().Add(-time.Minute)
+	mockRepo.On("GetByHash", hashAPIKey(rawKey)).Return(&domain.APIKey{ID: 3, ExpiresAt: &expiresAt}, nil)
+
+	result, err := service.Authenticate(ctx, rawKey)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "expirada")
+}
+
+func TestAuthenticateAPIKey_Unknown(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	service := NewAPIKeyService(mockRepo)
+
+	mockRepo.On("GetByHash", mock.AnythingOfType("string")).Return(nil, &repository.APIKeyNotFoundError{})
+
+	result, err := service.Authenticate(ctx, "evk_unknown")
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.IsType(t, &AuthenticationError{}, err)
+}
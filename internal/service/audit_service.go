@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/repository"
+	"github.com/nathaliaoliveira/goapp/internal/tracing"
+)
+
+const (
+	defaultAuditPageSize = 50
+	maxAuditPageSize     = 500
+	// maxAuditExportRows limita o tamanho de uma exportação; filtre por período para obter o resto.
+	maxAuditExportRows = 100000
+)
+
+const (
+	AuditExportCSV  = "csv"
+	AuditExportJSON = "json"
+)
+
+const auditRecordTimeout = 5 * time.Second
+
+type auditService struct {
+	auditRepo repository.AuditRepository
+}
+
+func NewAuditService(auditRepo repository.AuditRepository) AuditService {
+	return &auditService{auditRepo: auditRepo}
+}
+
+// Record nunca interrompe a requisição auditada: falhas ao gravar só são logadas.
+// A gravação ignora o cancelamento da requisição (cliente desconectado ou
+// timeout da rota) para que ações já executadas não fiquem sem registro.
+func (s *auditService) Record(ctx context.Context, entry domain.AuditEntry) {
+	ctx, span := tracing.Start(ctx, "AuditService.Record")
+	defer span.End()
+
+	entry = sanitizeAuditEntry(entry)
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), auditRecordTimeout)
+	defer cancel()
+
+	if err := s.auditRepo.Append(ctx, &entry); err != nil {
+		slog.ErrorContext(ctx, "Erro ao gravar auditoria", "action", entry.Action, "error", err)
+	}
+}
+
+// Tamanhos das colunas de audit_log, em caracteres.
+const (
+	auditEmailSize     = 255
+	auditActionSize    = 50
+	auditTargetIDSize  = 100
+	auditIPSize        = 45
+	auditUserAgentSize = 512
+	auditOutcomeSize   = 20
+)
+
+// sanitizeAuditEntry ajusta os campos vindos do cliente (email, User-Agent,
+// IDs da URL) às colunas, para que um valor malformado não impeça o registro:
+// o Postgres recusa UTF-8 inválido, bytes nulos e textos maiores que a coluna.
+func sanitizeAuditEntry(entry domain.AuditEntry) domain.AuditEntry {
+	entry.ActorEmail = auditText(entry.ActorEmail, auditEmailSize)
+	entry.Action = auditText(entry.Action, auditActionSize)
+	entry.TargetType = auditText(entry.TargetType, auditActionSize)
+	entry.TargetID = auditText(entry.TargetID, auditTargetIDSize)
+	entry.IP = auditText(entry.IP, auditIPSize)
+	entry.UserAgent = auditText(entry.UserAgent, auditUserAgentSize)
+	entry.Outcome = auditText(entry.Outcome, auditOutcomeSize)
+	return entry
+}
+
+func auditText(value string, size int) string {
+	value = strings.ReplaceAll(strings.ToValidUTF8(value, "\uFFFD"), "\x00", "")
+	if utf8.RuneCountInString(value) <= size {
+		return value
+	}
+	return string([]rune(value)[:size])
+}
+
+func (s *auditService) List(ctx context.Context, actor domain.Actor, filter domain.AuditFilter) (*domain.AuditListResponse, error) {
+	ctx, span := tracing.Start(ctx, "AuditService.List")
+	defer span.End()
+
+	if !actor.IsAdmin() {
+		return nil, &ForbiddenError{Key: "audit.list_forbidden"}
+	}
+	if err := validateAuditFilter(filter); err != nil {
+		return nil, err
+	}
+
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 {
+		filter.PageSize = defaultAuditPageSize
+	}
+	if filter.PageSize > maxAuditPageSize {
+		filter.PageSize = maxAuditPageSize
+	}
+
+	entries, total, err := s.auditRepo.List(ctx, actor.OrgID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.AuditListResponse{
+		Entries:    entries,
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		Total:      total,
+		TotalPages: (total + filter.PageSize - 1) / filter.PageSize,
+	}, nil
+}
+
+// Export escreve todas as entradas do filtro em CSV ou JSON, paginando por id
+// para não repetir nem pular entradas gravadas durante a exportação.
+func (s *auditService) Export(ctx context.Context, actor domain.Actor, filter domain.AuditFilter, format string, w io.Writer) error {
+	ctx, span := tracing.Start(ctx, "AuditService.Export")
+	defer span.End()
+
+	if !actor.IsAdmin() {
+		return &ForbiddenError{Key: "audit.export_forbidden"}
+	}
+	if format != AuditExportCSV && format != AuditExportJSON {
+		return &ValidationError{Key: "audit.invalid_format"}
+	}
+	if err := validateAuditFilter(filter); err != nil {
+		return err
+	}
+
+	writer := newAuditWriter(format, w)
+	var lastID int64
+	for written := 0; written < maxAuditExportRows; {
+		entries, err := s.auditRepo.ListBefore(ctx, actor.OrgID, filter, lastID, maxAuditPageSize)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if err := writer.write(entry); err != nil {
+				return &InternalError{Key: "audit.export_failed", Cause: err}
+			}
+		}
+		written += len(entries)
+		if len(entries) < maxAuditPageSize {
+			break
+		}
+		lastID = entries[len(entries)-1].ID
+	}
+
+	if err := writer.close(); err != nil {
+		return &InternalError{Key: "audit.export_failed", Cause: err}
+	}
+	return nil
+}
+
+func validateAuditFilter(filter domain.AuditFilter) error {
+	switch filter.Outcome {
+	case "", domain.AuditOutcomeSuccess, domain.AuditOutcomeDenied, domain.AuditOutcomeFailure:
+	default:
+		return &ValidationError{Key: "audit.invalid_outcome", Args: []interface{}{filter.Outcome}}
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return &ValidationError{Key: "audit.invalid_period"}
+	}
+	return nil
+}
+
+// auditWriter mantém o formato de saída independente da paginação.
+type auditWriter struct {
+	csv   *csv.Writer
+	json  io.Writer
+	first bool
+}
+
+var auditCSVHeader = []string{"id", "created_at", "actor_id", "actor_email", "action", "target_type", "target_id", "ip", "user_agent", "outcome", "status_code"}
+
+func newAuditWriter(format string, w io.Writer) *auditWriter {
+	if format == AuditExportCSV {
+		return &auditWriter{csv: csv.NewWriter(w), first: true}
+	}
+	return &auditWriter{json: w, first: true}
+}
+
+func (a *auditWriter) write(e domain.AuditEntry) error {
+	if a.csv != nil {
+		if a.first {
+			a.first = false
+			if err := a.csv.Write(auditCSVHeader); err != nil {
+				return err
+			}
+		}
+		return a.csv.Write([]string{
+			strconv.FormatInt(e.ID, 10),
+			e.CreatedAt.UTC().Format(time.RFC3339),
+			strconv.Itoa(e.ActorID),
+			csvCell(e.ActorEmail),
+			e.Action,
+			csvCell(e.TargetType),
+			csvCell(e.TargetID),
+			csvCell(e.IP),
+			csvCell(e.UserAgent),
+			e.Outcome,
+			strconv.Itoa(e.StatusCode),
+		})
+	}
+
+	prefix := ","
+	if a.first {
+		a.first = false
+		prefix = "["
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(a.json, prefix+string(data)+"\n")
+	return err
+}
+
+// csvCell neutraliza valores vindos do cliente que uma planilha
+// interpretaria como fórmula ao abrir o arquivo exportado.
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func (a *auditWriter) close() error {
+	if a.csv != nil {
+		if a.first {
+			if err := a.csv.Write(auditCSVHeader); err != nil {
+				return err
+			}
+		}
+		a.csv.Flush()
+		return a.csv.Error()
+	}
+	if a.first {
+		_, err := io.WriteString(a.json, "[]\n")
+		return err
+	}
+	_, err := io.WriteString(a.json, "]\n")
+	return err
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) Append(ctx context.Context, entry *domain.AuditEntry) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
+func (m *MockAuditRepository) List(ctx context.Context, orgID int, filter domain.AuditFilter) ([]domain.AuditEntry, int, error) {
+	args := m.Called(orgID, filter)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]domain.AuditEntry), args.Int(1), args.Error(2)
+}
+
+func (m *MockAuditRepository) ListBefore(ctx context.Context, orgID int, filter domain.AuditFilter, beforeID int64, limit int) ([]domain.AuditEntry, error) {
+	args := m.Called(orgID, filter, beforeID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.AuditEntry), args.Error(1)
+}
+
+var auditAdmin = domain.Actor{UserID: 1, OrgID: 3, Role: domain.RoleAdmin}
+
+func TestAuditRecord_IgnoresRepositoryErrors(t *testing.T) {
+	mockRepo := new(MockAuditRepository)
+	service := NewAuditService(mockRepo)
+
+	mockRepo.On("Append", mock.AnythingOfType("*domain.AuditEntry")).Return(assert.AnError)
+
+	assert.NotPanics(t, func() {
+		service.Record(ctx, domain.AuditEntry{Action: domain.AuditLogin, UserAgent: strings.Repeat("a", 600)})
+	})
+
+	stored := mockRepo.Calls[0].Arguments.Get(0).(*domain.AuditEntry)
+	assert.Len(t, stored.UserAgent, 512)
+}
+
+func TestAuditRecord_SanitizesClientSuppliedFields(t *testing.T) {
+	mockRepo := new(MockAuditRepository)
+	service := NewAuditService(mockRepo)
+
+	mockRepo.On("Append", mock.AnythingOfType("*domain.AuditEntry")).Return(nil)
+
+	service.Record(ctx, domain.AuditEntry{
+		Action:     domain.AuditLogin,
+		ActorEmail: strings.Repeat("é", 300) + "@exemplo.com",
+		TargetID:   strings.Repeat("9", 150),
+		UserAgent:  "curl/8.0 \xff\xfe\x00" + strings.Repeat("ç", 600),
+	})
+
+	stored := mockRepo.Calls[0].Arguments.Get(0).(*domain.AuditEntry)
+	assert.Equal(t, strings.Repeat("é", 255), stored.ActorEmail)
+	assert.Len(t, stored.TargetID, 100)
+	assert.True(t, utf8.ValidString(stored.UserAgent))
+	assert.NotContains(t, stored.UserAgent, "\x00")
+	assert.Equal(t, 512, utf8.RuneCountInString(stored.UserAgent))
+	assert.True(t, strings.HasPrefix(stored.UserAgent, "curl/8.0 \uFFFD"))
+}
+
+// ctxAuditRepository registra o estado do contexto recebido pelo Append.
+type ctxAuditRepository struct {
+	MockAuditRepository
+	ctxErr error
+}
+
+func (r *ctxAuditRepository) Append(ctx context.Context, entry *domain.AuditEntry) error {
+	r.ctxErr = ctx.Err()
+	return nil
+}
+
+func TestAuditRecord_SurvivesCanceledRequest(t *testing.T) {
+	repo := &ctxAuditRepository{}
+	service := NewAuditService(repo)
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	service.Record(canceled, domain.AuditEntry{Action: domain.AuditUserDelete})
+	assert.NoError(t, repo.ctxErr)
+}
+
+func TestAuditList_AdminOnlyAndScopedToOrganization(t *testing.T) {
+	mockRepo := new(MockAuditRepository)
+	service := NewAuditService(mockRepo)
+
+	_, err := service.List(ctx, domain.Actor{UserID: 2, OrgID: 3, Role: domain.RoleUser}, domain.AuditFilter{})
+	assert.IsType(t, &ForbiddenError{}, err)
+
+	expected := domain.AuditFilter{Action: domain.AuditUserCreate, Page: 1, PageSize: 500}
+	mockRepo.On("List", 3, expected).Return([]domain.AuditEntry{{ID: 1, Action: domain.AuditUserCreate}}, 1, nil)
+
+	result, err := service.List(ctx, auditAdmin, domain.AuditFilter{Action: domain.AuditUserCreate, PageSize: 10000})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Total)
+	assert.Equal(t, 1, result.TotalPages)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuditList_RejectsInvalidFilters(t *testing.T) {
+	service := NewAuditService(new(MockAuditRepository))
+
+	_, err := service.List(ctx, auditAdmin, domain.AuditFilter{Outcome: "maybe"})
+	assert.IsType(t, &ValidationError{}, err)
+
+	from := time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC)
+	to := from.Add(-time.Hour)
+	_, err = service.List(ctx, auditAdmin, domain.AuditFilter{From: &from, To: &to})
+	assert.IsType(t, &ValidationError{}, err)
+}
+
+func TestAuditExport_CSV(t *testing.T) {
+	mockRepo := new(MockAuditRepository)
+	service := NewAuditService(mockRepo)
+
+	createdAt := time.Date(2025, 8, 20, 10, 30, 0, 0, time.UTC)
+	mockRepo.On("ListBefore", 3, domain.AuditFilter{}, int64(0), 500).Return([]domain.AuditEntry{
+		{ID: 7, ActorID: 1, ActorEmail: "admin@test.com", Action: domain.AuditUserDelete, TargetType: "user", TargetID: "4",
+			IP: "203.0.113.7", UserAgent: "curl/8", Outcome: domain.AuditOutcomeSuccess, StatusCode: 200, CreatedAt: createdAt},
+	}, nil)
+
+	var out bytes.Buffer
+	err := service.Export(ctx, auditAdmin, domain.AuditFilter{}, AuditExportCSV, &out)
+
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.Equal(t, "id,created_at,actor_id,actor_email,action,target_type,target_id,ip,user_agent,outcome,status_code", lines[0])
+	assert.Equal(t, "7,2025-08-20T10:30:00Z,1,admin@test.com,user.delete,user,4,203.0.113.7,curl/8,success,200", lines[1])
+}
+
+func TestAuditExport_CSVEscapesFormulas(t *testing.T) {
+	mockRepo := new(MockAuditRepository)
+	service := NewAuditService(mockRepo)
+
+	mockRepo.On("ListBefore", 3, domain.AuditFilter{}, int64(0), 500).Return([]domain.AuditEntry{
+		{ID: 8, ActorEmail: "=cmd|'/c calc'!A1", Action: domain.AuditLogin, TargetID: "+1", UserAgent: "@SUM(A1)",
+			Outcome: domain.AuditOutcomeDenied, StatusCode: 401},
+	}, nil)
+
+	var out bytes.Buffer
+	assert.NoError(t, service.Export(ctx, auditAdmin, domain.AuditFilter{}, AuditExportCSV, &out))
+
+	rows, err := csv.NewReader(&out).ReadAll()
+	assert.NoError(t, err)
+	assert.Equal(t, "'=cmd|'/c calc'!A1", rows[1][3])
+	assert.Equal(t, "'+1", rows[1][6])
+	assert.Equal(t, "'@SUM(A1)", rows[1][8])
+}
+
+func TestAuditExport_JSONPaginatesByID(t *testing.T) {
+	mockRepo := new(MockAuditRepository)
+	service := NewAuditService(mockRepo)
+
+	firstPage := make([]domain.AuditEntry, 500)
+	for i := range firstPage {
+		firstPage[i] = domain.AuditEntry{ID: int64(1000 - i), Action: domain.AuditLogin}
+	}
+	mockRepo.On("ListBefore", 3, domain.AuditFilter{}, int64(0), 500).Return(firstPage, nil)
+	mockRepo.On("ListBefore", 3, domain.AuditFilter{}, int64(501), 500).Return([]domain.AuditEntry{{ID: 500, Action: domain.AuditLogin}}, nil)
+
+	var out bytes.Buffer
+	err := service.Export(ctx, auditAdmin, domain.AuditFilter{}, AuditExportJSON, &out)
+
+	assert.NoError(t, err)
+	var entries []domain.AuditEntry
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &entries))
+	assert.Len(t, entries, 501)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuditExport_EmptyJSONAndInvalidFormat(t *testing.T) {
+	mockRepo := new(MockAuditRepository)
+	service := NewAuditService(mockRepo)
+
+	mockRepo.On("ListBefore", 3, domain.AuditFilter{}, int64(0), 500).Return([]domain.AuditEntry{}, nil)
+
+	var out bytes.Buffer
+	assert.NoError(t, service.Export(ctx, auditAdmin, domain.AuditFilter{}, AuditExportJSON, &out))
+	assert.Equal(t, "[]\n", out.String())
+
+	err := service.Export(ctx, auditAdmin, domain.AuditFilter{}, "xml", &out)
+	assert.IsType(t, &ValidationError{}, err)
+}
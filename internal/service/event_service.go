@@ -1,25 +1,59 @@
 package service
 
 import (
-	"strings"
+	"context"
+	"errors"
+	"unicode/utf8"
 
 	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/metrics"
 	"github.com/nathaliaoliveira/goapp/internal/repository"
+	"github.com/nathaliaoliveira/goapp/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// MaxEventsPerBatch é o número máximo de eventos aceitos em uma chamada de
+// ProcessEvents.
+const MaxEventsPerBatch = 1000
+
+// Tamanho máximo, em caracteres, dos campos opcionais gravados com o evento;
+// são os tamanhos das colunas de email_events.
+const (
+	maxCampaignIDLength = 100
+	maxSubjectLength    = 500
+	maxIPAddressLength  = 45
 )
 
 type eventService struct {
     eventRepo repository.EventRepository
+    siteRepo  repository.SiteRepository
 }
 
-func NewEventService(eventRepo repository.EventRepository) EventService {
+func NewEventService(eventRepo repository.EventRepository, siteRepo repository.SiteRepository) EventService {
     return &eventService{
         eventRepo: eventRepo,
+        siteRepo:  siteRepo,
     }
 }
 
-func (s *eventService) ProcessEvents(events []domain.EmailEvent) (*domain.EventsResponse, error) {
+// ProcessEvents só aceita eventos de sites cadastrados na organização; se algum
+// site for de outra organização (ou de nenhuma), o lote inteiro é recusado.
+func (s *eventService) ProcessEvents(ctx context.Context, orgID int, events []domain.EmailEvent) (*domain.EventsResponse, error) {
+	ctx, span := tracing.Start(ctx, "EventService.ProcessEvents", attribute.Int("events.count", len(events)))
+	defer span.End()
+
 	if len(events) == 0 {
-		return nil, &ValidationError{Message: "Lista de eventos não pode estar vazia"}
+		return nil, &ValidationError{Key: "event.empty_batch"}
+	}
+	if len(events) > MaxEventsPerBatch {
+		return nil, &ValidationError{Key: "event.too_many", Args: []interface{}{MaxEventsPerBatch}}
+	}
+	
+	if err := s.checkSites(ctx, orgID, events); err != nil {
+		for _, event := range events {
+			metrics.ObserveEvent(event.Type, event.Site, metrics.EventRejected)
+		}
+		return nil, err
 	}
 	
 	processedCount := 0
@@ -28,8 +62,9 @@ func (s *eventService) ProcessEvents(events []domain.EmailEvent) (*domain.Events
 	var processedEvents []domain.ProcessedEvent
 	
 	for _, event := range events {
-		if event.Type == "" || event.Email == "" || event.Site == "" || event.Timestamp == "" {
+		if !validEvent(event) {
 			errorsCount++
+			metrics.ObserveEvent(event.Type, event.Site, metrics.EventInvalid)
 			processedEvents = append(processedEvents, domain.ProcessedEvent{
 				Type:   event.Type,
 				Email:  event.Email,
@@ -39,11 +74,12 @@ func (s *eventService) ProcessEvents(events []domain.EmailEvent) (*domain.Events
 			continue
 		}
 		
-		eventID, err := s.eventRepo.Create(&event)
+		eventID, err := s.eventRepo.Create(ctx, orgID, &event)
 		if err != nil {
-			// Verificar se é erro de duplicação
-			if strings.Contains(err.Error(), "evento duplicado") {
+			var duplicate *repository.DuplicateEventError
+			if errors.As(err, &duplicate) {
 				duplicatesCount++
+				metrics.ObserveEvent(event.Type, event.Site, metrics.EventDuplicate)
 				processedEvents = append(processedEvents, domain.ProcessedEvent{
 					Type:   event.Type,
 					Email:  event.Email,
@@ -54,6 +90,7 @@ func (s *eventService) ProcessEvents(events []domain.EmailEvent) (*domain.Events
 			}
 			
 			errorsCount++
+			metrics.ObserveEvent(event.Type, event.Site, metrics.EventError)
 			processedEvents = append(processedEvents, domain.ProcessedEvent{
 				Type:   event.Type,
 				Email:  event.Email,
@@ -64,6 +101,7 @@ func (s *eventService) ProcessEvents(events []domain.EmailEvent) (*domain.Events
 		}
 		
 		processedCount++
+		metrics.ObserveEvent(event.Type, event.Site, metrics.EventProcessed)
 		processedEvents = append(processedEvents, domain.ProcessedEvent{
 			ID:     eventID,
 			Type:   event.Type,
@@ -73,6 +111,11 @@ func (s *eventService) ProcessEvents(events []domain.EmailEvent) (*domain.Events
 		})
 	}
 	
+	span.SetAttributes(
+		attribute.Int("events.processed", processedCount),
+		attribute.Int("events.duplicates", duplicatesCount),
+		attribute.Int("events.errors", errorsCount))
+	
 	return &domain.EventsResponse{
 		Processed:  processedCount,
 		Duplicates: duplicatesCount,
@@ -81,8 +124,41 @@ func (s *eventService) ProcessEvents(events []domain.EmailEvent) (*domain.Events
 	}, nil
 }
 
-func (s *eventService) GetDailyStats(startDate, endDate, site string) (*domain.StatsResponse, error) {
-	stats, err := s.eventRepo.GetDailyStats(startDate, endDate, site)
+// validEvent exige os campos obrigatórios e recusa campos opcionais maiores
+// que as colunas, em vez de cortá-los ao gravar.
+func validEvent(event domain.EmailEvent) bool {
+	if event.Type == "" || event.Email == "" || event.Site == "" || event.Timestamp == "" {
+		return false
+	}
+	return utf8.RuneCountInString(event.CampaignID) <= maxCampaignIDLength &&
+		utf8.RuneCountInString(event.Subject) <= maxSubjectLength &&
+		utf8.RuneCountInString(event.IPAddress) <= maxIPAddressLength
+}
+
+func (s *eventService) checkSites(ctx context.Context, orgID int, events []domain.EmailEvent) error {
+	domains, err := s.siteRepo.Domains(ctx, orgID)
+	if err != nil {
+		return &InternalError{Key: "event.sites_lookup_failed", Cause: err}
+	}
+	
+	owned := make(map[string]bool, len(domains))
+	for _, d := range domains {
+		owned[d] = true
+	}
+	
+	for _, event := range events {
+		if event.Site != "" && !owned[event.Site] {
+			return &ForbiddenError{Key: "event.site_not_registered", Args: []interface{}{event.Site}}
+		}
+	}
+	return nil
+}
+
+func (s *eventService) GetDailyStats(ctx context.Context, orgID int, startDate, endDate, site string) (*domain.StatsResponse, error) {
+	ctx, span := tracing.Start(ctx, "EventService.GetDailyStats")
+	defer span.End()
+
+	stats, err := s.eventRepo.GetDailyStats(ctx, orgID, startDate, endDate, site)
 	if err != nil {
 		return nil, err
 	}
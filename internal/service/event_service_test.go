@@ -1,35 +1,74 @@
 package service
 
 import (
+	"context"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/repository"
+	"github.com/nathaliaoliveira/goapp/internal/tracing/tracingtest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type MockEventRepository struct {
 	mock.Mock
 }
 
-func (m *MockEventRepository) Create(event *domain.EmailEvent) (string, error) {
-	args := m.Called(event)
+func (m *MockEventRepository) Create(ctx context.Context, orgID int, event *domain.EmailEvent) (string, error) {
+	args := m.Called(orgID, event)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockEventRepository) GetDailyStats(startDate, endDate, site string) ([]domain.DailyStats, error) {
-	args := m.Called(startDate, endDate, site)
+func (m *MockEventRepository) GetDailyStats(ctx context.Context, orgID int, startDate, endDate, site string) ([]domain.DailyStats, error) {
+	args := m.Called(orgID, startDate, endDate, site)
 	return args.Get(0).([]domain.DailyStats), args.Error(1)
 }
 
-func (m *MockEventRepository) GetTotalCounts() (int, int, error) {
+func (m *MockEventRepository) EstimateTotalCounts(ctx context.Context) (int, int, error) {
 	args := m.Called()
 	return args.Int(0), args.Int(1), args.Error(2)
 }
 
+type MockSiteRepository struct {
+	mock.Mock
+}
+
+func (m *MockSiteRepository) Create(ctx context.Context, orgID int, domainName string) (*domain.Site, error) {
+	args := m.Called(orgID, domainName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Site), args.Error(1)
+}
+
+func (m *MockSiteRepository) List(ctx context.Context, orgID int) ([]domain.Site, error) {
+	args := m.Called(orgID)
+	return args.Get(0).([]domain.Site), args.Error(1)
+}
+
+func (m *MockSiteRepository) Delete(ctx context.Context, orgID, id int) error {
+	args := m.Called(orgID, id)
+	return args.Error(0)
+}
+
+func (m *MockSiteRepository) Domains(ctx context.Context, orgID int) ([]string, error) {
+	args := m.Called(orgID)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func sitesOf(orgID int, domains ...string) *MockSiteRepository {
+	sites := new(MockSiteRepository)
+	sites.On("Domains", orgID).Return(domains, nil)
+	return sites
+}
+
 func TestProcessEvents_ValidEvents(t *testing.T) {
 	mockRepo := new(MockEventRepository)
-	service := NewEventService(mockRepo)
+	service := NewEventService(mockRepo, sitesOf(1, "site-a.com"))
 
 	events := []domain.EmailEvent{
 		{
@@ -46,10 +85,10 @@ func TestProcessEvents_ValidEvents(t *testing.T) {
 		},
 	}
 
-	mockRepo.On("Create", &events[0]).Return("uuid-1", nil)
-	mockRepo.On("Create", &events[1]).Return("uuid-2", nil)
+	mockRepo.On("Create", 1, &events[0]).Return("uuid-1", nil)
+	mockRepo.On("Create", 1, &events[1]).Return("uuid-2", nil)
 
-	result, err := service.ProcessEvents(events)
+	result, err := service.ProcessEvents(ctx, 1, events)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -65,7 +104,7 @@ func TestProcessEvents_ValidEvents(t *testing.T) {
 
 func TestProcessEvents_DuplicateEvent(t *testing.T) {
 	mockRepo := new(MockEventRepository)
-	service := NewEventService(mockRepo)
+	service := NewEventService(mockRepo, sitesOf(1, "site-a.com"))
 
 	events := []domain.EmailEvent{
 		{
@@ -76,9 +115,9 @@ func TestProcessEvents_DuplicateEvent(t *testing.T) {
 		},
 	}
 
-	mockRepo.On("Create", &events[0]).Return("", assert.AnError)
+	mockRepo.On("Create", 1, &events[0]).Return("", assert.AnError)
 
-	result, err := service.ProcessEvents(events)
+	result, err := service.ProcessEvents(ctx, 1, events)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -91,10 +130,54 @@ func TestProcessEvents_DuplicateEvent(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestProcessEvents_CountsTypedDuplicate(t *testing.T) {
+	mockRepo := new(MockEventRepository)
+	service := NewEventService(mockRepo, sitesOf(1, "site-a.com"))
+
+	events := []domain.EmailEvent{
+		{
+			Type:      "sent",
+			Email:     "user@example.com",
+			Site:      "site-a.com",
+			Timestamp: "2025-08-20T10:30:00Z",
+		},
+	}
+
+	mockRepo.On("Create", 1, &events[0]).Return("", fmt.Errorf("lote 1: %w", &repository.DuplicateEventError{}))
+
+	result, err := service.ProcessEvents(ctx, 1, events)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Duplicates)
+	assert.Equal(t, 0, result.Errors)
+	assert.Equal(t, "duplicate", result.Events[0].Status)
+}
+
+func TestProcessEvents_RecordsSpan(t *testing.T) {
+	exporter := tracingtest.Install(t)
+	mockRepo := new(MockEventRepository)
+	service := NewEventService(mockRepo, sitesOf(1, "site-a.com"))
+
+	events := []domain.EmailEvent{
+		{Type: "sent", Email: "user@example.com", Site: "site-a.com", Timestamp: "2025-08-20T10:30:00Z"},
+		{Type: "open", Email: "user@example.com", Site: "site-a.com"},
+	}
+	mockRepo.On("Create", 1, &events[0]).Return("uuid-1", nil)
+
+	_, err := service.ProcessEvents(ctx, 1, events)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"EventService.ProcessEvents"}, tracingtest.Names(exporter))
+	attrs := exporter.GetSpans()[0].Attributes
+	assert.Contains(t, attrs, attribute.Int("events.count", 2))
+	assert.Contains(t, attrs, attribute.Int("events.processed", 1))
+	assert.Contains(t, attrs, attribute.Int("events.errors", 1))
+}
+
 func TestProcessEvents_InvalidEvent(t *testing.T) {
 	// Arrange
 	mockRepo := new(MockEventRepository)
-	service := NewEventService(mockRepo)
+	service := NewEventService(mockRepo, sitesOf(1, "site-a.com"))
 
 	events := []domain.EmailEvent{
 		{
@@ -106,7 +189,7 @@ func TestProcessEvents_InvalidEvent(t *testing.T) {
 	}
 
 	// Act
-	result, err := service.ProcessEvents(events)
+	result, err := service.ProcessEvents(ctx, 1, events)
 
 	// Assert
 	assert.NoError(t, err)
@@ -121,15 +204,59 @@ func TestProcessEvents_InvalidEvent(t *testing.T) {
 	mockRepo.AssertNotCalled(t, "Create")
 }
 
+func TestProcessEvents_StoresOptionalFields(t *testing.T) {
+	mockRepo := new(MockEventRepository)
+	service := NewEventService(mockRepo, sitesOf(1, "site-a.com"))
+
+	event := domain.EmailEvent{
+		Type:       "open",
+		Email:      "user@example.com",
+		Site:       "site-a.com",
+		Timestamp:  "2025-08-20T10:30:00Z",
+		CampaignID: "black-friday",
+		Subject:    "Ofertas da semana",
+		IPAddress:  "2001:db8::1",
+		UserAgent:  "Mozilla/5.0",
+	}
+	mockRepo.On("Create", 1, &event).Return("event-1", nil)
+
+	result, err := service.ProcessEvents(ctx, 1, []domain.EmailEvent{event})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Processed)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestProcessEvents_RejectsOversizedOptionalFields(t *testing.T) {
+	mockRepo := new(MockEventRepository)
+	service := NewEventService(mockRepo, sitesOf(1, "site-a.com"))
+
+	valid := domain.EmailEvent{Type: "open", Email: "user@example.com", Site: "site-a.com", Timestamp: "2025-08-20T10:30:00Z"}
+	campaign, subject, ip := valid, valid, valid
+	campaign.CampaignID = strings.Repeat("c", maxCampaignIDLength+1)
+	subject.Subject = strings.Repeat("á", maxSubjectLength+1)
+	ip.IPAddress = strings.Repeat("1", maxIPAddressLength+1)
+	limit := valid
+	limit.Subject = strings.Repeat("á", maxSubjectLength)
+	mockRepo.On("Create", 1, &limit).Return("event-1", nil)
+
+	result, err := service.ProcessEvents(ctx, 1, []domain.EmailEvent{campaign, subject, ip, limit})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Processed, "o limite conta caracteres, não bytes")
+	assert.Equal(t, 3, result.Errors)
+	mockRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
 func TestProcessEvents_EmptyEventsList(t *testing.T) {
 	// Arrange
 	mockRepo := new(MockEventRepository)
-	service := NewEventService(mockRepo)
+	service := NewEventService(mockRepo, sitesOf(1, "site-a.com"))
 
 	events := []domain.EmailEvent{}
 
 	// Act
-	result, err := service.ProcessEvents(events)
+	result, err := service.ProcessEvents(ctx, 1, events)
 
 	// Assert
 	assert.Error(t, err)
@@ -139,10 +266,25 @@ func TestProcessEvents_EmptyEventsList(t *testing.T) {
 	mockRepo.AssertNotCalled(t, "Create")
 }
 
+func TestProcessEvents_RejectsOversizedBatch(t *testing.T) {
+	mockRepo := new(MockEventRepository)
+	service := NewEventService(mockRepo, sitesOf(1, "site-a.com"))
+
+	events := make([]domain.EmailEvent, MaxEventsPerBatch+1)
+	result, err := service.ProcessEvents(ctx, 1, events)
+
+	assert.Nil(t, result)
+	var validation *ValidationError
+	if assert.ErrorAs(t, err, &validation) {
+		assert.Equal(t, "event.too_many", validation.Key)
+	}
+	mockRepo.AssertNotCalled(t, "Create")
+}
+
 func TestProcessEvents_MixedValidAndInvalid(t *testing.T) {
 	// Arrange
 	mockRepo := new(MockEventRepository)
-	service := NewEventService(mockRepo)
+	service := NewEventService(mockRepo, sitesOf(1, "site-a.com"))
 
 	events := []domain.EmailEvent{
 		{
@@ -166,12 +308,12 @@ func TestProcessEvents_MixedValidAndInvalid(t *testing.T) {
 	}
 
 	// Mock: primeiro evento processado com sucesso
-	mockRepo.On("Create", &events[0]).Return("uuid-1", nil)
+	mockRepo.On("Create", 1, &events[0]).Return("uuid-1", nil)
 	// Mock: terceiro evento processado com sucesso
-	mockRepo.On("Create", &events[2]).Return("uuid-3", nil)
+	mockRepo.On("Create", 1, &events[2]).Return("uuid-3", nil)
 
 	// Act
-	result, err := service.ProcessEvents(events)
+	result, err := service.ProcessEvents(ctx, 1, events)
 
 	// Assert
 	assert.NoError(t, err)
@@ -187,4 +329,20 @@ func TestProcessEvents_MixedValidAndInvalid(t *testing.T) {
 	assert.Equal(t, "processed", result.Events[2].Status)
 
 	mockRepo.AssertExpectations(t)
-} 
\ No newline at end of file
+} 
+func TestProcessEvents_RejectsSiteFromAnotherOrganization(t *testing.T) {
+	mockRepo := new(MockEventRepository)
+	service := NewEventService(mockRepo, sitesOf(2, "site-b.com"))
+
+	events := []domain.EmailEvent{
+		{Type: "sent", Email: "user@example.com", Site: "site-b.com", Timestamp: "2025-08-20T10:30:00Z"},
+		{Type: "sent", Email: "user@example.com", Site: "site-a.com", Timestamp: "2025-08-20T10:31:00Z"},
+	}
+
+	result, err := service.ProcessEvents(ctx, 2, events)
+
+	assert.Nil(t, result)
+	assert.IsType(t, &ForbiddenError{}, err)
+	assert.Contains(t, err.Error(), "site-a.com")
+	mockRepo.AssertNotCalled(t, "Create")
+}
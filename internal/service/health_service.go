@@ -1,62 +1,205 @@
 package service
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/nathaliaoliveira/goapp/internal/domain"
 	"github.com/nathaliaoliveira/goapp/internal/repository"
+	"github.com/nathaliaoliveira/goapp/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+// Erros informados em CheckResult.Error. O /readyz é público: o erro da
+// dependência, que pode trazer endereços e nomes internos, fica só no log.
+const (
+	HealthCheckFailed  = "check failed"
+	HealthCheckTimeout = "timeout"
+)
+
+// DefaultHealthCheckTimeout limita cada check do /readyz; um check lento
+// conta como falha em vez de segurar a probe do orquestrador.
+const DefaultHealthCheckTimeout = 2 * time.Second
+
 type DBInterface interface {
-	Ping() error
+	PingContext(ctx context.Context) error
+	Stats() sql.DBStats
+}
+
+// HealthChecker é uma dependência verificada pelo /readyz (banco, migrações).
+// Check deve respeitar o cancelamento de ctx.
+type HealthChecker interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+type healthCheck struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+// NewHealthCheck adapta uma função a HealthChecker.
+func NewHealthCheck(name string, check func(ctx context.Context) error) HealthChecker {
+	return &healthCheck{name: name, check: check}
+}
+
+func (c *healthCheck) Name() string                    { return c.name }
+func (c *healthCheck) Check(ctx context.Context) error { return c.check(ctx) }
+
+// DatabaseCheck verifica se o banco responde a um ping.
+func DatabaseCheck(db DBInterface) HealthChecker {
+	return NewHealthCheck("database", db.PingContext)
 }
 
 type healthService struct {
-	eventRepo repository.EventRepository
-	db        DBInterface
-	startTime time.Time
+	eventRepo    repository.EventRepository
+	db           DBInterface
+	startTime    time.Time
+	checkTimeout time.Duration
+	checks       []HealthChecker
 }
 
-func NewHealthService(eventRepo repository.EventRepository, db DBInterface, startTime time.Time) HealthService {
+// NewHealthService recebe os checks usados pelo /readyz; checkTimeout <= 0
+// usa DefaultHealthCheckTimeout.
+func NewHealthService(eventRepo repository.EventRepository, db DBInterface, startTime time.Time, checkTimeout time.Duration, checks ...HealthChecker) HealthService {
+	if checkTimeout <= 0 {
+		checkTimeout = DefaultHealthCheckTimeout
+	}
 	return &healthService{
-		eventRepo: eventRepo,
-		db:        db,
-		startTime: startTime,
+		eventRepo:    eventRepo,
+		db:           db,
+		startTime:    startTime,
+		checkTimeout: checkTimeout,
+		checks:       checks,
 	}
 }
 
-func (s *healthService) GetHealth() (*domain.HealthResponse, error) {
+func (s *healthService) GetHealth(ctx context.Context) (*domain.HealthResponse, error) {
+	ctx, span := tracing.Start(ctx, "HealthService.GetHealth")
+	defer span.End()
+
 	var dbStatus string
 	var dbLatency time.Duration
 	
 	start := time.Now()
-	err := s.db.Ping()
+	err := s.db.PingContext(ctx)
 	dbLatency = time.Since(start)
 	
 	if err != nil {
-		dbStatus = "unhealthy"
+		dbStatus = domain.HealthStatusUnhealthy
 	} else {
-		dbStatus = "healthy"
+		dbStatus = domain.HealthStatusHealthy
 	}
 	
-	totalUsers, totalEvents, err := s.eventRepo.GetTotalCounts()
+	totalUsers, totalEvents, err := s.eventRepo.EstimateTotalCounts(ctx)
 	if err != nil {
 		totalUsers, totalEvents = 0, 0
 	}
 	
 	uptime := time.Since(s.startTime)
+	stats := s.db.Stats()
 	
 	return &domain.HealthResponse{
-		Status:    "healthy",
+		Status:    dbStatus,
 		Timestamp: time.Now().Format(time.RFC3339),
 		Database: domain.DatabaseHealth{
 			Status:    dbStatus,
 			LatencyMs: dbLatency.Milliseconds(),
+			Pool: domain.PoolHealth{
+				MaxOpen:           stats.MaxOpenConnections,
+				Open:              stats.OpenConnections,
+				InUse:             stats.InUse,
+				Idle:              stats.Idle,
+				WaitCount:         stats.WaitCount,
+				WaitDurationMs:    stats.WaitDuration.Milliseconds(),
+				MaxIdleClosed:     stats.MaxIdleClosed,
+				MaxIdleTimeClosed: stats.MaxIdleTimeClosed,
+				MaxLifetimeClosed: stats.MaxLifetimeClosed,
+			},
 		},
 		Statistics: domain.StatisticsHealth{
 			TotalUsers:  totalUsers,
 			TotalEvents: totalEvents,
+			Estimated:   true,
 		},
 		Uptime: uptime.String(),
 	}, nil
-} 
\ No newline at end of file
+}
+
+// Live não toca em dependências: um banco fora do ar não deve fazer o
+// orquestrador reiniciar a aplicação, só tirá-la do balanceamento (Ready).
+func (s *healthService) Live(ctx context.Context) *domain.LivenessResponse {
+	return &domain.LivenessResponse{
+		Status:    domain.HealthStatusHealthy,
+		Timestamp: time.Now().Format(time.RFC3339),
+		Uptime:    time.Since(s.startTime).String(),
+	}
+}
+
+// Ready executa os checks em paralelo, cada um com seu próprio timeout, e
+// devolve o resultado na ordem em que foram registrados.
+func (s *healthService) Ready(ctx context.Context) *domain.ReadinessResponse {
+	ctx, span := tracing.Start(ctx, "HealthService.Ready")
+	defer span.End()
+
+	results := make([]domain.CheckResult, len(s.checks))
+	var wg sync.WaitGroup
+	for i, check := range s.checks {
+		wg.Add(1)
+		go func(i int, check HealthChecker) {
+			defer wg.Done()
+			results[i] = s.runCheck(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	status := domain.HealthStatusHealthy
+	for _, result := range results {
+		if result.Status != domain.HealthStatusHealthy {
+			status = domain.HealthStatusUnhealthy
+		}
+	}
+	span.SetAttributes(attribute.String("health.status", status))
+
+	return &domain.ReadinessResponse{
+		Status:    status,
+		Timestamp: time.Now().Format(time.RFC3339),
+		Checks:    results,
+	}
+}
+
+// runCheck não espera além do timeout mesmo que o check ignore o contexto.
+func (s *healthService) runCheck(ctx context.Context, check HealthChecker) domain.CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, s.checkTimeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	start := time.Now()
+	go func() { done <- check.Check(ctx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := domain.CheckResult{
+		Name:      check.Name(),
+		Status:    domain.HealthStatusHealthy,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		result.Status = domain.HealthStatusUnhealthy
+		result.Error = HealthCheckFailed
+		if errors.Is(err, context.DeadlineExceeded) {
+			result.Error = HealthCheckTimeout
+		}
+		slog.WarnContext(ctx, "Check de prontidão falhou", "check", result.Name, "error", err, "latency_ms", result.LatencyMs)
+	}
+	return result
+}
@@ -1,12 +1,16 @@
 package service
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/nathaliaoliveira/goapp/internal/domain"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockEventRepository é um mock do repositório para testes
@@ -14,20 +18,20 @@ type MockEventRepositoryForHealth struct {
 	mock.Mock
 }
 
-func (m *MockEventRepositoryForHealth) Create(event *domain.EmailEvent) (string, error) {
-	args := m.Called(event)
+func (m *MockEventRepositoryForHealth) Create(ctx context.Context, orgID int, event *domain.EmailEvent) (string, error) {
+	args := m.Called(orgID, event)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockEventRepositoryForHealth) GetDailyStats(startDate, endDate, site string) ([]domain.DailyStats, error) {
-	args := m.Called(startDate, endDate, site)
+func (m *MockEventRepositoryForHealth) GetDailyStats(ctx context.Context, orgID int, startDate, endDate, site string) ([]domain.DailyStats, error) {
+	args := m.Called(orgID, startDate, endDate, site)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]domain.DailyStats), args.Error(1)
 }
 
-func (m *MockEventRepositoryForHealth) GetTotalCounts() (int, int, error) {
+func (m *MockEventRepositoryForHealth) EstimateTotalCounts(ctx context.Context) (int, int, error) {
 	args := m.Called()
 	return args.Int(0), args.Int(1), args.Error(2)
 }
@@ -35,22 +39,27 @@ func (m *MockEventRepositoryForHealth) GetTotalCounts() (int, int, error) {
 // MockDB é um mock simples do banco de dados
 type MockDB struct {
 	pingError error
+	stats     sql.DBStats
 }
 
-func (m *MockDB) Ping() error {
+func (m *MockDB) PingContext(ctx context.Context) error {
 	return m.pingError
 }
 
+func (m *MockDB) Stats() sql.DBStats {
+	return m.stats
+}
+
 func TestGetHealth_RepositoryError(t *testing.T) {
 	mockRepo := new(MockEventRepositoryForHealth)
 	mockDB := &MockDB{pingError: nil} // DB saudável
 	startTime := time.Now().Add(-time.Hour)
-	service := NewHealthService(mockRepo, mockDB, startTime)
+	service := NewHealthService(mockRepo, mockDB, startTime, 0)
 
 	// Mock: repositório falha
-	mockRepo.On("GetTotalCounts").Return(0, 0, assert.AnError)
+	mockRepo.On("EstimateTotalCounts").Return(0, 0, assert.AnError)
 
-	result, err := service.GetHealth()
+	result, err := service.GetHealth(ctx)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -67,16 +76,112 @@ func TestGetHealth_UptimeCalculation(t *testing.T) {
 	mockRepo := new(MockEventRepositoryForHealth)
 	mockDB := &MockDB{pingError: nil}
 	startTime := time.Now().Add(-2 * time.Hour) // 2 horas atrás
-	service := NewHealthService(mockRepo, mockDB, startTime)
+	service := NewHealthService(mockRepo, mockDB, startTime, 0)
 
 	// Mock: repositório retorna estatísticas
-	mockRepo.On("GetTotalCounts").Return(1, 10, nil)
+	mockRepo.On("EstimateTotalCounts").Return(1, 10, nil)
 
-	result, err := service.GetHealth()
+	result, err := service.GetHealth(ctx)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
 	assert.Contains(t, result.Uptime, "2h")
 
 	mockRepo.AssertExpectations(t)
-} 
\ No newline at end of file
+} 
+func TestGetHealth_ReportsPoolStats(t *testing.T) {
+	mockRepo := new(MockEventRepositoryForHealth)
+	mockDB := &MockDB{stats: sql.DBStats{
+		MaxOpenConnections: 25,
+		OpenConnections:    4,
+		InUse:              3,
+		Idle:               1,
+		WaitCount:          2,
+		WaitDuration:       1500 * time.Millisecond,
+	}}
+	service := NewHealthService(mockRepo, mockDB, time.Now(), 0)
+
+	mockRepo.On("EstimateTotalCounts").Return(1, 10, nil)
+
+	result, err := service.GetHealth(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 25, result.Database.Pool.MaxOpen)
+	assert.Equal(t, 3, result.Database.Pool.InUse)
+	assert.Equal(t, 1, result.Database.Pool.Idle)
+	assert.Equal(t, int64(2), result.Database.Pool.WaitCount)
+	assert.Equal(t, int64(1500), result.Database.Pool.WaitDurationMs)
+}
+
+func TestGetHealth_UnhealthyDatabase(t *testing.T) {
+	mockRepo := new(MockEventRepositoryForHealth)
+	mockDB := &MockDB{pingError: errors.New("conexão recusada")}
+	service := NewHealthService(mockRepo, mockDB, time.Now(), 0)
+
+	mockRepo.On("EstimateTotalCounts").Return(0, 0, assert.AnError)
+
+	result, err := service.GetHealth(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "unhealthy", result.Status)
+	assert.Equal(t, "unhealthy", result.Database.Status)
+}
+
+func TestLive_DoesNotTouchDependencies(t *testing.T) {
+	mockRepo := new(MockEventRepositoryForHealth)
+	mockDB := &MockDB{pingError: errors.New("conexão recusada")}
+	failing := NewHealthCheck("database", mockDB.PingContext)
+	service := NewHealthService(mockRepo, mockDB, time.Now(), 0, failing)
+
+	result := service.Live(ctx)
+
+	assert.Equal(t, "healthy", result.Status)
+	mockRepo.AssertNotCalled(t, "EstimateTotalCounts")
+}
+
+func TestReady_ReportsEachCheck(t *testing.T) {
+	mockDB := &MockDB{}
+	pending := NewHealthCheck("migrations", func(ctx context.Context) error {
+		return errors.New("1 migração pendente")
+	})
+	service := NewHealthService(new(MockEventRepositoryForHealth), mockDB, time.Now(), 0, DatabaseCheck(mockDB), pending)
+
+	result := service.Ready(ctx)
+
+	assert.Equal(t, "unhealthy", result.Status)
+	require.Len(t, result.Checks, 2)
+	assert.Equal(t, "database", result.Checks[0].Name)
+	assert.Equal(t, "healthy", result.Checks[0].Status)
+	assert.Empty(t, result.Checks[0].Error)
+	assert.Equal(t, "migrations", result.Checks[1].Name)
+	assert.Equal(t, "unhealthy", result.Checks[1].Status)
+	assert.Equal(t, HealthCheckFailed, result.Checks[1].Error, "o erro da dependência fica só no log")
+}
+
+func TestReady_TimesOutSlowCheck(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	// Ignora o contexto de propósito: o Ready não pode esperar por ele.
+	stuck := NewHealthCheck("queue", func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+	service := NewHealthService(new(MockEventRepositoryForHealth), &MockDB{}, time.Now(), 20*time.Millisecond, stuck)
+
+	start := time.Now()
+	result := service.Ready(ctx)
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, "unhealthy", result.Status)
+	assert.Equal(t, HealthCheckTimeout, result.Checks[0].Error)
+	assert.GreaterOrEqual(t, result.Checks[0].LatencyMs, int64(20))
+}
+
+func TestReady_WithoutChecksIsHealthy(t *testing.T) {
+	service := NewHealthService(new(MockEventRepositoryForHealth), &MockDB{}, time.Now(), 0)
+
+	result := service.Ready(ctx)
+
+	assert.Equal(t, "healthy", result.Status)
+	assert.Empty(t, result.Checks)
+}
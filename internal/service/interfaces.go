@@ -1,20 +1,95 @@
 package service
 
-import "github.com/nathaliaoliveira/goapp/internal/domain"
+import (
+    "context"
+    "io"
+    "time"
+
+    "github.com/nathaliaoliveira/goapp/internal/domain"
+)
 
 type UserService interface {
-    Register(name, email, password string) (*domain.User, error)
-    Login(email, password string) (*domain.AuthResponse, error)
-    GetByID(id int) (*domain.User, error)
-    GetAll() ([]domain.User, error)
-    Create(name, email, password string) (*domain.User, error)
+    Register(ctx context.Context, orgName, name, email, password string) (*domain.User, error)
+    Login(ctx context.Context, email, password, clientIP string) (*domain.AuthResponse, error)
+    CompleteMFALogin(ctx context.Context, mfaToken, code, clientIP string) (*domain.AuthResponse, error)
+    StartSession(ctx context.Context, user *domain.User) (*domain.AuthResponse, error)
+    RefreshSession(ctx context.Context, userID int, authTime time.Time) (*domain.AuthResponse, error)
+    CheckSession(ctx context.Context, userID int) error
+    GetByID(ctx context.Context, id int) (*domain.User, error)
+    List(ctx context.Context, actor domain.Actor, params domain.UserListParams) (*domain.UserListResponse, error)
+    Create(ctx context.Context, actor domain.Actor, name, email, password string) (*domain.User, error)
+    Update(ctx context.Context, actor domain.Actor, id int, req domain.UpdateUserRequest) (*domain.User, error)
+    Delete(ctx context.Context, actor domain.Actor, id int) error
+    ChangePassword(ctx context.Context, userID int, currentPassword, newPassword string) error
+    UnlockLogin(ctx context.Context, actor domain.Actor, id int) error
 }
 
 type EventService interface {
-    ProcessEvents(events []domain.EmailEvent) (*domain.EventsResponse, error)
-    GetDailyStats(startDate, endDate, site string) (*domain.StatsResponse, error)
+    ProcessEvents(ctx context.Context, orgID int, events []domain.EmailEvent) (*domain.EventsResponse, error)
+    GetDailyStats(ctx context.Context, orgID int, startDate, endDate, site string) (*domain.StatsResponse, error)
 }
 
 type HealthService interface {
-    GetHealth() (*domain.HealthResponse, error)
-} 
\ No newline at end of file
+    GetHealth(ctx context.Context) (*domain.HealthResponse, error)
+    Live(ctx context.Context) *domain.LivenessResponse
+    Ready(ctx context.Context) *domain.ReadinessResponse
+}
+
+type APIKeyService interface {
+    Create(ctx context.Context, actor domain.Actor, req domain.CreateAPIKeyRequest) (*domain.APIKeyCreatedResponse, error)
+    List(ctx context.Context, userID int) ([]domain.APIKey, error)
+    Revoke(ctx context.Context, userID, id int) error
+    Authenticate(ctx context.Context, rawKey string) (*domain.APIKey, error)
+}
+
+type SignatureService interface {
+    Verify(ctx context.Context, req domain.SignedRequest) (*domain.WebhookSender, error)
+    CreateSender(ctx context.Context, actor domain.Actor, req domain.CreateWebhookSenderRequest) (*domain.WebhookSenderCreatedResponse, error)
+    ListSenders(ctx context.Context, userID int) ([]domain.WebhookSender, error)
+    RevokeSender(ctx context.Context, userID, id int) error
+}
+
+type AccountService interface {
+    RequestPasswordReset(ctx context.Context, email string) error
+    ResetPassword(ctx context.Context, token, newPassword string) error
+    SendVerification(ctx context.Context, user *domain.User) error
+    ResendVerification(ctx context.Context, userID int) error
+    VerifyEmail(ctx context.Context, token string) error
+}
+
+type TwoFactorService interface {
+    Setup(ctx context.Context, userID int) (*domain.TwoFactorSetupResponse, error)
+    Enable(ctx context.Context, userID int, code string) (*domain.RecoveryCodesResponse, error)
+    Disable(ctx context.Context, userID int, password, code string) error
+    RegenerateRecoveryCodes(ctx context.Context, userID int, code string) (*domain.RecoveryCodesResponse, error)
+    Verify(ctx context.Context, userID int, code string) error
+    IsRequired(ctx context.Context, orgID int) (bool, error)
+    SetRequired(ctx context.Context, actor domain.Actor, required bool) error
+}
+
+type SSOService interface {
+    Begin(ctx context.Context, clientIP string) (*domain.SSOStart, error)
+    Complete(ctx context.Context, state, code string) (*domain.AuthResponse, error)
+}
+
+type OrganizationService interface {
+    Get(ctx context.Context, orgID int) (*domain.Organization, error)
+    CreateSite(ctx context.Context, actor domain.Actor, req domain.CreateSiteRequest) (*domain.Site, error)
+    ListSites(ctx context.Context, orgID int) ([]domain.Site, error)
+    DeleteSite(ctx context.Context, actor domain.Actor, id int) error
+}
+
+type AuditService interface {
+    Record(ctx context.Context, entry domain.AuditEntry)
+    List(ctx context.Context, actor domain.Actor, filter domain.AuditFilter) (*domain.AuditListResponse, error)
+    Export(ctx context.Context, actor domain.Actor, filter domain.AuditFilter, format string, w io.Writer) error
+}
+
+// AnalyticsService responde as consultas do painel (API GraphQL). Os filtros
+// são validados e completados aqui: sem período, vale o dos últimos 30 dias.
+type AnalyticsService interface {
+    ListEvents(ctx context.Context, orgID int, filter domain.AnalyticsFilter, page domain.AnalyticsPage) ([]domain.StoredEvent, error)
+    ListCampaigns(ctx context.Context, orgID int, filter domain.AnalyticsFilter, page domain.AnalyticsPage) ([]domain.CampaignSummary, error)
+    ListContacts(ctx context.Context, orgID int, filter domain.AnalyticsFilter, page domain.AnalyticsPage) ([]domain.ContactSummary, error)
+    AggregateStats(ctx context.Context, orgID int, query domain.StatsQuery) ([]domain.StatsBucket, error)
+}
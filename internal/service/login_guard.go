@@ -0,0 +1,177 @@
+package service
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/repository"
+)
+
+// LoginLimits define quantas falhas são toleradas antes do backoff exponencial
+// e a partir de quantas falhas a chave fica bloqueada por LockoutDuration.
+type LoginLimits struct {
+	FreeAttempts     int
+	BaseDelay        time.Duration
+	MaxDelay         time.Duration
+	LockoutThreshold int
+	LockoutDuration  time.Duration
+}
+
+type LoginGuardConfig struct {
+	Account LoginLimits
+	IP      LoginLimits
+}
+
+func DefaultLoginGuardConfig() LoginGuardConfig {
+	return LoginGuardConfig{
+		Account: LoginLimits{
+			FreeAttempts:     3,
+			BaseDelay:        time.Second,
+			MaxDelay:         time.Minute,
+			LockoutThreshold: 10,
+			LockoutDuration:  15 * time.Minute,
+		},
+		IP: LoginLimits{
+			FreeAttempts:     20,
+			BaseDelay:        time.Second,
+			MaxDelay:         time.Minute,
+			LockoutThreshold: 100,
+			LockoutDuration:  15 * time.Minute,
+		},
+	}
+}
+
+// LoginGuard rastreia falhas de login por conta e por IP. As contas são
+// identificadas pelo email informado, exista ele ou não, para que o bloqueio
+// não revele quais emails estão cadastrados.
+type LoginGuard struct {
+	store  repository.LoginAttemptStore
+	config LoginGuardConfig
+	now    func() time.Time
+}
+
+func NewLoginGuard(store repository.LoginAttemptStore, config LoginGuardConfig) *LoginGuard {
+	return &LoginGuard{
+		store:  store,
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// Check reserva uma tentativa na conta e no IP antes de a credencial ser
+// verificada, devolvendo TooManyAttemptsError quando algum deles ainda está em
+// espera. A reserva conta como falha até ser desfeita por RecordSuccess ou
+// Release, de modo que requisições paralelas não passam todas pela mesma
+// verificação antes de a primeira falha ser registrada.
+func (g *LoginGuard) Check(email, ip string) error {
+	var reserved []guardKey
+
+	for _, k := range g.keys(email, ip) {
+		wait, err := g.reserve(k)
+		if err != nil {
+			slog.Warn("Erro ao reservar tentativa de login", "error", err)
+			continue
+		}
+		if wait > 0 {
+			g.release(reserved)
+			return &TooManyAttemptsError{RetryAfter: wait}
+		}
+		reserved = append(reserved, k)
+	}
+	return nil
+}
+
+// reserve soma a tentativa à chave quando ela não está em espera; em espera,
+// devolve quanto falta sem reservar.
+func (g *LoginGuard) reserve(k guardKey) (time.Duration, error) {
+	now := g.now()
+	var wait time.Duration
+
+	_, err := g.store.Update(k.key, func(attempts domain.LoginAttempts) domain.LoginAttempts {
+		if now.Sub(attempts.LastFailure) > k.limits.LockoutDuration {
+			attempts = domain.LoginAttempts{}
+		}
+		if wait = g.retryAfter(attempts, k.limits); wait > 0 {
+			return attempts
+		}
+		attempts.Failures++
+		attempts.LastFailure = now
+		return attempts
+	})
+	return wait, err
+}
+
+// Release desfaz a reserva de Check quando a tentativa terminou sem veredito
+// sobre a credencial: erro interno ou 2FA ainda pendente.
+func (g *LoginGuard) Release(email, ip string) {
+	g.release(g.keys(email, ip))
+}
+
+// RecordSuccess zera o contador da conta e desfaz a reserva do IP, que continua
+// com as falhas anteriores para que um login válido não libere tentativas
+// contra outras contas.
+func (g *LoginGuard) RecordSuccess(email, ip string) {
+	keys := g.keys(email, ip)
+	if err := g.store.Reset(keys[0].key); err != nil {
+		slog.Warn("Erro ao limpar tentativas de login", "error", err)
+	}
+	g.release(keys[1:])
+}
+
+func (g *LoginGuard) release(keys []guardKey) {
+	for _, k := range keys {
+		_, err := g.store.Update(k.key, func(attempts domain.LoginAttempts) domain.LoginAttempts {
+			if attempts.Failures > 0 {
+				attempts.Failures--
+			}
+			return attempts
+		})
+		if err != nil {
+			slog.Warn("Erro ao desfazer reserva de tentativa de login", "error", err)
+		}
+	}
+}
+
+func (g *LoginGuard) Unlock(email string) error {
+	return g.store.Reset(accountKey(email))
+}
+
+func (g *LoginGuard) retryAfter(attempts domain.LoginAttempts, limits LoginLimits) time.Duration {
+	if attempts.Failures < limits.FreeAttempts {
+		return 0
+	}
+
+	var delay time.Duration
+	if limits.LockoutThreshold > 0 && attempts.Failures >= limits.LockoutThreshold {
+		delay = limits.LockoutDuration
+	} else {
+		delay = limits.BaseDelay
+		for i := limits.FreeAttempts; i < attempts.Failures && delay < limits.MaxDelay; i++ {
+			delay *= 2
+		}
+		if delay > limits.MaxDelay {
+			delay = limits.MaxDelay
+		}
+	}
+
+	return attempts.LastFailure.Add(delay).Sub(g.now())
+}
+
+type guardKey struct {
+	key    string
+	limits LoginLimits
+}
+
+func (g *LoginGuard) keys(email, ip string) []guardKey {
+	keys := []guardKey{{key: accountKey(email), limits: g.config.Account}}
+	if ip != "" {
+		keys = append(keys, guardKey{key: "ip:" + ip, limits: g.config.IP})
+	}
+	return keys
+}
+
+func accountKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nathaliaoliveira/goapp/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLoginGuard(now *time.Time) *LoginGuard {
+	guard := NewLoginGuard(repository.NewMemoryLoginAttemptStore(time.Hour), LoginGuardConfig{
+		Account: LoginLimits{FreeAttempts: 2, BaseDelay: time.Second, MaxDelay: 8 * time.Second, LockoutThreshold: 6, LockoutDuration: 10 * time.Minute},
+		IP:      LoginLimits{FreeAttempts: 4, BaseDelay: time.Second, MaxDelay: 8 * time.Second, LockoutThreshold: 20, LockoutDuration: 10 * time.Minute},
+	})
+	guard.now = func() time.Time { return *now }
+	return guard
+}
+
+func TestLoginGuard_ExponentialBackoff(t *testing.T) {
+	now := time.Date(2025, 8, 20, 10, 0, 0, 0, time.UTC)
+	guard := newTestLoginGuard(&now)
+
+	assert.NoError(t, guard.Check("user@example.com", ""))
+	assert.NoError(t, guard.Check("user@example.com", ""))
+
+	err := guard.Check("user@example.com", "")
+	assert.Equal(t, time.Second, err.(*TooManyAttemptsError).RetryAfter)
+
+	now = now.Add(time.Second)
+	assert.NoError(t, guard.Check("user@example.com", ""))
+	err = guard.Check("user@example.com", "")
+	assert.Equal(t, 2*time.Second, err.(*TooManyAttemptsError).RetryAfter, "a tentativa recusada não conta como falha")
+
+	now = now.Add(2 * time.Second)
+	assert.NoError(t, guard.Check("user@example.com", ""))
+}
+
+func TestLoginGuard_LockoutAfterThreshold(t *testing.T) {
+	now := time.Date(2025, 8, 20, 10, 0, 0, 0, time.UTC)
+	guard := newTestLoginGuard(&now)
+
+	for i := 0; i < 6; i++ {
+		assert.NoError(t, guard.Check("User@Example.com", ""))
+		now = now.Add(time.Minute)
+	}
+
+	err := guard.Check("user@example.com", "")
+	assert.Equal(t, 9*time.Minute, err.(*TooManyAttemptsError).RetryAfter)
+
+	now = now.Add(9 * time.Minute)
+	assert.NoError(t, guard.Check("user@example.com", ""))
+}
+
+func TestLoginGuard_PerIPAcrossAccounts(t *testing.T) {
+	now := time.Date(2025, 8, 20, 10, 0, 0, 0, time.UTC)
+	guard := newTestLoginGuard(&now)
+
+	for _, email := range []string{"a@example.com", "b@example.com", "c@example.com", "d@example.com"} {
+		assert.NoError(t, guard.Check(email, "198.51.100.1"))
+	}
+
+	assert.Error(t, guard.Check("e@example.com", "198.51.100.1"))
+	assert.NoError(t, guard.Check("e@example.com", "198.51.100.2"), "a recusa pelo IP desfaz a reserva da conta")
+	assert.NoError(t, guard.Check("e@example.com", "198.51.100.3"))
+}
+
+func TestLoginGuard_SuccessKeepsIPCounter(t *testing.T) {
+	now := time.Date(2025, 8, 20, 10, 0, 0, 0, time.UTC)
+	guard := newTestLoginGuard(&now)
+
+	for _, email := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+		assert.NoError(t, guard.Check(email, "198.51.100.1"))
+	}
+	assert.NoError(t, guard.Check("user@example.com", "198.51.100.1"))
+	guard.RecordSuccess("user@example.com", "198.51.100.1")
+
+	assert.NoError(t, guard.Check("user@example.com", "198.51.100.1"))
+	assert.Error(t, guard.Check("user@example.com", "198.51.100.1"))
+}
+
+func TestLoginGuard_ReleaseUndoesReservation(t *testing.T) {
+	now := time.Date(2025, 8, 20, 10, 0, 0, 0, time.UTC)
+	guard := newTestLoginGuard(&now)
+
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, guard.Check("user@example.com", ""))
+		guard.Release("user@example.com", "")
+	}
+}
+
+func TestLoginGuard_ConcurrentChecksReserveAttempts(t *testing.T) {
+	now := time.Date(2025, 8, 20, 10, 0, 0, 0, time.UTC)
+	guard := newTestLoginGuard(&now)
+
+	var allowed atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if guard.Check("user@example.com", "") == nil {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(2), allowed.Load())
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/repository"
+	"github.com/nathaliaoliveira/goapp/internal/tracing"
+)
+
+type organizationService struct {
+	orgRepo  repository.OrganizationRepository
+	siteRepo repository.SiteRepository
+}
+
+func NewOrganizationService(orgRepo repository.OrganizationRepository, siteRepo repository.SiteRepository) OrganizationService {
+	return &organizationService{
+		orgRepo:  orgRepo,
+		siteRepo: siteRepo,
+	}
+}
+
+func (s *organizationService) Get(ctx context.Context, orgID int) (*domain.Organization, error) {
+	ctx, span := tracing.Start(ctx, "OrganizationService.Get")
+	defer span.End()
+
+	return s.orgRepo.GetByID(ctx, orgID)
+}
+
+func (s *organizationService) CreateSite(ctx context.Context, actor domain.Actor, req domain.CreateSiteRequest) (*domain.Site, error) {
+	ctx, span := tracing.Start(ctx, "OrganizationService.CreateSite")
+	defer span.End()
+
+	if !actor.IsAdmin() {
+		return nil, &ForbiddenError{Key: "site.create_forbidden"}
+	}
+
+	domainName := strings.ToLower(strings.TrimSpace(req.Domain))
+	if domainName == "" || strings.ContainsAny(domainName, " /") {
+		return nil, &ValidationError{Key: "site.invalid_domain"}
+	}
+
+	return s.siteRepo.Create(ctx, actor.OrgID, domainName)
+}
+
+func (s *organizationService) ListSites(ctx context.Context, orgID int) ([]domain.Site, error) {
+	ctx, span := tracing.Start(ctx, "OrganizationService.ListSites")
+	defer span.End()
+
+	return s.siteRepo.List(ctx, orgID)
+}
+
+func (s *organizationService) DeleteSite(ctx context.Context, actor domain.Actor, id int) error {
+	ctx, span := tracing.Start(ctx, "OrganizationService.DeleteSite")
+	defer span.End()
+
+	if !actor.IsAdmin() {
+		return &ForbiddenError{Key: "site.delete_forbidden"}
+	}
+
+	return s.siteRepo.Delete(ctx, actor.OrgID, id)
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateSite_AdminOnlyAndNormalized(t *testing.T) {
+	sites := new(MockSiteRepository)
+	service := NewOrganizationService(nil, sites)
+
+	_, err := service.CreateSite(ctx, domain.Actor{UserID: 2, OrgID: 1, Role: domain.RoleUser}, domain.CreateSiteRequest{Domain: "site-a.com"})
+	assert.IsType(t, &ForbiddenError{}, err)
+
+	sites.On("Create", 1, "site-a.com").Return(&domain.Site{ID: 1, OrgID: 1, Domain: "site-a.com"}, nil)
+
+	site, err := service.CreateSite(ctx, domain.Actor{UserID: 1, OrgID: 1, Role: domain.RoleAdmin}, domain.CreateSiteRequest{Domain: "  Site-A.com "})
+	assert.NoError(t, err)
+	assert.Equal(t, "site-a.com", site.Domain)
+
+	sites.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestDeleteSite_ScopedToActorOrganization(t *testing.T) {
+	sites := new(MockSiteRepository)
+	service := NewOrganizationService(nil, sites)
+
+	sites.On("Delete", 3, 10).Return(nil)
+
+	assert.NoError(t, service.DeleteSite(ctx, domain.Actor{UserID: 1, OrgID: 3, Role: domain.RoleAdmin}, 10))
+	sites.AssertExpectations(t)
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"log/slog"
+	"strings"
+	"unicode"
+
+	"github.com/nathaliaoliveira/goapp/internal/breach"
+	"github.com/nathaliaoliveira/goapp/internal/i18n"
+)
+
+type BreachedPasswordChecker interface {
+	IsBreached(password string) (bool, error)
+}
+
+type PasswordPolicy struct {
+	MinLength          int
+	RequireUpper       bool
+	RequireLower       bool
+	RequireDigit       bool
+	RequireSymbol      bool
+	ForbidPersonalInfo bool
+	Breached           BreachedPasswordChecker
+}
+
+// bcrypt ignora tudo depois de 72 bytes.
+const maxPasswordBytes = 72
+
+func DefaultPasswordPolicy() *PasswordPolicy {
+	return &PasswordPolicy{
+		MinLength:          10,
+		RequireUpper:       true,
+		RequireLower:       true,
+		RequireDigit:       true,
+		ForbidPersonalInfo: true,
+		Breached:           breach.NewLocalChecker(),
+	}
+}
+
+// Validate devolve uma entrada para cada regra violada; a lista vazia indica senha aceita.
+func (p *PasswordPolicy) Validate(password, email, name string) []ValidationDetail {
+	var details []ValidationDetail
+	fail := func(rule string, args ...interface{}) {
+		key := "password.rule." + rule
+		details = append(details, ValidationDetail{
+			Field:   "password",
+			Rule:    rule,
+			Message: i18n.T(i18n.DefaultLanguage, key, args...),
+			Key:     key,
+			Args:    args,
+		})
+	}
+
+	if len([]rune(password)) < p.MinLength {
+		fail("min_length", p.MinLength)
+	}
+	if len(password) > maxPasswordBytes {
+		fail("max_length", maxPasswordBytes)
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		default:
+			hasSymbol = true
+		}
+	}
+
+	if p.RequireUpper && !hasUpper {
+		fail("uppercase")
+	}
+	if p.RequireLower && !hasLower {
+		fail("lowercase")
+	}
+	if p.RequireDigit && !hasDigit {
+		fail("digit")
+	}
+	if p.RequireSymbol && !hasSymbol {
+		fail("symbol")
+	}
+
+	if p.ForbidPersonalInfo && matchesPersonalInfo(password, email, name) {
+		fail("personal_info")
+	}
+
+	if p.Breached != nil {
+		breached, err := p.Breached.IsBreached(password)
+		if err != nil {
+			slog.Warn("Erro ao consultar lista de senhas vazadas", "error", err)
+		} else if breached {
+			fail("breached")
+		}
+	}
+
+	return details
+}
+
+func (p *PasswordPolicy) check(password, email, name string) error {
+	details := p.Validate(password, email, name)
+	if len(details) == 0 {
+		return nil
+	}
+	return &ValidationError{Key: "password.policy_violation", Details: details}
+}
+
+func matchesPersonalInfo(password, email, name string) bool {
+	candidate := strings.ToLower(strings.TrimSpace(password))
+	values := []string{email, name, strings.ReplaceAll(name, " ", "")}
+	if at := strings.Index(email, "@"); at > 0 {
+		values = append(values, email[:at])
+	}
+
+	for _, value := range values {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value != "" && candidate == value {
+			return true
+		}
+	}
+	return false
+}
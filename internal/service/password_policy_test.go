@@ -0,0 +1,74 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubBreachChecker map[string]bool
+
+func (s stubBreachChecker) IsBreached(password string) (bool, error) {
+	return s[password], nil
+}
+
+func rules(details []ValidationDetail) []string {
+	var names []string
+	for _, d := range details {
+		names = append(names, d.Rule)
+	}
+	return names
+}
+
+func TestPasswordPolicy_AcceptsStrongPassword(t *testing.T) {
+	policy := DefaultPasswordPolicy()
+
+	assert.Empty(t, policy.Validate("Sup3r-Secreta", "test@example.com", "Test User"))
+}
+
+func TestPasswordPolicy_ListsEveryFailedRule(t *testing.T) {
+	policy := &PasswordPolicy{MinLength: 10, RequireUpper: true, RequireDigit: true, RequireSymbol: true}
+
+	details := policy.Validate("curta", "test@example.com", "Test User")
+
+	assert.ElementsMatch(t, []string{"min_length", "uppercase", "digit", "symbol"}, rules(details))
+	for _, d := range details {
+		assert.Equal(t, "password", d.Field)
+		assert.NotEmpty(t, d.Message)
+	}
+}
+
+func TestPasswordPolicy_ForbidsPersonalInfo(t *testing.T) {
+	policy := &PasswordPolicy{ForbidPersonalInfo: true}
+
+	for _, password := range []string{"Test@Example.com", "test", "TestUser", "test user"} {
+		assert.Equal(t, []string{"personal_info"}, rules(policy.Validate(password, "test@example.com", "Test User")), password)
+	}
+}
+
+func TestPasswordPolicy_RejectsBreachedPassword(t *testing.T) {
+	policy := &PasswordPolicy{Breached: stubBreachChecker{"Vazada-123": true}}
+
+	assert.Equal(t, []string{"breached"}, rules(policy.Validate("Vazada-123", "", "")))
+	assert.Empty(t, policy.Validate("Inedita-123", "", ""))
+}
+
+func TestDefaultPasswordPolicy_RejectsSeededPassword(t *testing.T) {
+	details := DefaultPasswordPolicy().Validate("admin123", "admin@test.com", "Admin User")
+
+	assert.Contains(t, rules(details), "breached")
+}
+
+func TestRegister_WeakPasswordReturnsDetails(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, []byte("test-secret"))
+
+	result, err := service.Register(ctx, "", "Test User", "test@example.com", "password123")
+
+	assert.Nil(t, result)
+	validationErr, ok := err.(*ValidationError)
+	assert.True(t, ok)
+	assert.Contains(t, rules(validationErr.Details), "uppercase")
+	assert.Contains(t, rules(validationErr.Details), "breached")
+	mockRepo.AssertNotCalled(t, "CreateWithOrganization")
+}
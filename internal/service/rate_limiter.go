@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/repository"
+)
+
+// Grupos de rotas do limitador de requisições.
+const (
+	// RateLimitGroupAuth são as rotas públicas de credenciais (/login,
+	// /register, /password/*...), limitadas por IP.
+	RateLimitGroupAuth = "auth"
+	// RateLimitGroupEvents é a ingestão em POST /api/events.
+	RateLimitGroupEvents = "events"
+	// RateLimitGroupAPI são as demais rotas autenticadas.
+	RateLimitGroupAPI = "api"
+)
+
+var (
+	RateLimitGroups     = []string{RateLimitGroupAuth, RateLimitGroupEvents, RateLimitGroupAPI}
+	RateLimitPrincipals = []string{domain.PrincipalAPIKey, domain.PrincipalUser, domain.PrincipalIP}
+)
+
+// RateLimitPolicy define o limite de um grupo por tipo de principal. Tipos
+// ausentes não são limitados.
+type RateLimitPolicy map[string]domain.RateLimit
+
+// RateLimiterConfig associa cada grupo de rotas à sua política.
+type RateLimiterConfig map[string]RateLimitPolicy
+
+// DefaultRateLimiterConfig devolve os limites padrão. Nos grupos autenticados
+// o limite por IP vale antes da autenticação e o da chave de API ou do
+// usuário, depois dela.
+func DefaultRateLimiterConfig() RateLimiterConfig {
+	return RateLimiterConfig{
+		RateLimitGroupAuth: {
+			domain.PrincipalIP: {Requests: 20, Period: time.Minute},
+		},
+		RateLimitGroupEvents: {
+			domain.PrincipalAPIKey: {Requests: 600, Period: time.Minute},
+			domain.PrincipalUser:   {Requests: 120, Period: time.Minute},
+			domain.PrincipalIP:     {Requests: 1200, Period: time.Minute},
+		},
+		RateLimitGroupAPI: {
+			domain.PrincipalAPIKey: {Requests: 600, Period: time.Minute},
+			domain.PrincipalUser:   {Requests: 600, Period: time.Minute},
+			domain.PrincipalIP:     {Requests: 1200, Period: time.Minute},
+		},
+	}
+}
+
+// ParseRateLimit lê limites no formato "<requisições>/<período>", como
+// "600/1m" ou "10/1s". "0" e "off" desligam o limite.
+func ParseRateLimit(value string) (domain.RateLimit, error) {
+	value = strings.TrimSpace(value)
+	if value == "0" || strings.EqualFold(value, "off") {
+		return domain.RateLimit{}, nil
+	}
+
+	requests, period, ok := strings.Cut(value, "/")
+	if !ok {
+		return domain.RateLimit{}, fmt.Errorf("limite inválido %q: use <requisições>/<período>, ex.: 600/1m", value)
+	}
+	n, err := strconv.Atoi(requests)
+	if err != nil || n < 0 {
+		return domain.RateLimit{}, fmt.Errorf("número de requisições inválido em %q", value)
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return domain.RateLimit{}, fmt.Errorf("período inválido em %q", value)
+	}
+	return domain.RateLimit{Requests: n, Period: d}, nil
+}
+
+// RateLimiter aplica os limites de requisições por grupo de rotas e
+// principal, com baldes de fichas guardados em store.
+type RateLimiter struct {
+	store  repository.RateLimitStore
+	config RateLimiterConfig
+}
+
+func NewRateLimiter(store repository.RateLimitStore, config RateLimiterConfig) *RateLimiter {
+	return &RateLimiter{
+		store:  store,
+		config: config,
+	}
+}
+
+// Allow consome uma ficha do principal no grupo. Devolve nil quando o grupo
+// não limita esse tipo de principal. Erros do store não bloqueiam a
+// requisição: o limitador falha aberto e só registra o erro.
+func (l *RateLimiter) Allow(ctx context.Context, group, kind, id string) *domain.RateLimitResult {
+	limit := l.config[group][kind]
+	if !limit.Enabled() {
+		return nil
+	}
+
+	result, err := l.store.Take(ctx, group+":"+kind+":"+id, limit)
+	if err != nil {
+		slog.WarnContext(ctx, "Erro ao consultar o limite de requisições", "group", group, "principal", kind, "error", err)
+		return nil
+	}
+	return &result
+}
+
+// Limit devolve o limite configurado, para o cabeçalho RateLimit-Policy.
+func (l *RateLimiter) Limit(group, kind string) domain.RateLimit {
+	return l.config[group][kind]
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingRateLimitStore struct{}
+
+func (failingRateLimitStore) Take(ctx context.Context, key string, limit domain.RateLimit) (domain.RateLimitResult, error) {
+	return domain.RateLimitResult{}, errors.New("store indisponível")
+}
+
+func TestRateLimiter_SeparateBucketsPerGroupAndPrincipal(t *testing.T) {
+	limiter := NewRateLimiter(repository.NewMemoryRateLimitStore(), RateLimiterConfig{
+		RateLimitGroupEvents: {domain.PrincipalAPIKey: {Requests: 1, Period: time.Minute}},
+		RateLimitGroupAPI:    {domain.PrincipalAPIKey: {Requests: 1, Period: time.Minute}},
+	})
+
+	require.True(t, limiter.Allow(ctx, RateLimitGroupEvents, domain.PrincipalAPIKey, "7").Allowed)
+	assert.False(t, limiter.Allow(ctx, RateLimitGroupEvents, domain.PrincipalAPIKey, "7").Allowed)
+	assert.True(t, limiter.Allow(ctx, RateLimitGroupEvents, domain.PrincipalAPIKey, "8").Allowed)
+	assert.True(t, limiter.Allow(ctx, RateLimitGroupAPI, domain.PrincipalAPIKey, "7").Allowed)
+
+	assert.Nil(t, limiter.Allow(ctx, RateLimitGroupEvents, domain.PrincipalUser, "7"), "sem limite para usuários no grupo")
+	assert.Nil(t, limiter.Allow(ctx, RateLimitGroupAuth, domain.PrincipalIP, "1.2.3.4"), "grupo sem política")
+}
+
+func TestRateLimiter_FailsOpenOnStoreErrors(t *testing.T) {
+	limiter := NewRateLimiter(failingRateLimitStore{}, DefaultRateLimiterConfig())
+
+	assert.Nil(t, limiter.Allow(ctx, RateLimitGroupAuth, domain.PrincipalIP, "1.2.3.4"))
+}
+
+func TestParseRateLimit(t *testing.T) {
+	limit, err := ParseRateLimit("600/1m")
+	require.NoError(t, err)
+	assert.Equal(t, domain.RateLimit{Requests: 600, Period: time.Minute}, limit)
+
+	for _, off := range []string{"0", "off"} {
+		limit, err = ParseRateLimit(off)
+		require.NoError(t, err)
+		assert.False(t, limit.Enabled())
+	}
+
+	for _, invalid := range []string{"600", "x/1m", "-1/1m", "10/minuto", "10/0s"} {
+		_, err = ParseRateLimit(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/repository"
+	"github.com/nathaliaoliveira/goapp/internal/tracing"
+)
+
+const (
+	DefaultSignatureTolerance = 5 * time.Minute
+	maxNonceLength            = 128
+)
+
+type signatureService struct {
+	senderRepo repository.WebhookSenderRepository
+	nonces     repository.NonceStore
+	tolerance  time.Duration
+	now        func() time.Time
+}
+
+func NewSignatureService(senderRepo repository.WebhookSenderRepository, nonces repository.NonceStore, tolerance time.Duration) SignatureService {
+	if tolerance <= 0 {
+		tolerance = DefaultSignatureTolerance
+	}
+	return &signatureService{
+		senderRepo: senderRepo,
+		nonces:     nonces,
+		tolerance:  tolerance,
+		now:        time.Now,
+	}
+}
+
+// Verify confere a assinatura HMAC-SHA256 de "timestamp.nonce.corpo" com o segredo
+// do remetente, rejeitando timestamps fora da janela de tolerância e nonces repetidos.
+func (s *signatureService) Verify(ctx context.Context, req domain.SignedRequest) (*domain.WebhookSender, error) {
+	ctx, span := tracing.Start(ctx, "SignatureService.Verify")
+	defer span.End()
+
+	if req.KeyID == "" || req.Timestamp == "" || req.Nonce == "" || req.Signature == "" {
+		return nil, &AuthenticationError{Key: "signature.headers_missing"}
+	}
+
+	if len(req.Nonce) > maxNonceLength {
+		return nil, &AuthenticationError{Key: "signature.invalid_nonce"}
+	}
+
+	unixSeconds, err := strconv.ParseInt(req.Timestamp, 10, 64)
+	if err != nil {
+		return nil, &AuthenticationError{Key: "signature.invalid_timestamp"}
+	}
+
+	signedAt := time.Unix(unixSeconds, 0)
+	now := s.now()
+	if signedAt.Before(now.Add(-s.tolerance)) || signedAt.After(now.Add(s.tolerance)) {
+		return nil, &AuthenticationError{Key: "signature.timestamp_out_of_window"}
+	}
+
+	sender, err := s.senderRepo.GetByKeyID(ctx, req.KeyID)
+	if err != nil || sender.RevokedAt != nil {
+		return nil, &AuthenticationError{Key: "signature.invalid"}
+	}
+
+	expected := SignPayload(sender.Secret, req.Timestamp, req.Nonce, req.Body)
+	provided := strings.TrimPrefix(req.Signature, "sha256=")
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(provided))) {
+		return nil, &AuthenticationError{Key: "signature.invalid"}
+	}
+
+	fresh, err := s.nonces.Remember(sender.KeyID+":"+req.Nonce, signedAt.Add(s.tolerance))
+	if err != nil {
+		return nil, &InternalError{Key: "signature.nonce_check_failed", Cause: err}
+	}
+	if !fresh {
+		return nil, &AuthenticationError{Key: "signature.replayed"}
+	}
+
+	return sender, nil
+}
+
+func (s *signatureService) CreateSender(ctx context.Context, actor domain.Actor, req domain.CreateWebhookSenderRequest) (*domain.WebhookSenderCreatedResponse, error) {
+	ctx, span := tracing.Start(ctx, "SignatureService.CreateSender")
+	defer span.End()
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, &ValidationError{Key: "sender.name_required"}
+	}
+
+	sites := []string{}
+	for _, site := range req.Sites {
+		if site = strings.TrimSpace(site); site != "" {
+			sites = append(sites, site)
+		}
+	}
+
+	keyID, err := randomHex(8)
+	if err != nil {
+		return nil, &InternalError{Key: "sender.key_id_failed", Cause: err}
+	}
+
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, &InternalError{Key: "sender.secret_failed", Cause: err}
+	}
+
+	sender, err := s.senderRepo.Create(ctx, &domain.WebhookSender{
+		UserID: actor.UserID,
+		OrgID:  actor.OrgID,
+		Name:   name,
+		KeyID:  "whk_" + keyID,
+		Secret: secret,
+		Sites:  sites,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.WebhookSenderCreatedResponse{
+		Secret: secret,
+		Sender: *sender,
+	}, nil
+}
+
+func (s *signatureService) ListSenders(ctx context.Context, userID int) ([]domain.WebhookSender, error) {
+	ctx, span := tracing.Start(ctx, "SignatureService.ListSenders")
+	defer span.End()
+
+	return s.senderRepo.ListByUser(ctx, userID)
+}
+
+func (s *signatureService) RevokeSender(ctx context.Context, userID, id int) error {
+	ctx, span := tracing.Start(ctx, "SignatureService.RevokeSender")
+	defer span.End()
+
+	return s.senderRepo.Revoke(ctx, id, userID)
+}
+
+// SignPayload calcula a assinatura esperada em hexadecimal; os remetentes usam o mesmo algoritmo.
+func SignPayload(secret, timestamp, nonce string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write([]byte(nonce))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWebhookSenderRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookSenderRepository) Create(ctx context.Context, sender *domain.WebhookSender) (*domain.WebhookSender, error) {
+	args := m.Called(sender)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WebhookSender), args.Error(1)
+}
+
+func (m *MockWebhookSenderRepository) GetByKeyID(ctx context.Context, keyID string) (*domain.WebhookSender, error) {
+	args := m.Called(keyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WebhookSender), args.Error(1)
+}
+
+func (m *MockWebhookSenderRepository) ListByUser(ctx context.Context, userID int) ([]domain.WebhookSender, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]domain.WebhookSender), args.Error(1)
+}
+
+func (m *MockWebhookSenderRepository) Revoke(ctx context.Context, id, userID int) error {
+	args := m.Called(id, userID)
+	return args.Error(0)
+}
+
+func signedRequest(secret string, signedAt time.Time, nonce string, body []byte) domain.SignedRequest {
+	timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+	return domain.SignedRequest{
+		KeyID:     "whk_test",
+		Timestamp: timestamp,
+		Nonce:     nonce,
+		Signature: "sha256=" + SignPayload(secret, timestamp, nonce, body),
+		Body:      body,
+	}
+}
+
+func newTestSignatureService(repo *MockWebhookSenderRepository) SignatureService {
+	return NewSignatureService(repo, repository.NewMemoryNonceStore(), time.Minute)
+}
+
+func TestVerifySignature_Valid(t *testing.T) {
+	mockRepo := new(MockWebhookSenderRepository)
+	service := newTestSignatureService(mockRepo)
+
+	sender := &domain.WebhookSender{ID: 1, UserID: 2, KeyID: "whk_test", Secret: "s3cr3t"}
+	mockRepo.On("GetByKeyID", "whk_test").Return(sender, nil)
+
+	result, err := service.Verify(ctx, signedRequest("s3cr3t", time.Now(), "nonce-1", []byte(`{"events":[]}`)))
+
+	assert.NoError(t, err)
+	assert.Equal(t, sender, result)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestVerifySignature_TamperedBody(t *testing.T) {
+	mockRepo := new(MockWebhookSenderRepository)
+	service := newTestSignatureService(mockRepo)
+
+	mockRepo.On("GetByKeyID", "whk_test").Return(&domain.WebhookSender{KeyID: "whk_test", Secret: "s3cr3t"}, nil)
+
+	req := signedRequest("s3cr3t", time.Now(), "nonce-1", []byte(`{"events":[]}`))
+	req.Body = []byte(`{"events":[{}]}`)
+
+	result, err := service.Verify(ctx, req)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "Assinatura inválida")
+}
+
+func TestVerifySignature_OutsideTolerance(t *testing.T) {
+	mockRepo := new(MockWebhookSenderRepository)
+	service := newTestSignatureService(mockRepo)
+
+	result, err := service.Verify(ctx, signedRequest("s3cr3t", time.Now().Add(-2*time.Minute), "nonce-1", []byte("{}")))
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "fora da janela")
+
+	mockRepo.AssertNotCalled(t, "GetByKeyID", mock.Anything)
+}
+
+func TestVerifySignature_ReusedNonce(t *testing.T) {
+	mockRepo := new(MockWebhookSenderRepository)
+	service := newTestSignatureService(mockRepo)
+
+	mockRepo.On("GetByKeyID", "whk_test").Return(&domain.WebhookSender{KeyID: "whk_test", Secret: "s3cr3t"}, nil)
+
+	req := signedRequest("s3cr3t", time.Now(), "nonce-1", []byte("{}"))
+
+	_, err := service.Verify(ctx, req)
+	assert.NoError(t, err)
+
+	result, err := service.Verify(ctx, req)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "Requisição repetida")
+}
+
+func TestVerifySignature_RevokedSender(t *testing.T) {
+	mockRepo := new(MockWebhookSenderRepository)
+	service := newTestSignatureService(mockRepo)
+
+	revokedAt := time.Now()
+	mockRepo.On("GetByKeyID", "whk_test").Return(&domain.WebhookSender{KeyID: "whk_test", Secret: "s3cr3t", RevokedAt: &revokedAt}, nil)
+
+	result, err := service.Verify(ctx, signedRequest("s3cr3t", time.Now(), "nonce-1", []byte("{}")))
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.IsType(t, &AuthenticationError{}, err)
+}
+
+func TestCreateSender_ReturnsSecretOnce(t *testing.T) {
+	mockRepo := new(MockWebhookSenderRepository)
+	service := newTestSignatureService(mockRepo)
+
+	var stored *domain.WebhookSender
+	mockRepo.On("Create", mock.AnythingOfType("*domain.WebhookSender")).
+		Run(func(args mock.Arguments) {
+			stored = args.Get(0).(*domain.WebhookSender)
+		}).
+		Return(&domain.WebhookSender{ID: 1, Name: "esp"}, nil)
+
+	result, err := service.CreateSender(ctx, domain.Actor{UserID: 2, OrgID: 5, Role: domain.RoleUser}, domain.CreateWebhookSenderRequest{Name: "esp"})
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Secret, 64)
+	assert.Equal(t, result.Secret, stored.Secret)
+	assert.Equal(t, 2, stored.UserID)
+	assert.Equal(t, 5, stored.OrgID)
+	assert.Regexp(t, "^whk_[0-9a-f]{16}$", stored.KeyID)
+}
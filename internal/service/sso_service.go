@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/oidc"
+	"github.com/nathaliaoliveira/goapp/internal/repository"
+	"github.com/nathaliaoliveira/goapp/internal/tracing"
+)
+
+// Tempo máximo entre o redirecionamento para o provedor e o callback.
+const ssoStateTTL = 10 * time.Minute
+
+// OIDCProvider é a parte do cliente OIDC usada pelo login SSO.
+type OIDCProvider interface {
+	AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error)
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*oidc.Claims, error)
+}
+
+type ssoService struct {
+	provider     OIDCProvider
+	states       repository.SSOStateStore
+	identityRepo repository.IdentityRepository
+	userRepo     repository.UserRepository
+	userService  UserService
+	orgID        int
+	now          func() time.Time
+}
+
+// NewSSOService cria os usuários que ainda não existem na organização orgID.
+func NewSSOService(provider OIDCProvider, states repository.SSOStateStore, identityRepo repository.IdentityRepository, userRepo repository.UserRepository, userService UserService, orgID int) SSOService {
+	return &ssoService{
+		provider:     provider,
+		states:       states,
+		identityRepo: identityRepo,
+		userRepo:     userRepo,
+		userService:  userService,
+		orgID:        orgID,
+		now:          time.Now,
+	}
+}
+
+// Begin recusa com TooManyAttemptsError quando clientIP já tem logins demais
+// em andamento.
+func (s *ssoService) Begin(ctx context.Context, clientIP string) (*domain.SSOStart, error) {
+	ctx, span := tracing.Start(ctx, "SSOService.Begin")
+	defer span.End()
+
+	state, err := oidc.RandomString(32)
+	if err != nil {
+		return nil, &InternalError{Key: "sso.start_failed", Cause: err}
+	}
+	nonce, err := oidc.RandomString(32)
+	if err != nil {
+		return nil, &InternalError{Key: "sso.start_failed", Cause: err}
+	}
+	verifier, err := oidc.NewCodeVerifier()
+	if err != nil {
+		return nil, &InternalError{Key: "sso.start_failed", Cause: err}
+	}
+
+	authURL, err := s.provider.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		return nil, &InternalError{Key: "sso.provider_unavailable", Cause: err}
+	}
+
+	err = s.states.Save(state, domain.SSOState{
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    s.now().Add(ssoStateTTL),
+		ClientIP:     clientIP,
+	})
+	if errors.Is(err, repository.ErrTooManySSOStates) {
+		return nil, &TooManyAttemptsError{RetryAfter: ssoStateTTL}
+	}
+	if err != nil {
+		return nil, &InternalError{Key: "sso.start_failed", Cause: err}
+	}
+
+	return &domain.SSOStart{AuthURL: authURL, State: state}, nil
+}
+
+// Complete valida o callback do provedor e devolve a sessão do usuário local
+// vinculado à identidade. Na primeira vez a identidade é vinculada pelo email
+// confirmado pelo provedor, criando o usuário se ele ainda não existir.
+func (s *ssoService) Complete(ctx context.Context, state, code string) (*domain.AuthResponse, error) {
+	ctx, span := tracing.Start(ctx, "SSOService.Complete")
+	defer span.End()
+
+	if state == "" || code == "" {
+		return nil, &ValidationError{Key: "sso.state_and_code_required"}
+	}
+
+	pending, err := s.states.Take(state)
+	if err != nil {
+		return nil, &ValidationError{Key: "sso.state_invalid"}
+	}
+
+	claims, err := s.provider.Exchange(ctx, code, pending.CodeVerifier, pending.Nonce)
+	if err != nil {
+		slog.ErrorContext(ctx, "Falha ao validar resposta do provedor OIDC", "error", err)
+		return nil, &AuthenticationError{Key: "sso.authentication_failed"}
+	}
+
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, &ForbiddenError{Key: "sso.email_not_verified"}
+	}
+
+	user, err := s.resolveUser(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.identityRepo.Link(ctx, user.ID, claims.Issuer, claims.Subject, claims.Email); err != nil {
+		return nil, &InternalError{Key: "sso.link_failed", Cause: err}
+	}
+
+	return s.userService.StartSession(ctx, user)
+}
+
+func (s *ssoService) resolveUser(ctx context.Context, claims *oidc.Claims) (*domain.User, error) {
+	userID, err := s.identityRepo.FindUserID(ctx, claims.Issuer, claims.Subject)
+	if err == nil {
+		return s.userRepo.GetByID(ctx, userID)
+	}
+	var identityNotFound *repository.IdentityNotFoundError
+	if !errors.As(err, &identityNotFound) {
+		return nil, &InternalError{Key: "sso.lookup_failed", Cause: err}
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, claims.Email)
+	if err != nil {
+		var userNotFound *repository.UserNotFoundError
+		if !errors.As(err, &userNotFound) {
+			return nil, err
+		}
+		user, err = s.provision(ctx, claims)
+		if err != nil {
+			return nil, err
+		}
+		slog.InfoContext(ctx, "Usuário criado via SSO", "email", user.Email)
+	} else {
+		slog.InfoContext(ctx, "Identidade SSO vinculada ao usuário", "email", user.Email)
+	}
+
+	if !user.EmailVerified {
+		if err := s.userRepo.MarkEmailVerified(ctx, user.ID); err != nil {
+			return nil, err
+		}
+		user.EmailVerified = true
+	}
+
+	return user, nil
+}
+
+// provision cria o usuário sem senha local: o login passa a depender do provedor.
+func (s *ssoService) provision(ctx context.Context, claims *oidc.Claims) (*domain.User, error) {
+	name := strings.TrimSpace(claims.Name)
+	if name == "" {
+		name = claims.Email[:strings.Index(claims.Email+"@", "@")]
+	}
+
+	user, err := s.userRepo.Create(ctx, s.orgID, name, claims.Email, "")
+	if err != nil {
+		// Outro callback pode ter criado o mesmo usuário ao mesmo tempo.
+		var duplicate *repository.DuplicateEmailError
+		if errors.As(err, &duplicate) {
+			return s.userRepo.GetByEmail(ctx, claims.Email)
+		}
+		return nil, err
+	}
+	return user, nil
+}
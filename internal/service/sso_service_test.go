@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/oidc"
+	"github.com/nathaliaoliveira/goapp/internal/oidc/oidctest"
+	"github.com/nathaliaoliveira/goapp/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockIdentityRepository struct {
+	mock.Mock
+}
+
+func (m *MockIdentityRepository) FindUserID(ctx context.Context, issuer, subject string) (int, error) {
+	args := m.Called(issuer, subject)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockIdentityRepository) Link(ctx context.Context, userID int, issuer, subject, email string) error {
+	args := m.Called(userID, issuer, subject, email)
+	return args.Error(0)
+}
+
+type ssoFixture struct {
+	idp        *oidctest.Provider
+	users      *MockUserRepository
+	identities *MockIdentityRepository
+	service    SSOService
+}
+
+func newSSOFixture(t *testing.T) *ssoFixture {
+	idp, err := oidctest.NewProvider()
+	require.NoError(t, err)
+	t.Cleanup(idp.Close)
+
+	provider := oidc.NewProvider(oidc.Config{
+		IssuerURL:    idp.URL,
+		ClientID:     idp.ClientID,
+		ClientSecret: idp.ClientSecret,
+		RedirectURL:  "http://app.test/auth/oidc/callback",
+	}, nil)
+
+	users := new(MockUserRepository)
+	identities := new(MockIdentityRepository)
+	userService := NewUserService(users, []byte("test-secret"))
+
+	return &ssoFixture{
+		idp:        idp,
+		users:      users,
+		identities: identities,
+		service:    NewSSOService(provider, repository.NewMemorySSOStateStore(), identities, users, userService, 1),
+	}
+}
+
+// login percorre o fluxo completo: início, autorização no provedor e callback.
+func (f *ssoFixture) login(t *testing.T) (*domain.AuthResponse, error) {
+	start, err := f.service.Begin(ctx, "203.0.113.7")
+	require.NoError(t, err)
+
+	code, state, err := f.idp.Authorize(start.AuthURL)
+	require.NoError(t, err)
+	require.Equal(t, start.State, state)
+
+	return f.service.Complete(ctx, state, code)
+}
+
+func TestSSOComplete_ProvisionsNewUser(t *testing.T) {
+	f := newSSOFixture(t)
+
+	created := &domain.User{ID: 7, OrgID: 1, Name: "SSO User", Email: "sso@example.com", Role: domain.RoleUser}
+	f.identities.On("FindUserID", f.idp.URL, "user-1").Return(0, &repository.IdentityNotFoundError{})
+	f.users.On("GetByEmail", "sso@example.com").Return(nil, &repository.UserNotFoundError{Email: "sso@example.com"})
+	f.users.On("Create", 1, "SSO User", "sso@example.com", "").Return(created, nil)
+	f.users.On("MarkEmailVerified", 7).Return(nil)
+	f.identities.On("Link", 7, f.idp.URL, "user-1", "sso@example.com").Return(nil)
+
+	result, err := f.login(t)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, result.Token)
+	assert.True(t, result.User.EmailVerified)
+	f.users.AssertExpectations(t)
+	f.identities.AssertExpectations(t)
+}
+
+func TestSSOComplete_ProvisionsOnWrappedNotFoundErrors(t *testing.T) {
+	f := newSSOFixture(t)
+
+	created := &domain.User{ID: 7, OrgID: 1, Name: "SSO User", Email: "sso@example.com", Role: domain.RoleUser}
+	f.identities.On("FindUserID", f.idp.URL, "user-1").Return(0, fmt.Errorf("consulta: %w", &repository.IdentityNotFoundError{}))
+	f.users.On("GetByEmail", "sso@example.com").Return(nil, fmt.Errorf("consulta: %w", &repository.UserNotFoundError{Email: "sso@example.com"}))
+	f.users.On("Create", 1, "SSO User", "sso@example.com", "").Return(created, nil)
+	f.users.On("MarkEmailVerified", 7).Return(nil)
+	f.identities.On("Link", 7, f.idp.URL, "user-1", "sso@example.com").Return(nil)
+
+	_, err := f.login(t)
+
+	assert.NoError(t, err)
+	f.users.AssertExpectations(t)
+}
+
+func TestSSOComplete_LinksExistingUserByEmail(t *testing.T) {
+	f := newSSOFixture(t)
+
+	existing := &domain.User{ID: 3, Name: "Local", Email: "sso@example.com", EmailVerified: true}
+	f.identities.On("FindUserID", f.idp.URL, "user-1").Return(0, &repository.IdentityNotFoundError{})
+	f.users.On("GetByEmail", "sso@example.com").Return(existing, nil)
+	f.identities.On("Link", 3, f.idp.URL, "user-1", "sso@example.com").Return(nil)
+
+	result, err := f.login(t)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.User.ID)
+	f.users.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSSOComplete_UsesLinkedIdentity(t *testing.T) {
+	f := newSSOFixture(t)
+	f.idp.SetUser(oidctest.User{Subject: "user-1", Email: "novo-email@example.com", EmailVerified: true})
+
+	f.identities.On("FindUserID", f.idp.URL, "user-1").Return(3, nil)
+	f.users.On("GetByID", 3).Return(&domain.User{ID: 3, Email: "sso@example.com", EmailVerified: true}, nil)
+	f.identities.On("Link", 3, f.idp.URL, "user-1", "novo-email@example.com").Return(nil)
+
+	result, err := f.login(t)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.User.ID)
+	f.users.AssertNotCalled(t, "GetByEmail", mock.Anything)
+}
+
+func TestSSOComplete_RejectsUnverifiedEmail(t *testing.T) {
+	f := newSSOFixture(t)
+	f.idp.SetUser(oidctest.User{Subject: "user-2", Email: "admin@test.com", EmailVerified: false})
+
+	result, err := f.login(t)
+
+	assert.Nil(t, result)
+	assert.IsType(t, &ForbiddenError{}, err)
+	f.identities.AssertNotCalled(t, "Link", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSSOComplete_StateIsSingleUse(t *testing.T) {
+	f := newSSOFixture(t)
+
+	start, err := f.service.Begin(ctx, "203.0.113.7")
+	require.NoError(t, err)
+	code, state, err := f.idp.Authorize(start.AuthURL)
+	require.NoError(t, err)
+
+	f.identities.On("FindUserID", f.idp.URL, "user-1").Return(3, nil)
+	f.users.On("GetByID", 3).Return(&domain.User{ID: 3, Email: "sso@example.com", EmailVerified: true}, nil)
+	f.identities.On("Link", 3, f.idp.URL, "user-1", "sso@example.com").Return(nil)
+
+	_, err = f.service.Complete(ctx, state, code)
+	require.NoError(t, err)
+
+	result, err := f.service.Complete(ctx, state, code)
+	assert.Nil(t, result)
+	assert.IsType(t, &ValidationError{}, err)
+}
+
+func TestSSOBegin_LimitsPendingLoginsPerIP(t *testing.T) {
+	f := newSSOFixture(t)
+
+	var err error
+	for i := 0; i < 50; i++ {
+		_, err = f.service.Begin(ctx, "203.0.113.7")
+		require.NoError(t, err)
+	}
+
+	_, err = f.service.Begin(ctx, "203.0.113.7")
+	assert.IsType(t, &TooManyAttemptsError{}, err)
+	_, err = f.service.Begin(ctx, "198.51.100.9")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/repository"
+	"github.com/nathaliaoliveira/goapp/internal/totp"
+	"github.com/nathaliaoliveira/goapp/internal/tracing"
+	qrcode "github.com/skip2/go-qrcode"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	DefaultTOTPIssuer = "GoApp"
+
+	recoveryCodeCount = 10
+	// Aceita o código do passo anterior e do seguinte para tolerar relógios dessincronizados.
+	totpSkew = 1
+)
+
+type twoFactorService struct {
+	userRepo     repository.UserRepository
+	twoFactor    repository.TwoFactorRepository
+	settingsRepo repository.SettingsRepository
+	issuer       string
+	now          func() time.Time
+}
+
+func NewTwoFactorService(userRepo repository.UserRepository, twoFactor repository.TwoFactorRepository, settingsRepo repository.SettingsRepository, issuer string) TwoFactorService {
+	if issuer == "" {
+		issuer = DefaultTOTPIssuer
+	}
+	return &twoFactorService{
+		userRepo:     userRepo,
+		twoFactor:    twoFactor,
+		settingsRepo: settingsRepo,
+		issuer:       issuer,
+		now:          time.Now,
+	}
+}
+
+// Setup gera um novo segredo pendente. Ele só passa a valer no login depois de
+// confirmado com Enable.
+func (s *twoFactorService) Setup(ctx context.Context, userID int) (*domain.TwoFactorSetupResponse, error) {
+	ctx, span := tracing.Start(ctx, "TwoFactorService.Setup")
+	defer span.End()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabled {
+		return nil, &ValidationError{Key: "two_factor.already_enabled"}
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, &InternalError{Key: "two_factor.secret_failed", Cause: err}
+	}
+
+	if err := s.twoFactor.SetPendingSecret(ctx, userID, secret); err != nil {
+		return nil, err
+	}
+
+	uri := totp.ProvisioningURI(s.issuer, user.Email, secret)
+	png, err := qrcode.Encode(uri, qrcode.Medium, 256)
+	if err != nil {
+		return nil, &InternalError{Key: "two_factor.qr_code_failed", Cause: err}
+	}
+
+	return &domain.TwoFactorSetupResponse{
+		Secret:     secret,
+		OTPAuthURI: uri,
+		QRCodePNG:  "data:image/png;base64," + base64.StdEncoding.EncodeToString(png),
+	}, nil
+}
+
+func (s *twoFactorService) Enable(ctx context.Context, userID int, code string) (*domain.RecoveryCodesResponse, error) {
+	ctx, span := tracing.Start(ctx, "TwoFactorService.Enable")
+	defer span.End()
+
+	state, err := s.twoFactor.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if state.Enabled {
+		return nil, &ValidationError{Key: "two_factor.already_enabled"}
+	}
+	if state.Secret == "" {
+		return nil, &ValidationError{Key: "two_factor.setup_required"}
+	}
+
+	step, ok := totp.Validate(state.Secret, code, s.now(), totpSkew)
+	if !ok {
+		return nil, &AuthenticationError{Key: "two_factor.invalid_code"}
+	}
+
+	if err := s.twoFactor.Enable(ctx, userID, step); err != nil {
+		return nil, err
+	}
+
+	return s.issueRecoveryCodes(ctx, userID)
+}
+
+func (s *twoFactorService) Disable(ctx context.Context, userID int, password, code string) error {
+	ctx, span := tracing.Start(ctx, "TwoFactorService.Disable")
+	defer span.End()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	required, err := s.IsRequired(ctx, user.OrgID)
+	if err != nil {
+		return err
+	}
+	if required {
+		return &ForbiddenError{Key: "two_factor.required"}
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return &AuthenticationError{Key: "password.incorrect"}
+	}
+
+	if err := s.Verify(ctx, userID, code); err != nil {
+		return err
+	}
+
+	return s.twoFactor.Disable(ctx, userID)
+}
+
+func (s *twoFactorService) RegenerateRecoveryCodes(ctx context.Context, userID int, code string) (*domain.RecoveryCodesResponse, error) {
+	ctx, span := tracing.Start(ctx, "TwoFactorService.RegenerateRecoveryCodes")
+	defer span.End()
+
+	if err := s.verifyTOTP(ctx, userID, code); err != nil {
+		return nil, err
+	}
+	return s.issueRecoveryCodes(ctx, userID)
+}
+
+// Verify aceita um código TOTP ou um código de recuperação ainda não usado.
+func (s *twoFactorService) Verify(ctx context.Context, userID int, code string) error {
+	ctx, span := tracing.Start(ctx, "TwoFactorService.Verify")
+	defer span.End()
+
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return &ValidationError{Key: "two_factor.code_required"}
+	}
+	if len(code) == totp.Digits {
+		return s.verifyTOTP(ctx, userID, code)
+	}
+
+	used, err := s.twoFactor.ConsumeRecoveryCode(ctx, userID, hashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return &InternalError{Key: "two_factor.recovery_code_failed", Cause: err}
+	}
+	if !used {
+		return &AuthenticationError{Key: "two_factor.invalid_code"}
+	}
+	return nil
+}
+
+func (s *twoFactorService) verifyTOTP(ctx context.Context, userID int, code string) error {
+	state, err := s.twoFactor.Get(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !state.Enabled {
+		return &ValidationError{Key: "two_factor.not_active"}
+	}
+
+	step, ok := totp.Validate(state.Secret, code, s.now(), totpSkew)
+	if !ok {
+		return &AuthenticationError{Key: "two_factor.invalid_code"}
+	}
+
+	fresh, err := s.twoFactor.UseStep(ctx, userID, step)
+	if err != nil {
+		return &InternalError{Key: "two_factor.code_validation_failed", Cause: err}
+	}
+	if !fresh {
+		return &AuthenticationError{Key: "two_factor.code_reused"}
+	}
+	return nil
+}
+
+// IsRequired informa se a organização exige 2FA de todos os seus usuários.
+func (s *twoFactorService) IsRequired(ctx context.Context, orgID int) (bool, error) {
+	ctx, span := tracing.Start(ctx, "TwoFactorService.IsRequired")
+	defer span.End()
+
+	value, ok, err := s.settingsRepo.Get(ctx, orgID, domain.SettingRequireTwoFactor)
+	if err != nil {
+		return false, &InternalError{Key: "two_factor.settings_lookup_failed", Cause: err}
+	}
+	return ok && value == "true", nil
+}
+
+// SetRequired altera a exigência de 2FA apenas na organização do administrador.
+func (s *twoFactorService) SetRequired(ctx context.Context, actor domain.Actor, required bool) error {
+	ctx, span := tracing.Start(ctx, "TwoFactorService.SetRequired")
+	defer span.End()
+
+	if !actor.IsAdmin() {
+		return &ForbiddenError{Key: "two_factor.settings_forbidden"}
+	}
+
+	value := "false"
+	if required {
+		value = "true"
+	}
+	if err := s.settingsRepo.Set(ctx, actor.OrgID, domain.SettingRequireTwoFactor, value); err != nil {
+		return &InternalError{Key: "two_factor.settings_save_failed", Cause: err}
+	}
+	return nil
+}
+
+func (s *twoFactorService) issueRecoveryCodes(ctx context.Context, userID int) (*domain.RecoveryCodesResponse, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		raw, err := randomHex(5)
+		if err != nil {
+			return nil, &InternalError{Key: "two_factor.recovery_codes_failed", Cause: err}
+		}
+		codes[i] = raw[:5] + "-" + raw[5:]
+		hashes[i] = hashToken(raw)
+	}
+
+	if err := s.twoFactor.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, &InternalError{Key: "two_factor.recovery_codes_save_failed", Cause: err}
+	}
+
+	return &domain.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
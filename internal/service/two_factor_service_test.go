@@ -0,0 +1,325 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/repository"
+	"github.com/nathaliaoliveira/goapp/internal/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockTwoFactorRepository struct {
+	mock.Mock
+}
+
+func (m *MockTwoFactorRepository) Get(ctx context.Context, userID int) (*domain.TwoFactorState, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.TwoFactorState), args.Error(1)
+}
+
+func (m *MockTwoFactorRepository) SetPendingSecret(ctx context.Context, userID int, secret string) error {
+	args := m.Called(userID, secret)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepository) Enable(ctx context.Context, userID int, step int64) error {
+	args := m.Called(userID, step)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepository) Disable(ctx context.Context, userID int) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepository) UseStep(ctx context.Context, userID int, step int64) (bool, error) {
+	args := m.Called(userID, step)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID int, codeHashes []string) error {
+	args := m.Called(userID, codeHashes)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepository) ConsumeRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error) {
+	args := m.Called(userID, codeHash)
+	return args.Bool(0), args.Error(1)
+}
+
+type MockSettingsRepository struct {
+	mock.Mock
+}
+
+func (m *MockSettingsRepository) Get(ctx context.Context, orgID int, key string) (string, bool, error) {
+	args := m.Called(orgID, key)
+	return args.String(0), args.Bool(1), args.Error(2)
+}
+
+func (m *MockSettingsRepository) Set(ctx context.Context, orgID int, key, value string) error {
+	args := m.Called(orgID, key, value)
+	return args.Error(0)
+}
+
+// memorySettings guarda as configurações por organização, como a tabela
+// app_settings.
+type memorySettings map[string]string
+
+func (m memorySettings) Get(ctx context.Context, orgID int, key string) (string, bool, error) {
+	value, ok := m[fmt.Sprint(orgID, ":", key)]
+	return value, ok, nil
+}
+
+func (m memorySettings) Set(ctx context.Context, orgID int, key, value string) error {
+	m[fmt.Sprint(orgID, ":", key)] = value
+	return nil
+}
+
+const testTOTPSecret = "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
+
+var testNow = time.Unix(1700000000, 0)
+
+func newTestTwoFactorService(users *MockUserRepository, repo *MockTwoFactorRepository, settings *MockSettingsRepository) *twoFactorService {
+	s := NewTwoFactorService(users, repo, settings, "GoApp").(*twoFactorService)
+	s.now = func() time.Time { return testNow }
+	return s
+}
+
+func currentCode(t *testing.T) string {
+	code, err := totp.Code(testTOTPSecret, totp.Step(testNow))
+	assert.NoError(t, err)
+	return code
+}
+
+func TestTwoFactorSetup_ReturnsProvisioningData(t *testing.T) {
+	mockUsers := new(MockUserRepository)
+	mockRepo := new(MockTwoFactorRepository)
+	service := newTestTwoFactorService(mockUsers, mockRepo, new(MockSettingsRepository))
+
+	mockUsers.On("GetByID", 1).Return(&domain.User{ID: 1, Email: "test@example.com"}, nil)
+	mockRepo.On("SetPendingSecret", 1, mock.AnythingOfType("string")).Return(nil)
+
+	result, err := service.Setup(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, mockRepo.Calls[0].Arguments.String(1), result.Secret)
+	assert.True(t, strings.HasPrefix(result.OTPAuthURI, "otpauth://totp/GoApp:test@example.com?"))
+	assert.True(t, strings.HasPrefix(result.QRCodePNG, "data:image/png;base64,"))
+}
+
+func TestTwoFactorEnable_ValidCodeIssuesRecoveryCodes(t *testing.T) {
+	mockRepo := new(MockTwoFactorRepository)
+	service := newTestTwoFactorService(new(MockUserRepository), mockRepo, new(MockSettingsRepository))
+
+	mockRepo.On("Get", 1).Return(&domain.TwoFactorState{Secret: testTOTPSecret}, nil)
+	mockRepo.On("Enable", 1, totp.Step(testNow)).Return(nil)
+	mockRepo.On("ReplaceRecoveryCodes", 1, mock.AnythingOfType("[]string")).Return(nil)
+
+	result, err := service.Enable(ctx, 1, currentCode(t))
+
+	assert.NoError(t, err)
+	assert.Len(t, result.RecoveryCodes, recoveryCodeCount)
+	assert.Regexp(t, "^[0-9a-f]{5}-[0-9a-f]{5}$", result.RecoveryCodes[0])
+
+	hashes := mockRepo.Calls[2].Arguments.Get(1).([]string)
+	assert.Equal(t, hashToken(normalizeRecoveryCode(result.RecoveryCodes[0])), hashes[0])
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTwoFactorEnable_WrongCode(t *testing.T) {
+	mockRepo := new(MockTwoFactorRepository)
+	service := newTestTwoFactorService(new(MockUserRepository), mockRepo, new(MockSettingsRepository))
+
+	mockRepo.On("Get", 1).Return(&domain.TwoFactorState{Secret: testTOTPSecret}, nil)
+
+	result, err := service.Enable(ctx, 1, "000000")
+
+	assert.Nil(t, result)
+	assert.IsType(t, &AuthenticationError{}, err)
+	mockRepo.AssertNotCalled(t, "Enable", mock.Anything, mock.Anything)
+}
+
+func TestTwoFactorVerify_RejectsReusedCode(t *testing.T) {
+	mockRepo := new(MockTwoFactorRepository)
+	service := newTestTwoFactorService(new(MockUserRepository), mockRepo, new(MockSettingsRepository))
+
+	mockRepo.On("Get", 1).Return(&domain.TwoFactorState{Secret: testTOTPSecret, Enabled: true}, nil)
+	mockRepo.On("UseStep", 1, totp.Step(testNow)).Return(true, nil).Once()
+	mockRepo.On("UseStep", 1, totp.Step(testNow)).Return(false, nil).Once()
+
+	assert.NoError(t, service.Verify(ctx, 1, currentCode(t)))
+
+	err := service.Verify(ctx, 1, currentCode(t))
+	assert.IsType(t, &AuthenticationError{}, err)
+}
+
+func TestTwoFactorVerify_AcceptsRecoveryCode(t *testing.T) {
+	mockRepo := new(MockTwoFactorRepository)
+	service := newTestTwoFactorService(new(MockUserRepository), mockRepo, new(MockSettingsRepository))
+
+	mockRepo.On("ConsumeRecoveryCode", 1, hashToken("abcde12345")).Return(true, nil)
+
+	assert.NoError(t, service.Verify(ctx, 1, "ABCDE-12345"))
+}
+
+func TestTwoFactorDisable_ForbiddenWhenRequired(t *testing.T) {
+	mockRepo := new(MockTwoFactorRepository)
+	mockUsers := new(MockUserRepository)
+	mockSettings := new(MockSettingsRepository)
+	service := newTestTwoFactorService(mockUsers, mockRepo, mockSettings)
+
+	mockUsers.On("GetByID", 1).Return(&domain.User{ID: 1, OrgID: 4, PasswordHash: testPasswordHash}, nil)
+	mockSettings.On("Get", 4, domain.SettingRequireTwoFactor).Return("true", true, nil)
+
+	err := service.Disable(ctx, 1, "password", currentCode(t))
+
+	assert.IsType(t, &ForbiddenError{}, err)
+	mockRepo.AssertNotCalled(t, "Disable", mock.Anything)
+}
+
+func TestTwoFactorSetRequired_AdminOnly(t *testing.T) {
+	mockSettings := new(MockSettingsRepository)
+	service := newTestTwoFactorService(new(MockUserRepository), new(MockTwoFactorRepository), mockSettings)
+
+	err := service.SetRequired(ctx, domain.Actor{UserID: 2, Role: domain.RoleUser}, true)
+	assert.IsType(t, &ForbiddenError{}, err)
+
+	mockSettings.On("Set", 3, domain.SettingRequireTwoFactor, "true").Return(nil)
+	assert.NoError(t, service.SetRequired(ctx, domain.Actor{UserID: 1, OrgID: 3, Role: domain.RoleAdmin}, true))
+	mockSettings.AssertExpectations(t)
+}
+
+func TestTwoFactorSetRequired_OnlyAffectsActorOrganization(t *testing.T) {
+	service := NewTwoFactorService(new(MockUserRepository), new(MockTwoFactorRepository), memorySettings{}, "GoApp")
+	adminA := domain.Actor{UserID: 1, OrgID: 1, Role: domain.RoleAdmin}
+
+	require.NoError(t, service.SetRequired(ctx, adminA, true))
+
+	required, err := service.IsRequired(ctx, 1)
+	require.NoError(t, err)
+	assert.True(t, required)
+	required, err = service.IsRequired(ctx, 2)
+	require.NoError(t, err)
+	assert.False(t, required, "a organização B não é afetada")
+}
+
+const testPasswordHash = "$2a$10$92IXUNpkjO0rOQ5byMi.Ye4oKoEa3Ro9llC/.og/at2.uheWG/igi"
+
+func TestLogin_TwoFactorReturnsChallenge(t *testing.T) {
+	mockUsers := new(MockUserRepository)
+	mockRepo := new(MockTwoFactorRepository)
+	twoFactor := newTestTwoFactorService(mockUsers, mockRepo, new(MockSettingsRepository))
+	service := NewUserService(mockUsers, []byte("test-secret"), WithTwoFactor(twoFactor))
+
+	user := &domain.User{ID: 1, Email: "test@example.com", PasswordHash: testPasswordHash, TwoFactorEnabled: true}
+	mockUsers.On("GetByEmail", "test@example.com").Return(user, nil)
+	mockUsers.On("GetByID", 1).Return(user, nil)
+	mockRepo.On("Get", 1).Return(&domain.TwoFactorState{Secret: testTOTPSecret, Enabled: true}, nil)
+	mockRepo.On("UseStep", 1, totp.Step(testNow)).Return(true, nil)
+
+	challenge, err := service.Login(ctx, "test@example.com", "password", "203.0.113.7")
+
+	assert.NoError(t, err)
+	assert.True(t, challenge.MFARequired)
+	assert.Empty(t, challenge.Token)
+	assert.Nil(t, challenge.User)
+
+	result, err := service.CompleteMFALogin(ctx, challenge.MFAToken, currentCode(t), "203.0.113.7")
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, result.Token)
+	assert.Equal(t, "test@example.com", result.User.Email)
+
+	_, err = ParsePurposeToken([]byte("test-secret"), result.Token, domain.TokenPurposeMFAChallenge)
+	assert.Error(t, err)
+}
+
+func TestCompleteMFALogin_WrongCodesCountAcrossNewLogins(t *testing.T) {
+	mockUsers := new(MockUserRepository)
+	mockRepo := new(MockTwoFactorRepository)
+	twoFactor := newTestTwoFactorService(mockUsers, mockRepo, new(MockSettingsRepository))
+	guard := NewLoginGuard(repository.NewMemoryLoginAttemptStore(time.Hour), DefaultLoginGuardConfig())
+	service := NewUserService(mockUsers, []byte("test-secret"), WithTwoFactor(twoFactor), WithLoginGuard(guard))
+
+	user := &domain.User{ID: 1, Email: "test@example.com", PasswordHash: testPasswordHash, TwoFactorEnabled: true}
+	mockUsers.On("GetByEmail", "test@example.com").Return(user, nil)
+	mockRepo.On("ConsumeRecoveryCode", 1, mock.Anything).Return(false, nil)
+
+	challenge, err := service.Login(ctx, "test@example.com", "password", "203.0.113.7")
+	assert.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		_, err = service.CompleteMFALogin(ctx, challenge.MFAToken, "codigo-errado", "203.0.113.7")
+		assert.IsType(t, &AuthenticationError{}, err)
+	}
+
+	// Logar de novo com a senha não zera as falhas dos códigos.
+	challenge, err = service.Login(ctx, "test@example.com", "password", "198.51.100.9")
+	assert.NoError(t, err)
+	_, err = service.CompleteMFALogin(ctx, challenge.MFAToken, "codigo-errado", "198.51.100.9")
+	assert.IsType(t, &AuthenticationError{}, err)
+
+	_, err = service.CompleteMFALogin(ctx, challenge.MFAToken, currentCode(t), "198.51.100.9")
+	assert.IsType(t, &TooManyAttemptsError{}, err)
+}
+
+func TestCompleteMFALogin_RejectsSessionToken(t *testing.T) {
+	mockUsers := new(MockUserRepository)
+	twoFactor := newTestTwoFactorService(mockUsers, new(MockTwoFactorRepository), new(MockSettingsRepository))
+	service := NewUserService(mockUsers, []byte("test-secret"), WithTwoFactor(twoFactor)).(*userService)
+
+	session, err := service.generateJWT(&domain.User{ID: 1, Email: "test@example.com"})
+	assert.NoError(t, err)
+
+	result, err := service.CompleteMFALogin(ctx, session, currentCode(t), "203.0.113.7")
+
+	assert.Nil(t, result)
+	assert.IsType(t, &AuthenticationError{}, err)
+}
+
+func TestLogin_RequiredTwoFactorReturnsEnrollmentToken(t *testing.T) {
+	mockUsers := new(MockUserRepository)
+	mockSettings := new(MockSettingsRepository)
+	twoFactor := newTestTwoFactorService(mockUsers, new(MockTwoFactorRepository), mockSettings)
+	service := NewUserService(mockUsers, []byte("test-secret"), WithTwoFactor(twoFactor))
+
+	mockUsers.On("GetByEmail", "test@example.com").Return(&domain.User{ID: 1, OrgID: 2, Email: "test@example.com", PasswordHash: testPasswordHash}, nil)
+	mockSettings.On("Get", 2, domain.SettingRequireTwoFactor).Return("true", true, nil)
+
+	result, err := service.Login(ctx, "test@example.com", "password", "203.0.113.7")
+
+	assert.NoError(t, err)
+	assert.True(t, result.EnrollmentRequired)
+	assert.Empty(t, result.Token)
+
+	_, err = ParsePurposeToken([]byte("test-secret"), result.MFAToken, domain.TokenPurposeMFAEnrollment)
+	assert.NoError(t, err)
+}
+
+func TestParseSessionToken_RejectsRestrictedTokens(t *testing.T) {
+	service := NewUserService(new(MockUserRepository), []byte("test-secret")).(*userService)
+	user := &domain.User{ID: 1, OrgID: 3, Email: "test@example.com", Role: domain.RoleAdmin}
+
+	session, err := service.generateJWT(user)
+	assert.NoError(t, err)
+	actor, err := ParseSessionToken([]byte("test-secret"), session)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Actor{UserID: 1, OrgID: 3, Role: domain.RoleAdmin}, actor)
+
+	_, err = ParseSessionToken([]byte("outro-secret"), session)
+	assert.IsType(t, &AuthenticationError{}, err)
+
+	challenge, err := service.mfaResponse(user, domain.TokenPurposeMFAChallenge, time.Minute)
+	assert.NoError(t, err)
+	_, err = ParseSessionToken([]byte("test-secret"), challenge.MFAToken)
+	assert.IsType(t, &AuthenticationError{}, err)
+}
@@ -1,37 +1,101 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"net/mail"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/i18n"
 	"github.com/nathaliaoliveira/goapp/internal/repository"
+	"github.com/nathaliaoliveira/goapp/internal/tracing"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type userService struct {
     userRepo   repository.UserRepository
     jwtSecret  []byte
+    loginGuard *LoginGuard
+    policy     *PasswordPolicy
+    twoFactor  TwoFactorService
 }
 
-func NewUserService(userRepo repository.UserRepository, jwtSecret []byte) UserService {
-    return &userService{
+type UserServiceOption func(*userService)
+
+// WithLoginGuard ativa o controle de tentativas de login por conta e por IP.
+func WithLoginGuard(guard *LoginGuard) UserServiceOption {
+    return func(s *userService) {
+        s.loginGuard = guard
+    }
+}
+
+// WithPasswordPolicy substitui a política de senhas padrão.
+func WithPasswordPolicy(policy *PasswordPolicy) UserServiceOption {
+    return func(s *userService) {
+        s.policy = policy
+    }
+}
+
+// WithTwoFactor ativa o login em duas etapas para contas com TOTP.
+func WithTwoFactor(twoFactor TwoFactorService) UserServiceOption {
+    return func(s *userService) {
+        s.twoFactor = twoFactor
+    }
+}
+
+const (
+    sessionTTL       = 24 * time.Hour
+    mfaChallengeTTL  = 5 * time.Minute
+    mfaEnrollmentTTL = 15 * time.Minute
+    // maxSessionAge limita as renovações: depois disso é preciso fazer login
+    // de novo, mesmo com o token ainda válido.
+    maxSessionAge = 30 * 24 * time.Hour
+)
+
+func NewUserService(userRepo repository.UserRepository, jwtSecret []byte, opts ...UserServiceOption) UserService {
+    s := &userService{
         userRepo:  userRepo,
         jwtSecret: jwtSecret,
+        policy:    DefaultPasswordPolicy(),
     }
+    for _, opt := range opts {
+        opt(s)
+    }
+    return s
 }
 
-func (s *userService) Register(name, email, password string) (*domain.User, error) {
+// dummyPasswordHash é comparado quando o email não existe, para que a resposta
+// leve o mesmo tempo de um login com senha errada.
+var dummyPasswordHash = []byte("$2a$10$5..HUFKNwhcTePBAHhVAIeb54dLHyvmEHj5ayB5leWrKepkFYIS/u")
+
+// Register cria uma nova organização com o usuário como administrador. Sem
+// nome de organização, usa o nome do usuário.
+func (s *userService) Register(ctx context.Context, orgName, name, email, password string) (*domain.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.Register")
+	defer span.End()
+
 	if name == "" || email == "" || password == "" {
-		return nil, &ValidationError{Message: "Nome, email e senha são obrigatórios"}
+		return nil, &ValidationError{Key: "user.name_email_password_required"}
+	}
+	
+	orgName = strings.TrimSpace(orgName)
+	if orgName == "" {
+		orgName = name
+	}
+	
+	if err := s.policy.check(password, email, name); err != nil {
+		return nil, err
 	}
 	
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
-		return nil, &InternalError{Message: "Erro ao processar senha", Cause: err}
+		return nil, &InternalError{Key: "password.hash_failed", Cause: err}
 	}
 	
-	user, err := s.userRepo.Create(name, email, string(hashedPassword))
+	user, err := s.userRepo.CreateWithOrganization(ctx, orgName, name, email, string(hashedPassword))
 	if err != nil {
 		return nil, err
 	}
@@ -39,29 +103,269 @@ func (s *userService) Register(name, email, password string) (*domain.User, erro
 	return user, nil
 }
 
-func (s *userService) Login(email, password string) (*domain.AuthResponse, error) {
-	user, err := s.userRepo.GetByEmail(email)
+func (s *userService) Login(ctx context.Context, email, password, clientIP string) (*domain.AuthResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserService.Login")
+	defer span.End()
+
+	if s.loginGuard != nil {
+		if err := s.loginGuard.Check(email, clientIP); err != nil {
+			return nil, err
+		}
+	}
+	
+	user, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
-		return nil, &AuthenticationError{Message: "Credenciais inválidas"}
+		bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+		return nil, &AuthenticationError{Key: "auth.invalid_credentials"}
 	}
 	
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
-		return nil, &AuthenticationError{Message: "Credenciais inválidas"}
+		return nil, &AuthenticationError{Key: "auth.invalid_credentials"}
 	}
 	
+	response, err := s.StartSession(ctx, user)
+	if err != nil {
+		s.releaseLoginAttempt(email, clientIP)
+		return nil, err
+	}
+	
+	// Com 2FA o contador da conta só é zerado em CompleteMFALogin: quem sabe
+	// apenas a senha não pode zerá-lo logando de novo entre códigos errados.
+	if s.loginGuard != nil {
+		if response.Token != "" {
+			s.loginGuard.RecordSuccess(email, clientIP)
+		} else {
+			s.loginGuard.Release(email, clientIP)
+		}
+	}
+	
+	return response, nil
+}
+
+// StartSession emite a sessão de um usuário já autenticado pelo primeiro fator
+// (senha ou SSO), exigindo a segunda etapa quando a conta ou a política pedem 2FA.
+func (s *userService) StartSession(ctx context.Context, user *domain.User) (*domain.AuthResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserService.StartSession")
+	defer span.End()
+
+	if s.twoFactor != nil {
+		if user.TwoFactorEnabled {
+			return s.mfaResponse(user, domain.TokenPurposeMFAChallenge, mfaChallengeTTL)
+		}
+		
+		required, err := s.twoFactor.IsRequired(ctx, user.OrgID)
+		if err != nil {
+			return nil, err
+		}
+		if required {
+			return s.mfaResponse(user, domain.TokenPurposeMFAEnrollment, mfaEnrollmentTTL)
+		}
+	}
+	
+	return s.sessionResponse(user)
+}
+
+// CompleteMFALogin troca o token de desafio emitido pelo Login por um token de
+// sessão. Códigos errados contam como falhas de login da conta.
+func (s *userService) CompleteMFALogin(ctx context.Context, mfaToken, code, clientIP string) (*domain.AuthResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserService.CompleteMFALogin")
+	defer span.End()
+
+	if s.twoFactor == nil {
+		return nil, &ValidationError{Key: "two_factor.not_enabled"}
+	}
+	if mfaToken == "" || code == "" {
+		return nil, &ValidationError{Key: "two_factor.token_and_code_required"}
+	}
+	
+	claims, err := ParsePurposeToken(s.jwtSecret, mfaToken, domain.TokenPurposeMFAChallenge)
+	if err != nil {
+		return nil, &AuthenticationError{Key: "two_factor.challenge_invalid"}
+	}
+	
+	email, _ := claims["email"].(string)
+	if s.loginGuard != nil {
+		if err := s.loginGuard.Check(email, clientIP); err != nil {
+			return nil, err
+		}
+	}
+	
+	userID := int(claims["user_id"].(float64))
+	if err := s.twoFactor.Verify(ctx, userID, code); err != nil {
+		// Código errado mantém a reserva de Check como falha.
+		if _, ok := err.(*AuthenticationError); !ok {
+			s.releaseLoginAttempt(email, clientIP)
+		}
+		return nil, err
+	}
+	
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		s.releaseLoginAttempt(email, clientIP)
+		return nil, err
+	}
+	
+	if s.loginGuard != nil {
+		s.loginGuard.RecordSuccess(email, clientIP)
+	}
+	
+	return s.sessionResponse(user)
+}
+
+// RefreshSession emite um token novo para uma sessão ainda válida, sem pedir a
+// senha de novo. authTime é o momento do login que originou a sessão e não muda
+// nas renovações, para que elas não passem de maxSessionAge.
+func (s *userService) RefreshSession(ctx context.Context, userID int, authTime time.Time) (*domain.AuthResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserService.RefreshSession")
+	defer span.End()
+
+	if authTime.IsZero() || time.Since(authTime) > maxSessionAge {
+		return nil, &AuthenticationError{Key: "auth.session_expired"}
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		var notFound *repository.UserNotFoundError
+		if errors.As(err, &notFound) {
+			return nil, &AuthenticationError{Key: "auth.token_invalid"}
+		}
+		return nil, err
+	}
+
+	token, err := s.signSession(user, authTime)
+	if err != nil {
+		return nil, &InternalError{Key: "token.generate_failed", Cause: err}
+	}
+	return &domain.AuthResponse{Token: token, User: user}, nil
+}
+
+func (s *userService) sessionResponse(user *domain.User) (*domain.AuthResponse, error) {
 	token, err := s.generateJWT(user)
 	if err != nil {
-		return nil, &InternalError{Message: "Erro ao gerar token", Cause: err}
+		return nil, &InternalError{Key: "token.generate_failed", Cause: err}
 	}
 	
 	return &domain.AuthResponse{
 		Token: token,
-		User:  *user,
+		User:  user,
 	}, nil
 }
 
-func (s *userService) GetByID(id int) (*domain.User, error) {
-	user, err := s.userRepo.GetByID(id)
+func (s *userService) mfaResponse(user *domain.User, purpose string, ttl time.Duration) (*domain.AuthResponse, error) {
+	claims := jwt.MapClaims{
+		"user_id": user.ID,
+		"org_id":  user.OrgID,
+		"email":   user.Email,
+		"role":    user.Role,
+		"purpose": purpose,
+		"exp":     time.Now().Add(ttl).Unix(),
+		"iat":     time.Now().Unix(),
+	}
+	
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.jwtSecret)
+	if err != nil {
+		return nil, &InternalError{Key: "token.generate_failed", Cause: err}
+	}
+	
+	return &domain.AuthResponse{
+		MFARequired:        purpose == domain.TokenPurposeMFAChallenge,
+		EnrollmentRequired: purpose == domain.TokenPurposeMFAEnrollment,
+		MFAToken:           token,
+	}, nil
+}
+
+// ParsePurposeToken valida um JWT emitido para um propósito específico. Tokens
+// de sessão não têm o claim "purpose" e são recusados aqui.
+func ParsePurposeToken(jwtSecret []byte, tokenString, purpose string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return jwtSecret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil || !token.Valid {
+		return nil, &AuthenticationError{Key: "auth.token_invalid"}
+	}
+	if p, _ := claims["purpose"].(string); p != purpose {
+		return nil, &AuthenticationError{Key: "auth.token_invalid"}
+	}
+	if _, ok := claims["user_id"].(float64); !ok {
+		return nil, &AuthenticationError{Key: "auth.token_invalid"}
+	}
+	return claims, nil
+}
+
+// ParseSessionToken valida um JWT de sessão, emitido no login, e devolve quem
+// o usa. Tokens de propósito restrito (2FA) e sem organização são recusados.
+func ParseSessionToken(jwtSecret []byte, tokenString string) (domain.Actor, error) {
+	claims, err := ParsePurposeToken(jwtSecret, tokenString, "")
+	if err != nil {
+		return domain.Actor{}, err
+	}
+	orgID, ok := claims["org_id"].(float64)
+	if !ok {
+		return domain.Actor{}, &AuthenticationError{Key: "auth.token_invalid"}
+	}
+	role, _ := claims["role"].(string)
+	return domain.Actor{UserID: int(claims["user_id"].(float64)), OrgID: int(orgID), Role: role}, nil
+}
+
+// CheckSession confere se o dono de um token de sessão ainda existe: os JWTs
+// não são revogáveis, então usuários removidos são recusados aqui.
+func (s *userService) CheckSession(ctx context.Context, userID int) error {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		var notFound *repository.UserNotFoundError
+		if errors.As(err, &notFound) {
+			return &AuthenticationError{Key: "auth.token_invalid"}
+		}
+		return err
+	}
+	return nil
+}
+
+// releaseLoginAttempt desfaz a reserva feita por LoginGuard.Check quando a
+// tentativa falhou por outro motivo que não a credencial.
+func (s *userService) releaseLoginAttempt(email, clientIP string) {
+	if s.loginGuard != nil {
+		s.loginGuard.Release(email, clientIP)
+	}
+}
+
+func (s *userService) UnlockLogin(ctx context.Context, actor domain.Actor, id int) error {
+	ctx, span := tracing.Start(ctx, "UserService.UnlockLogin")
+	defer span.End()
+
+	user, err := s.getInOrg(ctx, actor.OrgID, id)
+	if err != nil {
+		return err
+	}
+	
+	if s.loginGuard == nil {
+		return nil
+	}
+	
+	if err := s.loginGuard.Unlock(user.Email); err != nil {
+		return &InternalError{Key: "user.unlock_failed", Cause: err}
+	}
+	
+	return nil
+}
+
+// getInOrg esconde usuários de outras organizações como se não existissem.
+func (s *userService) getInOrg(ctx context.Context, orgID, id int) (*domain.User, error) {
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user.OrgID != orgID {
+		return nil, &repository.UserNotFoundError{ID: id}
+	}
+	return user, nil
+}
+
+func (s *userService) GetByID(ctx context.Context, id int) (*domain.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetByID")
+	defer span.End()
+
+	user, err := s.userRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -69,26 +373,158 @@ func (s *userService) GetByID(id int) (*domain.User, error) {
 	return user, nil
 }
 
-func (s *userService) GetAll() ([]domain.User, error) {
-	users, err := s.userRepo.GetAll()
+const (
+	defaultUserPageSize = 20
+	maxUserPageSize     = 100
+)
+
+func (s *userService) List(ctx context.Context, actor domain.Actor, params domain.UserListParams) (*domain.UserListResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserService.List")
+	defer span.End()
+
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.PageSize < 1 {
+		params.PageSize = defaultUserPageSize
+	}
+	if params.PageSize > maxUserPageSize {
+		params.PageSize = maxUserPageSize
+	}
+	params.Search = strings.TrimSpace(params.Search)
+	
+	users, total, err := s.userRepo.List(ctx, actor.OrgID, params)
 	if err != nil {
 		return nil, err
 	}
 	
-	return users, nil
+	return &domain.UserListResponse{
+		Users:      users,
+		Page:       params.Page,
+		PageSize:   params.PageSize,
+		Total:      total,
+		TotalPages: (total + params.PageSize - 1) / params.PageSize,
+	}, nil
 }
 
-func (s *userService) Create(name, email, password string) (*domain.User, error) {
+func (s *userService) Update(ctx context.Context, actor domain.Actor, id int, req domain.UpdateUserRequest) (*domain.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.Update")
+	defer span.End()
+
+	if !actor.IsAdmin() && actor.UserID != id {
+		return nil, &ForbiddenError{Key: "user.update_forbidden"}
+	}
+	
+	if req.Role != nil && !actor.IsAdmin() {
+		return nil, &ForbiddenError{Key: "user.role_change_forbidden"}
+	}
+	
+	user, err := s.getInOrg(ctx, actor.OrgID, id)
+	if err != nil {
+		return nil, err
+	}
+	
+	name, email, role := user.Name, user.Email, user.Role
+	if req.Name != nil {
+		name = strings.TrimSpace(*req.Name)
+	}
+	if req.Email != nil {
+		email = strings.TrimSpace(*req.Email)
+	}
+	if req.Role != nil {
+		role = *req.Role
+	}
+	
+	if name == "" || email == "" {
+		return nil, &ValidationError{Key: "user.name_email_required"}
+	}
+	if _, err := mail.ParseAddress(email); err != nil {
+		return nil, &ValidationError{Key: "user.invalid_email"}
+	}
+	if role != domain.RoleAdmin && role != domain.RoleUser {
+		return nil, &ValidationError{Key: "user.invalid_role", Args: []interface{}{role}}
+	}
+	if actor.UserID == id && user.Role == domain.RoleAdmin && role != domain.RoleAdmin {
+		return nil, &ValidationError{Key: "user.cannot_demote_self"}
+	}
+	
+	return s.userRepo.Update(ctx, actor.OrgID, id, name, email, role)
+}
+
+func (s *userService) Delete(ctx context.Context, actor domain.Actor, id int) error {
+	ctx, span := tracing.Start(ctx, "UserService.Delete")
+	defer span.End()
+
+	if !actor.IsAdmin() {
+		return &ForbiddenError{Key: "user.delete_forbidden"}
+	}
+	
+	if actor.UserID == id {
+		return &ValidationError{Key: "user.cannot_delete_self"}
+	}
+	
+	return s.userRepo.SoftDelete(ctx, actor.OrgID, id)
+}
+
+func (s *userService) ChangePassword(ctx context.Context, userID int, currentPassword, newPassword string) error {
+	ctx, span := tracing.Start(ctx, "UserService.ChangePassword")
+	defer span.End()
+
+	if currentPassword == "" || newPassword == "" {
+		return &ValidationError{Key: "password.current_and_new_required"}
+	}
+	
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	
+	if s.loginGuard != nil {
+		if err := s.loginGuard.Check(user.Email, ""); err != nil {
+			return err
+		}
+	}
+	
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(currentPassword)); err != nil {
+		return &AuthenticationError{Key: "password.current_incorrect"}
+	}
+	s.releaseLoginAttempt(user.Email, "")
+	
+	if err := s.policy.check(newPassword, user.Email, user.Name); err != nil {
+		return err
+	}
+	
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return &InternalError{Key: "password.hash_failed", Cause: err}
+	}
+	
+	return s.userRepo.UpdatePassword(ctx, userID, string(hashedPassword))
+}
+
+// Create adiciona um usuário à organização do administrador que faz a chamada.
+func (s *userService) Create(ctx context.Context, actor domain.Actor, name, email, password string) (*domain.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.Create")
+	defer span.End()
+
+	if !actor.IsAdmin() {
+		return nil, &ForbiddenError{Key: "user.create_forbidden"}
+	}
+	
 	if name == "" || email == "" || password == "" {
-		return nil, &ValidationError{Message: "Nome, email e senha são obrigatórios"}
+		return nil, &ValidationError{Key: "user.name_email_password_required"}
+	}
+	
+	if err := s.policy.check(password, email, name); err != nil {
+		return nil, err
 	}
 	
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
-		return nil, &InternalError{Message: "Erro ao criptografar senha", Cause: err}
+		return nil, &InternalError{Key: "password.hash_failed", Cause: err}
 	}
 	
-	user, err := s.userRepo.Create(name, email, string(hashedPassword))
+	user, err := s.userRepo.Create(ctx, actor.OrgID, name, email, string(hashedPassword))
 	if err != nil {
 		return nil, err
 	}
@@ -97,41 +533,79 @@ func (s *userService) Create(name, email, password string) (*domain.User, error)
 }
 
 func (s *userService) generateJWT(user *domain.User) (string, error) {
+    return s.signSession(user, time.Now())
+}
+
+// signSession guarda em auth_time o login que originou a sessão.
+func (s *userService) signSession(user *domain.User, authTime time.Time) (string, error) {
     claims := jwt.MapClaims{
-        "user_id": user.ID,
-        "email":   user.Email,
-        "exp":     time.Now().Add(24 * time.Hour).Unix(), // 24 horas
-        "iat":     time.Now().Unix(),
+        "user_id":   user.ID,
+        "org_id":    user.OrgID,
+        "email":     user.Email,
+        "role":      user.Role,
+        "auth_time": authTime.Unix(),
+        "exp":       time.Now().Add(sessionTTL).Unix(),
+        "iat":       time.Now().Unix(),
     }
     
     token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
     return token.SignedString(s.jwtSecret)
 }
 
+// Os erros de serviço guardam a chave da mensagem no catálogo i18n; o handler
+// a traduz para o idioma da requisição e Error() usa o idioma padrão.
 type ValidationError struct {
-    Message string
+    Key     string
+    Args    []interface{}
+    Details []ValidationDetail
 }
 
+// ValidationDetail é o erro de um campo, devolvido em Problem.Errors.
+type ValidationDetail = domain.FieldError
+
 func (e *ValidationError) Error() string {
-    return e.Message
+    return i18n.T(i18n.DefaultLanguage, e.Key, e.Args...)
+}
+
+type ForbiddenError struct {
+    Key  string
+    Args []interface{}
+}
+
+func (e *ForbiddenError) Error() string {
+    return i18n.T(i18n.DefaultLanguage, e.Key, e.Args...)
 }
 
 type AuthenticationError struct {
-    Message string
+    Key  string
+    Args []interface{}
 }
 
 func (e *AuthenticationError) Error() string {
-    return e.Message
+    return i18n.T(i18n.DefaultLanguage, e.Key, e.Args...)
 }
 
 type InternalError struct {
-    Message string
-    Cause   error
+    Key   string
+    Cause error
 }
 
 func (e *InternalError) Error() string {
+    message := i18n.T(i18n.DefaultLanguage, e.Key)
     if e.Cause != nil {
-        return e.Message + ": " + e.Cause.Error()
+        return message + ": " + e.Cause.Error()
     }
-    return e.Message
-} 
\ No newline at end of file
+    return message
+}
+
+func (e *InternalError) Unwrap() error {
+    return e.Cause
+}
+
+type TooManyAttemptsError struct {
+    RetryAfter time.Duration
+}
+
+func (e *TooManyAttemptsError) Error() string {
+    return i18n.T(i18n.DefaultLanguage, "auth.too_many_attempts")
+}
@@ -1,26 +1,42 @@
 package service
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/nathaliaoliveira/goapp/internal/domain"
+	"github.com/nathaliaoliveira/goapp/internal/repository"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
+// ctx é o contexto usado nas chamadas aos serviços durante os testes.
+var ctx = context.Background()
+
 type MockUserRepository struct {
 	mock.Mock
 }
 
-func (m *MockUserRepository) Create(name, email, passwordHash string) (*domain.User, error) {
-	args := m.Called(name, email, passwordHash)
+func (m *MockUserRepository) Create(ctx context.Context, orgID int, name, email, passwordHash string) (*domain.User, error) {
+	args := m.Called(orgID, name, email, passwordHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+func (m *MockUserRepository) CreateWithOrganization(ctx context.Context, orgName, name, email, passwordHash string) (*domain.User, error) {
+	args := m.Called(orgName, name, email, passwordHash)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.User), args.Error(1)
 }
 
-func (m *MockUserRepository) GetByEmail(email string) (*domain.User, error) {
+func (m *MockUserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
 	args := m.Called(email)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -28,7 +44,7 @@ func (m *MockUserRepository) GetByEmail(email string) (*domain.User, error) {
 	return args.Get(0).(*domain.User), args.Error(1)
 }
 
-func (m *MockUserRepository) GetByID(id int) (*domain.User, error) {
+func (m *MockUserRepository) GetByID(ctx context.Context, id int) (*domain.User, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -36,12 +52,35 @@ func (m *MockUserRepository) GetByID(id int) (*domain.User, error) {
 	return args.Get(0).(*domain.User), args.Error(1)
 }
 
-func (m *MockUserRepository) GetAll() ([]domain.User, error) {
-	args := m.Called()
+func (m *MockUserRepository) List(ctx context.Context, orgID int, params domain.UserListParams) ([]domain.User, int, error) {
+	args := m.Called(orgID, params)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]domain.User), args.Int(1), args.Error(2)
+}
+
+func (m *MockUserRepository) Update(ctx context.Context, orgID, id int, name, email, role string) (*domain.User, error) {
+	args := m.Called(orgID, id, name, email, role)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]domain.User), args.Error(1)
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+func (m *MockUserRepository) SoftDelete(ctx context.Context, orgID, id int) error {
+	args := m.Called(orgID, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdatePassword(ctx context.Context, id int, passwordHash string) error {
+	args := m.Called(id, passwordHash)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) MarkEmailVerified(ctx context.Context, id int) error {
+	args := m.Called(id)
+	return args.Error(0)
 }
 
 func TestRegister_ValidUser(t *testing.T) {
@@ -50,13 +89,15 @@ func TestRegister_ValidUser(t *testing.T) {
 
 	expectedUser := &domain.User{
 		ID:    1,
+		OrgID: 4,
 		Name:  "Test User",
 		Email: "test@example.com",
+		Role:  domain.RoleAdmin,
 	}
 
-	mockRepo.On("Create", "Test User", "test@example.com", mock.AnythingOfType("string")).Return(expectedUser, nil)
+	mockRepo.On("CreateWithOrganization", "Acme", "Test User", "test@example.com", mock.AnythingOfType("string")).Return(expectedUser, nil)
 
-	result, err := service.Register("Test User", "test@example.com", "password123")
+	result, err := service.Register(ctx, "Acme", "Test User", "test@example.com", "Sup3r-Secreta")
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -70,22 +111,22 @@ func TestRegister_EmptyFields(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	service := NewUserService(mockRepo, []byte("test-secret"))
 
-	result, err := service.Register("", "test@example.com", "password123")
+	result, err := service.Register(ctx, "Acme", "", "test@example.com", "Sup3r-Secreta")
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
 	assert.Contains(t, err.Error(), "Nome, email e senha são obrigatórios")
 
-	mockRepo.AssertNotCalled(t, "Create")
+	mockRepo.AssertNotCalled(t, "CreateWithOrganization")
 }
 
 func TestRegister_RepositoryError(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	service := NewUserService(mockRepo, []byte("test-secret"))
 
-	mockRepo.On("Create", "Test User", "test@example.com", mock.AnythingOfType("string")).Return(nil, assert.AnError)
+	mockRepo.On("CreateWithOrganization", "Test User", "Test User", "test@example.com", mock.AnythingOfType("string")).Return(nil, assert.AnError)
 
-	result, err := service.Register("Test User", "test@example.com", "password123")
+	result, err := service.Register(ctx, "", "Test User", "test@example.com", "Sup3r-Secreta")
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -107,7 +148,7 @@ func TestLogin_ValidCredentials(t *testing.T) {
 
 	mockRepo.On("GetByEmail", "test@example.com").Return(user, nil)
 
-	result, err := service.Login("test@example.com", "password")
+	result, err := service.Login(ctx, "test@example.com", "password", "203.0.113.7")
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -123,7 +164,7 @@ func TestLogin_InvalidEmail(t *testing.T) {
 
 	mockRepo.On("GetByEmail", "invalid@example.com").Return(nil, assert.AnError)
 
-	result, err := service.Login("invalid@example.com", "password")
+	result, err := service.Login(ctx, "invalid@example.com", "password", "203.0.113.7")
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -146,7 +187,7 @@ func TestLogin_InvalidPassword(t *testing.T) {
 
 	mockRepo.On("GetByEmail", "test@example.com").Return(user, nil)
 
-	result, err := service.Login("test@example.com", "wrongpassword")
+	result, err := service.Login(ctx, "test@example.com", "wrongpassword", "203.0.113.7")
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -167,7 +208,7 @@ func TestGetByID_ValidID(t *testing.T) {
 
 	mockRepo.On("GetByID", 1).Return(expectedUser, nil)
 
-	result, err := service.GetByID(1)
+	result, err := service.GetByID(ctx, 1)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -177,7 +218,7 @@ func TestGetByID_ValidID(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
-func TestGetAll_ValidUsers(t *testing.T) {
+func TestList_Paginated(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	service := NewUserService(mockRepo, []byte("test-secret"))
 
@@ -186,15 +227,225 @@ func TestGetAll_ValidUsers(t *testing.T) {
 		{ID: 2, Name: "User 2", Email: "user2@example.com"},
 	}
 
-	mockRepo.On("GetAll").Return(expectedUsers, nil)
+	params := domain.UserListParams{Page: 2, PageSize: 2, Search: "user", SortBy: "name", SortOrder: "desc"}
+	mockRepo.On("List", 1, params).Return(expectedUsers, 5, nil)
 
-	result, err := service.GetAll()
+	result, err := service.List(ctx, domain.Actor{UserID: 1, OrgID: 1, Role: domain.RoleAdmin}, domain.UserListParams{Page: 2, PageSize: 2, Search: "  user ", SortBy: "name", SortOrder: "desc"})
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
-	assert.Len(t, result, 2)
-	assert.Equal(t, expectedUsers[0].Name, result[0].Name)
-	assert.Equal(t, expectedUsers[1].Name, result[1].Name)
+	assert.Len(t, result.Users, 2)
+	assert.Equal(t, expectedUsers[0].Name, result.Users[0].Name)
+	assert.Equal(t, 5, result.Total)
+	assert.Equal(t, 3, result.TotalPages)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestList_DefaultsAndLimits(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, []byte("test-secret"))
+
+	mockRepo.On("List", 1, domain.UserListParams{Page: 1, PageSize: 100}).Return([]domain.User{}, 0, nil)
+
+	result, err := service.List(ctx, domain.Actor{UserID: 1, OrgID: 1, Role: domain.RoleAdmin}, domain.UserListParams{Page: -1, PageSize: 1000})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Page)
+	assert.Equal(t, 100, result.PageSize)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdate_SelfCanChangeName(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, []byte("test-secret"))
+
+	existing := &domain.User{ID: 2, OrgID: 1, Name: "Old", Email: "user@example.com", Role: domain.RoleUser}
+	updated := &domain.User{ID: 2, OrgID: 1, Name: "New", Email: "user@example.com", Role: domain.RoleUser}
+	mockRepo.On("GetByID", 2).Return(existing, nil)
+	mockRepo.On("Update", 1, 2, "New", "user@example.com", domain.RoleUser).Return(updated, nil)
+
+	name := "New"
+	result, err := service.Update(ctx, domain.Actor{UserID: 2, OrgID: 1, Role: domain.RoleUser}, 2, domain.UpdateUserRequest{Name: &name})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "New", result.Name)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdate_NonAdminCannotChangeOthersOrRoles(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, []byte("test-secret"))
+
+	name := "Hacker"
+	_, err := service.Update(ctx, domain.Actor{UserID: 2, Role: domain.RoleUser}, 3, domain.UpdateUserRequest{Name: &name})
+	assert.IsType(t, &ForbiddenError{}, err)
+
+	role := domain.RoleAdmin
+	_, err = service.Update(ctx, domain.Actor{UserID: 2, Role: domain.RoleUser}, 2, domain.UpdateUserRequest{Role: &role})
+	assert.IsType(t, &ForbiddenError{}, err)
+
+	mockRepo.AssertNotCalled(t, "Update")
+}
+
+func TestUpdate_UserFromAnotherOrganizationIsNotFound(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, []byte("test-secret"))
+
+	other := &domain.User{ID: 5, OrgID: 2, Name: "Other", Email: "other@example.com", Role: domain.RoleUser}
+	mockRepo.On("GetByID", 5).Return(other, nil)
+
+	name := "Renamed"
+	_, err := service.Update(ctx, domain.Actor{UserID: 1, OrgID: 1, Role: domain.RoleAdmin}, 5, domain.UpdateUserRequest{Name: &name})
+
+	assert.IsType(t, &repository.UserNotFoundError{}, err)
+	mockRepo.AssertNotCalled(t, "Update")
+}
+
+func TestCreate_UsesActorOrganization(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, []byte("test-secret"))
+
+	created := &domain.User{ID: 9, OrgID: 3, Name: "New", Email: "new@example.com", Role: domain.RoleUser}
+	mockRepo.On("Create", 3, "New", "new@example.com", mock.AnythingOfType("string")).Return(created, nil)
+
+	_, err := service.Create(ctx, domain.Actor{UserID: 2, OrgID: 3, Role: domain.RoleUser}, "New", "new@example.com", "Sup3r-Secreta")
+	assert.IsType(t, &ForbiddenError{}, err)
 
+	result, err := service.Create(ctx, domain.Actor{UserID: 1, OrgID: 3, Role: domain.RoleAdmin}, "New", "new@example.com", "Sup3r-Secreta")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.OrgID)
+
+	mockRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestDelete_AdminSoftDeletes(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, []byte("test-secret"))
+
+	mockRepo.On("SoftDelete", 1, 3).Return(nil)
+
+	err := service.Delete(ctx, domain.Actor{UserID: 1, OrgID: 1, Role: domain.RoleAdmin}, 3)
+	assert.NoError(t, err)
+
+	err = service.Delete(ctx, domain.Actor{UserID: 1, OrgID: 1, Role: domain.RoleAdmin}, 1)
+	assert.IsType(t, &ValidationError{}, err)
+
+	mockRepo.AssertNumberOfCalls(t, "SoftDelete", 1)
+}
+
+func TestChangePassword_RequiresCurrentPassword(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, []byte("test-secret"))
+
+	hashedPassword := "$2a$10$92IXUNpkjO0rOQ5byMi.Ye4oKoEa3Ro9llC/.og/at2.uheWG/igi"
+	mockRepo.On("GetByID", 1).Return(&domain.User{ID: 1, Email: "test@example.com", PasswordHash: hashedPassword}, nil)
+	mockRepo.On("UpdatePassword", 1, mock.AnythingOfType("string")).Return(nil)
+
+	err := service.ChangePassword(ctx, 1, "wrongpassword", "N0va-Senha!")
+	assert.IsType(t, &AuthenticationError{}, err)
+	mockRepo.AssertNotCalled(t, "UpdatePassword")
+
+	err = service.ChangePassword(ctx, 1, "password", "N0va-Senha!")
+	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
-} 
\ No newline at end of file
+}
+
+func TestLogin_LockedAfterRepeatedFailures(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	guard := NewLoginGuard(repository.NewMemoryLoginAttemptStore(time.Hour), DefaultLoginGuardConfig())
+	service := NewUserService(mockRepo, []byte("test-secret"), WithLoginGuard(guard))
+
+	hashedPassword := "$2a$10$92IXUNpkjO0rOQ5byMi.Ye4oKoEa3Ro9llC/.og/at2.uheWG/igi"
+	user := &domain.User{ID: 1, Email: "test@example.com", PasswordHash: hashedPassword}
+	mockRepo.On("GetByEmail", "test@example.com").Return(user, nil)
+
+	for i := 0; i < 3; i++ {
+		_, err := service.Login(ctx, "test@example.com", "wrongpassword", "203.0.113.7")
+		assert.IsType(t, &AuthenticationError{}, err)
+	}
+
+	result, err := service.Login(ctx, "test@example.com", "password", "203.0.113.7")
+
+	assert.Nil(t, result)
+	assert.IsType(t, &TooManyAttemptsError{}, err)
+	mockRepo.AssertNumberOfCalls(t, "GetByEmail", 3)
+}
+
+func TestLogin_UnknownEmailLockedLikeExistingOne(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	guard := NewLoginGuard(repository.NewMemoryLoginAttemptStore(time.Hour), DefaultLoginGuardConfig())
+	service := NewUserService(mockRepo, []byte("test-secret"), WithLoginGuard(guard))
+
+	mockRepo.On("GetByEmail", "ghost@example.com").Return(nil, &repository.UserNotFoundError{Email: "ghost@example.com"})
+
+	for i := 0; i < 3; i++ {
+		_, err := service.Login(ctx, "ghost@example.com", "password", "203.0.113.7")
+		assert.Equal(t, "Credenciais inválidas", err.Error())
+	}
+
+	_, err := service.Login(ctx, "ghost@example.com", "password", "203.0.113.7")
+
+	assert.IsType(t, &TooManyAttemptsError{}, err)
+}
+
+func TestUnlockLogin_ClearsAccountLock(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	guard := NewLoginGuard(repository.NewMemoryLoginAttemptStore(time.Hour), DefaultLoginGuardConfig())
+	service := NewUserService(mockRepo, []byte("test-secret"), WithLoginGuard(guard))
+
+	user := &domain.User{ID: 1, OrgID: 1, Email: "test@example.com"}
+	mockRepo.On("GetByID", 1).Return(user, nil)
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, guard.Check("test@example.com", ""))
+	}
+	assert.Error(t, guard.Check("test@example.com", ""))
+
+	err := service.UnlockLogin(ctx, domain.Actor{UserID: 2, OrgID: 2, Role: domain.RoleAdmin}, 1)
+	assert.IsType(t, &repository.UserNotFoundError{}, err)
+	assert.Error(t, guard.Check("test@example.com", ""))
+
+	err = service.UnlockLogin(ctx, domain.Actor{UserID: 2, OrgID: 1, Role: domain.RoleAdmin}, 1)
+
+	assert.NoError(t, err)
+	assert.NoError(t, guard.Check("test@example.com", ""))
+}
+
+func TestCheckSession_RejectsDeletedUsers(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, []byte("test-secret"))
+
+	mockRepo.On("GetByID", 1).Return(&domain.User{ID: 1}, nil)
+	mockRepo.On("GetByID", 2).Return(nil, &repository.UserNotFoundError{ID: 2})
+
+	assert.NoError(t, service.CheckSession(ctx, 1))
+	assert.IsType(t, &AuthenticationError{}, service.CheckSession(ctx, 2))
+}
+
+func TestRefreshSession_KeepsLoginTimeAndLimitsAge(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, []byte("test-secret"))
+
+	mockRepo.On("GetByID", 1).Return(&domain.User{ID: 1, OrgID: 3, Email: "test@example.com", Role: domain.RoleAdmin}, nil)
+	mockRepo.On("GetByID", 2).Return(nil, &repository.UserNotFoundError{ID: 2})
+
+	authTime := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+	resp, err := service.RefreshSession(ctx, 1, authTime)
+	require.NoError(t, err)
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(resp.Token, claims, func(*jwt.Token) (interface{}, error) { return []byte("test-secret"), nil })
+	require.NoError(t, err)
+	assert.Equal(t, float64(authTime.Unix()), claims["auth_time"])
+	assert.Equal(t, domain.RoleAdmin, claims["role"])
+
+	_, err = service.RefreshSession(ctx, 1, time.Now().Add(-31*24*time.Hour))
+	assert.IsType(t, &AuthenticationError{}, err)
+	_, err = service.RefreshSession(ctx, 1, time.Time{})
+	assert.IsType(t, &AuthenticationError{}, err)
+	_, err = service.RefreshSession(ctx, 2, time.Now())
+	assert.IsType(t, &AuthenticationError{}, err)
+}
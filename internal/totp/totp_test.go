@@ -0,0 +1,62 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Vetores SHA-1 do apêndice B da RFC 6238, truncados para 6 dígitos.
+func TestCode_RFC6238Vectors(t *testing.T) {
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+
+	for unix, expected := range vectors {
+		code, err := Code(secret, Step(time.Unix(unix, 0)))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, code, "t=%d", unix)
+	}
+}
+
+func TestValidate_AcceptsAdjacentStep(t *testing.T) {
+	secret, err := GenerateSecret()
+	assert.NoError(t, err)
+
+	now := time.Unix(1700000000, 0)
+	previous, _ := Code(secret, Step(now)-1)
+
+	step, ok := Validate(secret, previous, now, 1)
+	assert.True(t, ok)
+	assert.Equal(t, Step(now)-1, step)
+
+	_, ok = Validate(secret, previous, now, 0)
+	assert.False(t, ok)
+}
+
+func TestValidate_RejectsMalformedCode(t *testing.T) {
+	secret, _ := GenerateSecret()
+
+	for _, code := range []string{"", "12345", "1234567", "abcdef"} {
+		_, ok := Validate(secret, code, time.Now(), 1)
+		assert.False(t, ok, code)
+	}
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := ProvisioningURI("Event Go", "admin@test.com", "JBSWY3DPEHPK3PXP")
+
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/Event%20Go:admin@test.com?"))
+	assert.Contains(t, uri, "secret=JBSWY3DPEHPK3PXP")
+	assert.Contains(t, uri, "issuer=Event+Go")
+}
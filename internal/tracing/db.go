@@ -0,0 +1,112 @@
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Querier é o banco instrumentado: *sql.DB ou outro wrapper dele.
+type Querier interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// DB é o conjunto de métodos usado pelos repositórios (repository.DBInterface).
+type DB interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (Rows, error)
+}
+
+// Rows são os métodos de *sql.Rows usados pelos repositórios.
+type Rows interface {
+	Next() bool
+	Scan(dest ...interface{}) error
+	Err() error
+	Close() error
+}
+
+// InstrumentDB abre um span por comando SQL. O texto da consulta vai no span,
+// mas os argumentos não, para não exportar dados pessoais.
+func InstrumentDB(db Querier) DB {
+	return &tracedDB{db: db}
+}
+
+type tracedDB struct {
+	db Querier
+}
+
+func (d *tracedDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	ctx, span := startQuery(ctx, "query_row", query)
+	defer span.End()
+
+	row := d.db.QueryRowContext(ctx, query, args...)
+	if err := row.Err(); err != nil && err != sql.ErrNoRows {
+		RecordError(span, err)
+	}
+	return row
+}
+
+func (d *tracedDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, span := startQuery(ctx, "exec", query)
+	defer span.End()
+
+	result, err := d.db.ExecContext(ctx, query, args...)
+	RecordError(span, err)
+	return result, err
+}
+
+// QueryContext só encerra o span quando as linhas terminam de ser lidas ou são
+// fechadas, para que ele cubra também o tempo de leitura do resultado.
+func (d *tracedDB) QueryContext(ctx context.Context, query string, args ...interface{}) (Rows, error) {
+	ctx, span := startQuery(ctx, "query", query)
+
+	rows, err := d.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		RecordError(span, err)
+		span.End()
+		return nil, err
+	}
+	return &tracedRows{Rows: rows, span: span}, nil
+}
+
+type tracedRows struct {
+	*sql.Rows
+	span trace.Span
+	once sync.Once
+}
+
+func (r *tracedRows) Next() bool {
+	if r.Rows.Next() {
+		return true
+	}
+	r.end()
+	return false
+}
+
+func (r *tracedRows) Close() error {
+	err := r.Rows.Close()
+	r.end()
+	return err
+}
+
+func (r *tracedRows) end() {
+	r.once.Do(func() {
+		RecordError(r.span, r.Rows.Err())
+		r.span.End()
+	})
+}
+
+func startQuery(ctx context.Context, operation, query string) (context.Context, trace.Span) {
+	return start(ctx, "db."+operation, trace.SpanKindClient, []attribute.KeyValue{
+		semconv.DBSystemPostgreSQL,
+		semconv.DBOperationName(operation),
+		semconv.DBQueryText(query),
+	})
+}
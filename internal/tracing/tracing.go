@@ -0,0 +1,118 @@
+// Package tracing configura o OpenTelemetry: spans por requisição HTTP, método
+// de serviço e comando SQL, com propagação W3C trace-context.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/nathaliaoliveira/goapp/internal/env"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/nathaliaoliveira/goapp"
+
+// Exportadores aceitos em TRACING_EXPORTER.
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+type Config struct {
+	Exporter    string
+	ServiceName string
+	// SampleRatio é a fração de traces iniciados aqui que são amostrados;
+	// traces vindos de outro serviço seguem a decisão do pai.
+	SampleRatio float64
+}
+
+func NewConfig() *Config {
+	config := &Config{
+		Exporter:    strings.ToLower(env.String("TRACING_EXPORTER", ExporterNone)),
+		ServiceName: env.String("OTEL_SERVICE_NAME", "goapp"),
+		SampleRatio: env.Float("TRACING_SAMPLE_RATIO", 1),
+	}
+	if config.SampleRatio > 1 {
+		slog.Warn("Valor inválido em variável de ambiente, usando o padrão", "key", "TRACING_SAMPLE_RATIO", "value", config.SampleRatio, "default", 1)
+		config.SampleRatio = 1
+	}
+	return config
+}
+
+// Setup instala o TracerProvider global e o propagador W3C. O endpoint do OTLP
+// segue as variáveis padrão do OpenTelemetry (OTEL_EXPORTER_OTLP_ENDPOINT etc.).
+// A função devolvida envia os spans pendentes e deve ser chamada ao desligar.
+func Setup(ctx context.Context, config *Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	switch config.Exporter {
+	case ExporterNone, "":
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		otlp, err := otlptracehttp.New(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao criar exportador OTLP: %w", err)
+		}
+		exporter = otlp
+	case ExporterStdout:
+		stdout, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, fmt.Errorf("erro ao criar exportador stdout: %w", err)
+		}
+		exporter = stdout
+	default:
+		return nil, fmt.Errorf("exportador de tracing desconhecido: %q", config.Exporter)
+	}
+
+	provider := NewProvider(config, sdktrace.WithBatcher(exporter))
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// NewProvider cria o TracerProvider com o recurso do serviço e a amostragem da
+// configuração; opts define como os spans são exportados.
+func NewProvider(config *Config, opts ...sdktrace.TracerProviderOption) *sdktrace.TracerProvider {
+	res := resource.NewSchemaless(semconv.ServiceName(config.ServiceName))
+	opts = append([]sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+	}, opts...)
+	return sdktrace.NewTracerProvider(opts...)
+}
+
+// Start abre um span interno, filho do span presente em ctx.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return start(ctx, name, trace.SpanKindInternal, attrs)
+}
+
+// StartServer abre o span de uma requisição recebida.
+func StartServer(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return start(ctx, name, trace.SpanKindServer, attrs)
+}
+
+func start(ctx context.Context, name string, kind trace.SpanKind, attrs []attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(attrs...))
+}
+
+// RecordError marca o span como falho quando err não é nil.
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
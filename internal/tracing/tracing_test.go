@@ -0,0 +1,151 @@
+package tracing_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/nathaliaoliveira/goapp/internal/tracing"
+	"github.com/nathaliaoliveira/goapp/internal/tracing/tracingtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type failingDB struct{}
+
+func (failingDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return nil
+}
+
+func (failingDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return nil, errors.New("conexão perdida")
+}
+
+func (failingDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, errors.New("conexão perdida")
+}
+
+func TestInstrumentDB_SpanPerStatementWithoutArgs(t *testing.T) {
+	exporter := tracingtest.Install(t)
+
+	ctx, parent := tracing.Start(context.Background(), "EventService.ProcessEvents")
+	db := tracing.InstrumentDB(failingDB{})
+	_, err := db.ExecContext(ctx, "INSERT INTO email_events (email) VALUES ($1)", "joao@exemplo.com")
+	require.Error(t, err)
+	parent.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+
+	query := spans[0]
+	assert.Equal(t, "db.exec", query.Name)
+	assert.Equal(t, trace.SpanKindClient, query.SpanKind)
+	assert.Equal(t, parent.SpanContext().SpanID(), query.Parent.SpanID())
+	assert.Equal(t, codes.Error, query.Status.Code)
+	for _, attr := range query.Attributes {
+		assert.NotContains(t, attr.Value.Emit(), "joao@exemplo.com")
+	}
+}
+
+// rowsDriver é um driver SQL mínimo cujas consultas devolvem duas linhas.
+type rowsDriver struct{}
+
+func (rowsDriver) Open(name string) (driver.Conn, error) { return rowsConn{}, nil }
+
+type rowsConn struct{}
+
+func (rowsConn) Prepare(query string) (driver.Stmt, error) { return rowsStmt{}, nil }
+func (rowsConn) Close() error                              { return nil }
+func (rowsConn) Begin() (driver.Tx, error)                 { return nil, errors.New("sem transações") }
+
+type rowsStmt struct{}
+
+func (rowsStmt) Close() error                                    { return nil }
+func (rowsStmt) NumInput() int                                   { return -1 }
+func (rowsStmt) Exec(args []driver.Value) (driver.Result, error) { return driver.ResultNoRows, nil }
+func (rowsStmt) Query(args []driver.Value) (driver.Rows, error)  { return &fakeRows{remaining: 2}, nil }
+
+type fakeRows struct{ remaining int }
+
+func (r *fakeRows) Columns() []string { return []string{"id"} }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.remaining == 0 {
+		return io.EOF
+	}
+	r.remaining--
+	dest[0] = int64(r.remaining)
+	return nil
+}
+
+func init() {
+	sql.Register("tracing-rows", rowsDriver{})
+}
+
+func TestInstrumentDB_QuerySpanCoversReadingRows(t *testing.T) {
+	exporter := tracingtest.Install(t)
+	sqlDB, err := sql.Open("tracing-rows", "")
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+	db := tracing.InstrumentDB(sqlDB)
+
+	rows, err := db.QueryContext(context.Background(), "SELECT id FROM sites")
+	require.NoError(t, err)
+	assert.Empty(t, exporter.GetSpans(), "o span segue aberto enquanto as linhas são lidas")
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		require.NoError(t, rows.Scan(&id))
+		ids = append(ids, id)
+	}
+	assert.Equal(t, []int{1, 0}, ids)
+	assert.Len(t, exporter.GetSpans(), 1, "termina quando as linhas acabam")
+	require.NoError(t, rows.Close())
+	assert.Len(t, exporter.GetSpans(), 1, "Close depois da leitura não encerra de novo")
+
+	rows, err = db.QueryContext(context.Background(), "SELECT id FROM sites")
+	require.NoError(t, err)
+	require.NoError(t, rows.Close())
+	assert.Equal(t, []string{"db.query", "db.query"}, tracingtest.Names(exporter), "ou quando são fechadas antes do fim")
+}
+
+func TestStart_ContinuesW3CTraceContext(t *testing.T) {
+	tracingtest.Install(t)
+
+	header := http.Header{}
+	header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(header))
+
+	_, span := tracing.StartServer(ctx, "POST /api/events")
+	defer span.End()
+
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+}
+
+func TestSetup(t *testing.T) {
+	shutdown, err := tracing.Setup(context.Background(), &tracing.Config{Exporter: tracing.ExporterNone})
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+
+	_, err = tracing.Setup(context.Background(), &tracing.Config{Exporter: "zipkin"})
+	assert.Error(t, err)
+}
+
+func TestNewConfig_InvalidSampleRatioUsesDefault(t *testing.T) {
+	for _, value := range []string{"metade", "-0.1", "1.5"} {
+		t.Setenv("TRACING_SAMPLE_RATIO", value)
+		assert.Equal(t, 1.0, tracing.NewConfig().SampleRatio, value)
+	}
+
+	t.Setenv("TRACING_SAMPLE_RATIO", "0.1")
+	assert.Equal(t, 0.1, tracing.NewConfig().SampleRatio)
+}
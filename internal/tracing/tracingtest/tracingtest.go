@@ -0,0 +1,43 @@
+// Package tracingtest instala um TracerProvider com exportador em memória para
+// que os testes inspecionem os spans gerados pela aplicação.
+package tracingtest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nathaliaoliveira/goapp/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// Install troca o provider global por um que exporta cada span, de forma
+// síncrona, para o exportador devolvido. O provider anterior volta ao fim do teste.
+func Install(t testing.TB) *tracetest.InMemoryExporter {
+	t.Helper()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := tracing.NewProvider(&tracing.Config{ServiceName: "goapp-test", SampleRatio: 1}, sdktrace.WithSyncer(exporter))
+
+	previous, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		provider.Shutdown(context.Background())
+		otel.SetTracerProvider(previous)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+	return exporter
+}
+
+// Names devolve os nomes dos spans na ordem em que terminaram.
+func Names(exporter *tracetest.InMemoryExporter) []string {
+	spans := exporter.GetSpans()
+	names := make([]string, len(spans))
+	for i, span := range spans {
+		names[i] = span.Name
+	}
+	return names
+}
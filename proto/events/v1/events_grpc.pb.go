@@ -0,0 +1,170 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: events/v1/events.proto
+
+// API gRPC de ingestão e consulta de eventos de email. Usa a mesma
+// autenticação da API REST, enviada nos metadados da chamada:
+// "authorization: Bearer <jwt>", "authorization: ApiKey <chave>" ou
+// "x-api-key: <chave>".
+
+package eventsv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	EventService_IngestEvents_FullMethodName  = "/events.v1.EventService/IngestEvents"
+	EventService_GetDailyStats_FullMethodName = "/events.v1.EventService/GetDailyStats"
+)
+
+// EventServiceClient is the client API for EventService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type EventServiceClient interface {
+	// IngestEvents recebe eventos em fluxo e responde ao fim com o resumo de
+	// todos eles. O servidor grava em lotes enquanto recebe; se a chamada
+	// falhar, os lotes anteriores já foram gravados e reenviá-los é seguro
+	// (voltam como duplicados). Chaves de API precisam do escopo events:write.
+	IngestEvents(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[IngestEventsRequest, IngestEventsResponse], error)
+	// GetDailyStats devolve as estatísticas diárias. Chaves de API precisam do
+	// escopo stats:read.
+	GetDailyStats(ctx context.Context, in *GetDailyStatsRequest, opts ...grpc.CallOption) (*GetDailyStatsResponse, error)
+}
+
+type eventServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEventServiceClient(cc grpc.ClientConnInterface) EventServiceClient {
+	return &eventServiceClient{cc}
+}
+
+func (c *eventServiceClient) IngestEvents(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[IngestEventsRequest, IngestEventsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &EventService_ServiceDesc.Streams[0], EventService_IngestEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[IngestEventsRequest, IngestEventsResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EventService_IngestEventsClient = grpc.ClientStreamingClient[IngestEventsRequest, IngestEventsResponse]
+
+func (c *eventServiceClient) GetDailyStats(ctx context.Context, in *GetDailyStatsRequest, opts ...grpc.CallOption) (*GetDailyStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDailyStatsResponse)
+	err := c.cc.Invoke(ctx, EventService_GetDailyStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// EventServiceServer is the server API for EventService service.
+// All implementations must embed UnimplementedEventServiceServer
+// for forward compatibility.
+type EventServiceServer interface {
+	// IngestEvents recebe eventos em fluxo e responde ao fim com o resumo de
+	// todos eles. O servidor grava em lotes enquanto recebe; se a chamada
+	// falhar, os lotes anteriores já foram gravados e reenviá-los é seguro
+	// (voltam como duplicados). Chaves de API precisam do escopo events:write.
+	IngestEvents(grpc.ClientStreamingServer[IngestEventsRequest, IngestEventsResponse]) error
+	// GetDailyStats devolve as estatísticas diárias. Chaves de API precisam do
+	// escopo stats:read.
+	GetDailyStats(context.Context, *GetDailyStatsRequest) (*GetDailyStatsResponse, error)
+	mustEmbedUnimplementedEventServiceServer()
+}
+
+// UnimplementedEventServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedEventServiceServer struct{}
+
+func (UnimplementedEventServiceServer) IngestEvents(grpc.ClientStreamingServer[IngestEventsRequest, IngestEventsResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method IngestEvents not implemented")
+}
+func (UnimplementedEventServiceServer) GetDailyStats(context.Context, *GetDailyStatsRequest) (*GetDailyStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDailyStats not implemented")
+}
+func (UnimplementedEventServiceServer) mustEmbedUnimplementedEventServiceServer() {}
+func (UnimplementedEventServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeEventServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to EventServiceServer will
+// result in compilation errors.
+type UnsafeEventServiceServer interface {
+	mustEmbedUnimplementedEventServiceServer()
+}
+
+func RegisterEventServiceServer(s grpc.ServiceRegistrar, srv EventServiceServer) {
+	// If the following call pancis, it indicates UnimplementedEventServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&EventService_ServiceDesc, srv)
+}
+
+func _EventService_IngestEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(EventServiceServer).IngestEvents(&grpc.GenericServerStream[IngestEventsRequest, IngestEventsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EventService_IngestEventsServer = grpc.ClientStreamingServer[IngestEventsRequest, IngestEventsResponse]
+
+func _EventService_GetDailyStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDailyStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventServiceServer).GetDailyStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EventService_GetDailyStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventServiceServer).GetDailyStats(ctx, req.(*GetDailyStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// EventService_ServiceDesc is the grpc.ServiceDesc for EventService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var EventService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "events.v1.EventService",
+	HandlerType: (*EventServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetDailyStats",
+			Handler:    _EventService_GetDailyStats_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "IngestEvents",
+			Handler:       _EventService_IngestEvents_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "events/v1/events.proto",
+}